@@ -1,21 +1,34 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
-	"github.com/gauravdhanuka4/trade-detection-system/internal/redis"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/admin"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/metrics"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
+// labelsToRedis is the labels output value that selects the Redis label stream
+const labelsToRedis = "redis"
+
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate trade feed",
@@ -25,11 +38,25 @@ The generator creates trades from different trader profiles:
   - High-Frequency Traders (HFT): Fast, high-volume trades
   - Regular Traders: Moderate activity, typical patterns
   - Casual Traders: Low frequency, small volumes
+  - Market Makers: Paired passive buys and sells around the mid (profiles.mm_ratio)
 
 It can inject fraud patterns for testing:
   - Wash Trades: Buy/sell pairs with minimal price difference
   - Velocity Spikes: Sudden bursts of trading activity
+  - Cross-Symbol Velocity: Coordinated same-side bursts across related symbols
   - Anomalies: Unusual patterns (size, time, symbol, price)
+  - Imbalances: Runs of trades heavily skewed to one side
+  - Fragmented Wash: Many small matched buy/sell pairs inflating volume
+  - Spoofing: Layered large orders cancelled before a small opposite-side trade
+  - Pump and Dump: Accumulation, a price pump and a collapsing sell-off in a penny stock
+  - Collusion: Wash trades whose buy and sell legs run through different linked accounts
+  - Quote Stuffing: Bursts of rapid orders inside one second, nearly all cancelled
+  - Momentum Ignition: An escalating aggressive cluster, then an opposite-side unwind
+  - Front-Running: Trading just ahead of a normal trader's large order, then exiting
+  - Combo: Several patterns overlaid on one account, e.g. wash pairs inside a velocity spike
+  - Trading Ring: 3-6 linked accounts trading a penny stock around a closed loop
+  - Fat Finger: One trade with a misplaced decimal in size or price, usually reversed seconds later
+  - Insider Trading: Quiet buys ahead of a scheduled news event (news_events in the config)
 
 Examples:
   # Generate 100 trades per second for 5 minutes
@@ -38,11 +65,47 @@ Examples:
   # Generate with 10% fraud patterns
   feed-generator generate --tps 50 --fraud-rate 0.1
 
+  # Generate nothing but wash trades
+  feed-generator generate --tps 50 --fraud-only --fraud-type WASH
+
+  # Exactly 10 fraud patterns in 1000 ticks
+  feed-generator generate --tps 100 --duration 10s --fraud-every 100
+
   # Run indefinitely with verbose output
   feed-generator generate --tps 100 --duration 0 --verbose
 
   # Generate only wash trade patterns
-  feed-generator generate --tps 50 --fraud-type WASH`,
+  feed-generator generate --tps 50 --fraud-type WASH
+
+  # Generate nothing but fraud patterns (no background trades)
+  feed-generator generate --tps 50 --fraud-rate 1.0
+
+  # Make fraud trades 3x larger than the account's normal size
+  feed-generator generate --fraud-rate 0.1 --fraud-size-multiplier 3.0
+
+  # Capture the run so it can be regenerated with 'reproduce'
+  feed-generator generate --tps 50 --dump-reproduction run.json
+
+  # Write a labeled dataset to a file instead of Redis
+  feed-generator generate --duration 10m --output-file trades.ndjson
+
+  # Tune fraud rates from the statistics alone, without Redis
+  feed-generator generate --duration 1m --fraud-rate 0.2 --dry-run
+
+  # Publish to Kafka instead of Redis
+  feed-generator generate --output-backend kafka
+
+  # Feed the live Redis stream and archive the same trades to a file
+  feed-generator generate --output-backend redis,file --output-file trades.ndjson
+
+  # Record which trades were fraud for precision/recall evaluation
+  feed-generator generate --fraud-rate 0.1 --labels-output labels.ndjson
+
+  # Check a config file loads and validates, then exit (for CI)
+  feed-generator generate --config prod.yaml --validate-only
+
+  # Show the effective configuration after flags, env vars and config file
+  FEED_GEN_GENERATE_TPS=500 feed-generator generate --config prod.yaml --print-config`,
 	RunE: runGenerate,
 }
 
@@ -54,55 +117,388 @@ func init() {
 		"Trades per second (1-10000)")
 	generateCmd.Flags().DurationP("duration", "d", 5*time.Minute,
 		"Generation duration (0 = infinite)")
+	generateCmd.Flags().Int64("max-trades", 0,
+		"Stop after this many trades, finishing any fraud pattern in progress (0 = no cap)")
+	generateCmd.Flags().Int("ramp-from", 0,
+		"Ramp linearly from this TPS to --ramp-to over the run duration (0 = constant --tps)")
+	generateCmd.Flags().Int("ramp-to", 0,
+		"TPS reached at the end of a --ramp-from ramp")
+	generateCmd.Flags().String("schedule", "",
+		"Step schedule of TPS@offset entries, e.g. 100@0s,500@1m,2000@2m")
+	generateCmd.Flags().String("stream", "trades:stream",
+		"Redis stream to publish trades to")
+	generateCmd.Flags().Int64("stream-maxlen", 0,
+		"Trim the stream to about this many entries on each publish (0 = unbounded)")
+	generateCmd.Flags().String("create-group", "",
+		"Create this consumer group on the stream at startup if it does not exist")
 	generateCmd.Flags().Float64P("fraud-rate", "f", 0.05,
 		"Fraud pattern injection rate (0.0-1.0)")
+	generateCmd.Flags().Bool("fraud-only", false,
+		"Inject a fraud pattern every tick with no normal trades, failing ticks no fraud profile can serve")
+	generateCmd.Flags().Int("fraud-every", 0,
+		"Inject a fraud pattern on exactly every Nth tick instead of at --fraud-rate (0 = off)")
 	generateCmd.Flags().String("fraud-type", "ALL",
-		"Fraud types: ALL, WASH, VELOCITY, ANOMALY")
+		"Fraud types: ALL, WASH, VELOCITY, VELOCITY_MULTI, ANOMALY, IMBALANCE, FRAGMENTED_WASH, SPOOF, PUMP_DUMP, COLLUSION, QUOTE_STUFF, MOMENTUM, FRONT_RUN, COMBO, RING, FAT_FINGER")
+	generateCmd.Flags().Float64("fraud-size-multiplier", 1.0,
+		"Multiplier applied to fraud pattern trade sizes")
+	generateCmd.Flags().Float64("anomaly-price-sigmas", 10,
+		"Price anomaly deviation in multiples of the symbol's volatility")
+	generateCmd.Flags().String("anomaly-type", "",
+		"Only inject this anomaly type: size, off_hours, penny_stock, price (empty = weighted mix)")
+	generateCmd.Flags().Float64("imbalance-ratio", 0.95,
+		"Buy fraction for imbalance fraud patterns (0.0-1.0)")
+	generateCmd.Flags().Int("fragmented-wash-pairs", 10,
+		"Matched buy/sell pairs per fragmented wash pattern")
+	generateCmd.Flags().Float64("fragmented-wash-size", 500,
+		"Shares per leg of a fragmented wash pair")
+	generateCmd.Flags().Duration("insider-lead", 5*time.Minute,
+		"How long before a news event insiders start accumulating")
+	generateCmd.Flags().Float64("halt-threshold", 0,
+		"Halt a symbol after its price moves this much within the halt window (0.1 = 10%, 0 = never)")
+	generateCmd.Flags().Duration("halt-window", 5*time.Minute,
+		"Window a symbol's price move is measured over for a halt")
+	generateCmd.Flags().Duration("halt-duration", 5*time.Minute,
+		"How long a halted symbol stops trading")
+	generateCmd.Flags().Bool("halt-markers", false,
+		"Publish a marker to the trades:halts stream when a halt starts and ends")
+	generateCmd.Flags().Float64("fat-finger-correction", 0.8,
+		"Fraction of fat-finger errors reversed by a correcting trade seconds later (0.0-1.0)")
+	generateCmd.Flags().Int("ring-size", 0,
+		"Accounts in a trading ring pattern, 3-6 (0 = random)")
+	generateCmd.Flags().Duration("pump-dump-window", 30*time.Minute,
+		"Time span of a pump-and-dump pattern's accumulation, pump and dump phases")
+	generateCmd.Flags().Bool("cancel-orders", false,
+		"Publish cancelled orders around normal executions at each profile's order_to_trade ratio")
+	generateCmd.Flags().Int("quote-stuff-size", 100,
+		"Orders per quote stuffing burst, all within one second (max 1000)")
+	generateCmd.Flags().Float64("quote-stuff-cancel-ratio", 0.95,
+		"Fraction of quote stuffing orders that are cancelled")
+	generateCmd.Flags().StringSlice("combo-patterns", []string{"VELOCITY", "WASH"},
+		"Fraud types the COMBO pattern overlays on one account and symbol (at least two)")
+	generateCmd.Flags().String("price-model", "gbm",
+		"How prices move between trades: gbm (random walk), mean_reverting (pulled back toward a long-run mean) or jitter (static)")
+	generateCmd.Flags().Float64("price-reversion", 1,
+		"Speed per hour the mean_reverting price model pulls prices back toward their mean")
+	generateCmd.Flags().Float64("price-drift", 0,
+		"Expected log return per hour of every symbol's price random walk")
+	generateCmd.Flags().Float64("price-volatility", 0.05,
+		"Volatility per square-root hour of every symbol's price random walk (0 = static prices)")
+	generateCmd.Flags().Float64("price-jump-rate", 0,
+		"Expected price jumps per symbol per hour, simulating gaps and news (0 = off)")
+	generateCmd.Flags().Float64("price-jump-min", 0.05,
+		"Smallest price jump as a fraction of the price")
+	generateCmd.Flags().Float64("price-jump-max", 0.20,
+		"Largest price jump as a fraction of the price")
+	generateCmd.Flags().Float64("spread", 0,
+		"Bid/ask spread as a fraction of price: normal buys print at the ask, sells at the bid (0 = at the mid)")
+	generateCmd.Flags().Bool("market-hours", false,
+		"Only emit normal trades during the trading session; fraud patterns continue outside it")
+	generateCmd.Flags().String("volume-profile", "flat",
+		"Intraday volume curve: flat, u-shape, custom (volume_weights in config); --tps becomes the daily average")
+	generateCmd.Flags().String("session-open", "09:30",
+		"Trading session open (HH:MM in the session timezone)")
+	generateCmd.Flags().String("session-close", "16:00",
+		"Trading session close (HH:MM in the session timezone)")
+	generateCmd.Flags().String("session-timezone", "America/New_York",
+		"Trading session timezone (IANA name)")
+	generateCmd.Flags().String("timezone", "",
+		"Timezone for profile active hours (IANA name, empty = local time)")
+	generateCmd.Flags().Int64("target-stream-length", 0,
+		"Adjust TPS to hold the stream near this length (0 = fixed TPS)")
+	generateCmd.Flags().Float64("stream-depth-gain", 0.5,
+		"Proportional gain for the stream depth controller")
+	generateCmd.Flags().String("fraud-lag-group", "",
+		"Raise the fraud rate from --fraud-rate toward --fraud-rate-max as this consumer group falls behind")
+	generateCmd.Flags().Float64("fraud-rate-max", 0.5,
+		"Fraud rate reached once the consumer group lags by --fraud-lag-max entries")
+	generateCmd.Flags().Int64("fraud-lag-max", 10000,
+		"Consumer group lag at which the fraud rate reaches --fraud-rate-max")
+	generateCmd.Flags().String("amount-mode", "shares",
+		"Unit of trade amounts and profile trade sizes: shares, notional (dollars)")
+	generateCmd.Flags().Int("round-lot-size", 0,
+		"Round normal trade sizes to multiples of this many shares (0 = off)")
+	generateCmd.Flags().Float64("odd-lot-probability", 0.1,
+		"Probability a rounded normal trade is an odd lot instead (0.0-1.0)")
+	generateCmd.Flags().Float64("timestamp-skew-rate", 0,
+		"Fraction of trades with a skewed timestamp, simulating clock faults (0.0-1.0)")
+	generateCmd.Flags().Duration("timestamp-skew-range", 5*time.Second,
+		"Maximum timestamp skew into the past or future")
+	generateCmd.Flags().String("timestamp-jitter", "none",
+		"Spread normal trade timestamps within each tick: none, uniform, poisson")
+	generateCmd.Flags().Float64("user-session-trades", 0,
+		"Cluster normal trades into per-trader sessions of this many trades on average (0 = a fresh trader every trade)")
+	generateCmd.Flags().Int("synthetic-symbols", 0,
+		"Trade a generated universe of N symbols instead of the named set (0 = off)")
+	generateCmd.Flags().StringSlice("symbols", nil,
+		"Only trade these symbols, e.g. AAPL,TSLA, overriding every profile's symbols (empty = profile symbols)")
+	generateCmd.Flags().Bool("report-resources", false,
+		"Include the generator's own CPU, memory and GC usage in statistics")
+	generateCmd.Flags().Int64("seed", 0,
+		"Seed for trade content, making runs reproducible (0 = random)")
+	generateCmd.Flags().Int64("timing-seed", 0,
+		"Seed for timestamp offsets and skew, independent of trade content (0 = random)")
+	generateCmd.Flags().Bool("tag-sector", false,
+		"Publish each trade's sector and asset class (sector, asset_class stream fields)")
+	generateCmd.Flags().Bool("tag-trader-type", false,
+		"Tag each trade with the generating trader type (HFT, REGULAR, CASUAL, MM, FRAUD)")
+	generateCmd.Flags().Bool("sequence-numbers", false,
+		"Number published trades consecutively (seq field) so consumers can detect gaps")
+	generateCmd.Flags().Bool("enforce-positions", false,
+		"Track holdings per account and symbol so normal traders never sell short")
+	generateCmd.Flags().String("profiles-file", "",
+		"Load trader profiles from this YAML or JSON file instead of the built-in set")
+	generateCmd.Flags().String("output-backend", "redis",
+		"Where trades are published: redis, kafka (see the kafka config section), file, ws (WebSocket clients on --ws-addr), or a comma-separated list to fan out to several")
+	generateCmd.Flags().String("payload-format", "fields",
+		"How trades are encoded in Redis stream entries: fields, json (one data field) or protobuf (one data field, see trade.proto)")
+	generateCmd.Flags().String("ws-addr", ":9102",
+		"Address the ws output backend serves WebSocket clients on")
+	generateCmd.Flags().Int("ws-buffer", 256,
+		"Trades buffered per WebSocket client; a client further behind misses trades")
+	generateCmd.Flags().Bool("output-fail-fast", false,
+		"Stop publishing a batch at the first fan-out output that fails instead of trying them all")
+	generateCmd.Flags().StringP("output-file", "o", "",
+		"Write trades to this file instead of Redis")
+	generateCmd.Flags().Bool("dry-run", false,
+		"Generate and count trades without connecting to Redis or writing output")
+	generateCmd.Flags().String("output-format", "ndjson",
+		"Output file format: ndjson, csv")
+	generateCmd.Flags().String("labels-output", "",
+		"Write ground-truth fraud labels to this file, or 'redis' for the trades:labels stream")
+	generateCmd.Flags().Bool("negative-labels", false,
+		"Also label normal trades (fraud type NONE)")
+	generateCmd.Flags().Int("publish-retries", 3,
+		"Retry a failed publish this many times before dropping the trades (0 = no retries)")
+	generateCmd.Flags().Duration("publish-backoff", 100*time.Millisecond,
+		"Delay before the first publish retry, doubling on each further attempt")
+	generateCmd.Flags().Int("reconnect-buffer", 10000,
+		"Trades to buffer while reconnecting through a Redis outage (0 = fail publishes instead)")
+	generateCmd.Flags().Int("batch-size", 1,
+		"Publish trades in pipelined batches of this size (1 = one at a time)")
+	generateCmd.Flags().Int("workers", 1,
+		"Publisher goroutines, so publish latency doesn't hold up generation (1 = publish inline)")
+	generateCmd.Flags().Int("queue-size", 0,
+		"Batches queued for publisher workers (0 = twice the workers)")
+	generateCmd.Flags().String("queue-full", "block",
+		"When the publish queue is full: block (slow generation down) or drop (count and discard trades)")
+	generateCmd.Flags().Duration("flush-interval", 100*time.Millisecond,
+		"Maximum time a partial batch waits before being published")
+	generateCmd.Flags().String("metrics-addr", "",
+		"Serve Prometheus metrics on this address, e.g. :9100 (empty = off)")
+	generateCmd.Flags().String("admin-addr", "",
+		"Serve the admin API for live TPS and fraud rate changes on this address, e.g. :9101 (empty = off)")
+	generateCmd.Flags().Duration("stall-timeout", 0,
+		"Abort if no trade is published for this long (0 = never)")
 	generateCmd.Flags().BoolP("verbose", "v", false,
 		"Print each trade generated")
 	generateCmd.Flags().Duration("stats-interval", 10*time.Second,
 		"Statistics reporting interval")
+	generateCmd.Flags().String("stats-file", "",
+		"Write final statistics as JSON to this file")
+	generateCmd.Flags().String("stats-format", "text",
+		"Final statistics format on stdout: text, json")
+	generateCmd.Flags().String("snapshot-file", "",
+		"Save prices, positions, sequence numbers and random state to this file periodically and at exit")
+	generateCmd.Flags().Duration("snapshot-interval", time.Minute,
+		"How often to save the snapshot file")
+	generateCmd.Flags().String("resume", "",
+		"Continue a run from a snapshot file saved with --snapshot-file")
+	generateCmd.Flags().Bool("progress", false,
+		"Show elapsed/total duration and percentage on stderr, updating in place (off with --verbose or JSON logs)")
+	generateCmd.Flags().String("dump-reproduction", "",
+		"Write a reproduction bundle (config and version) to this file")
+	generateCmd.Flags().Bool("validate-only", false,
+		"Load and validate the configuration, then exit without generating")
+	generateCmd.Flags().String("print-config", "",
+		"Print the effective configuration as yaml or json, then exit without generating")
+	generateCmd.Flags().Lookup("print-config").NoOptDefVal = "yaml"
 
 	// Bind to viper
 	viper.BindPFlag("generate.tps", generateCmd.Flags().Lookup("tps"))
 	viper.BindPFlag("generate.duration", generateCmd.Flags().Lookup("duration"))
+	viper.BindPFlag("generate.max_trades", generateCmd.Flags().Lookup("max-trades"))
+	viper.BindPFlag("generate.ramp_from", generateCmd.Flags().Lookup("ramp-from"))
+	viper.BindPFlag("generate.ramp_to", generateCmd.Flags().Lookup("ramp-to"))
+	viper.BindPFlag("generate.schedule", generateCmd.Flags().Lookup("schedule"))
+	viper.BindPFlag("generate.stream", generateCmd.Flags().Lookup("stream"))
+	viper.BindPFlag("generate.stream_maxlen", generateCmd.Flags().Lookup("stream-maxlen"))
+	viper.BindPFlag("generate.create_group", generateCmd.Flags().Lookup("create-group"))
 	viper.BindPFlag("generate.fraud_rate", generateCmd.Flags().Lookup("fraud-rate"))
 	viper.BindPFlag("generate.fraud_type", generateCmd.Flags().Lookup("fraud-type"))
+	viper.BindPFlag("generate.fraud_only", generateCmd.Flags().Lookup("fraud-only"))
+	viper.BindPFlag("generate.fraud_every", generateCmd.Flags().Lookup("fraud-every"))
+	viper.BindPFlag("generate.fraud_size_multiplier", generateCmd.Flags().Lookup("fraud-size-multiplier"))
+	viper.BindPFlag("generate.anomaly_price_sigmas", generateCmd.Flags().Lookup("anomaly-price-sigmas"))
+	viper.BindPFlag("generate.anomaly_type", generateCmd.Flags().Lookup("anomaly-type"))
+	viper.BindPFlag("generate.imbalance_ratio", generateCmd.Flags().Lookup("imbalance-ratio"))
+	viper.BindPFlag("generate.fragmented_wash_pairs", generateCmd.Flags().Lookup("fragmented-wash-pairs"))
+	viper.BindPFlag("generate.fragmented_wash_size", generateCmd.Flags().Lookup("fragmented-wash-size"))
+	viper.BindPFlag("generate.halt_threshold", generateCmd.Flags().Lookup("halt-threshold"))
+	viper.BindPFlag("generate.halt_window", generateCmd.Flags().Lookup("halt-window"))
+	viper.BindPFlag("generate.halt_duration", generateCmd.Flags().Lookup("halt-duration"))
+	viper.BindPFlag("generate.halt_markers", generateCmd.Flags().Lookup("halt-markers"))
+	viper.BindPFlag("generate.ring_size", generateCmd.Flags().Lookup("ring-size"))
+	viper.BindPFlag("generate.fat_finger_correction", generateCmd.Flags().Lookup("fat-finger-correction"))
+	viper.BindPFlag("generate.pump_dump_window", generateCmd.Flags().Lookup("pump-dump-window"))
+	viper.BindPFlag("generate.insider_lead", generateCmd.Flags().Lookup("insider-lead"))
+	viper.BindPFlag("generate.cancel_orders", generateCmd.Flags().Lookup("cancel-orders"))
+	viper.BindPFlag("generate.quote_stuff_size", generateCmd.Flags().Lookup("quote-stuff-size"))
+	viper.BindPFlag("generate.quote_stuff_cancel_ratio", generateCmd.Flags().Lookup("quote-stuff-cancel-ratio"))
+	viper.BindPFlag("generate.combo_patterns", generateCmd.Flags().Lookup("combo-patterns"))
+	viper.BindPFlag("generate.price_model", generateCmd.Flags().Lookup("price-model"))
+	viper.BindPFlag("generate.price_reversion", generateCmd.Flags().Lookup("price-reversion"))
+	viper.BindPFlag("generate.price_drift", generateCmd.Flags().Lookup("price-drift"))
+	viper.BindPFlag("generate.price_volatility", generateCmd.Flags().Lookup("price-volatility"))
+	viper.BindPFlag("generate.price_jump_rate", generateCmd.Flags().Lookup("price-jump-rate"))
+	viper.BindPFlag("generate.price_jump_min", generateCmd.Flags().Lookup("price-jump-min"))
+	viper.BindPFlag("generate.price_jump_max", generateCmd.Flags().Lookup("price-jump-max"))
+	viper.BindPFlag("generate.spread", generateCmd.Flags().Lookup("spread"))
+	viper.BindPFlag("generate.market_hours", generateCmd.Flags().Lookup("market-hours"))
+	viper.BindPFlag("generate.volume_profile", generateCmd.Flags().Lookup("volume-profile"))
+	viper.BindPFlag("session.open", generateCmd.Flags().Lookup("session-open"))
+	viper.BindPFlag("session.close", generateCmd.Flags().Lookup("session-close"))
+	viper.BindPFlag("session.timezone", generateCmd.Flags().Lookup("session-timezone"))
+	viper.BindPFlag("generate.timezone", generateCmd.Flags().Lookup("timezone"))
+	viper.BindPFlag("generate.target_stream_length", generateCmd.Flags().Lookup("target-stream-length"))
+	viper.BindPFlag("generate.stream_depth_gain", generateCmd.Flags().Lookup("stream-depth-gain"))
+	viper.BindPFlag("generate.fraud_lag_group", generateCmd.Flags().Lookup("fraud-lag-group"))
+	viper.BindPFlag("generate.fraud_rate_max", generateCmd.Flags().Lookup("fraud-rate-max"))
+	viper.BindPFlag("generate.fraud_lag_max", generateCmd.Flags().Lookup("fraud-lag-max"))
+	viper.BindPFlag("generate.amount_mode", generateCmd.Flags().Lookup("amount-mode"))
+	viper.BindPFlag("generate.round_lot_size", generateCmd.Flags().Lookup("round-lot-size"))
+	viper.BindPFlag("generate.odd_lot_probability", generateCmd.Flags().Lookup("odd-lot-probability"))
+	viper.BindPFlag("generate.timestamp_skew_rate", generateCmd.Flags().Lookup("timestamp-skew-rate"))
+	viper.BindPFlag("generate.timestamp_skew_range", generateCmd.Flags().Lookup("timestamp-skew-range"))
+	viper.BindPFlag("generate.timestamp_jitter", generateCmd.Flags().Lookup("timestamp-jitter"))
+	viper.BindPFlag("generate.user_session_trades", generateCmd.Flags().Lookup("user-session-trades"))
+	viper.BindPFlag("generate.synthetic_symbols", generateCmd.Flags().Lookup("synthetic-symbols"))
+	viper.BindPFlag("generate.symbols", generateCmd.Flags().Lookup("symbols"))
+	viper.BindPFlag("generate.report_resources", generateCmd.Flags().Lookup("report-resources"))
+	viper.BindPFlag("generate.seed", generateCmd.Flags().Lookup("seed"))
+	viper.BindPFlag("generate.timing_seed", generateCmd.Flags().Lookup("timing-seed"))
+	viper.BindPFlag("generate.tag_trader_type", generateCmd.Flags().Lookup("tag-trader-type"))
+	viper.BindPFlag("generate.tag_sector", generateCmd.Flags().Lookup("tag-sector"))
+	viper.BindPFlag("generate.sequence_numbers", generateCmd.Flags().Lookup("sequence-numbers"))
+	viper.BindPFlag("generate.enforce_positions", generateCmd.Flags().Lookup("enforce-positions"))
+	viper.BindPFlag("profiles.file", generateCmd.Flags().Lookup("profiles-file"))
+	viper.BindPFlag("generate.output_backend", generateCmd.Flags().Lookup("output-backend"))
+	viper.BindPFlag("generate.output_fail_fast", generateCmd.Flags().Lookup("output-fail-fast"))
+	viper.BindPFlag("generate.payload_format", generateCmd.Flags().Lookup("payload-format"))
+	viper.BindPFlag("generate.ws_addr", generateCmd.Flags().Lookup("ws-addr"))
+	viper.BindPFlag("generate.ws_buffer", generateCmd.Flags().Lookup("ws-buffer"))
+	viper.BindPFlag("generate.output_file", generateCmd.Flags().Lookup("output-file"))
+	viper.BindPFlag("generate.dry_run", generateCmd.Flags().Lookup("dry-run"))
+	viper.BindPFlag("generate.output_format", generateCmd.Flags().Lookup("output-format"))
+	viper.BindPFlag("generate.labels_output", generateCmd.Flags().Lookup("labels-output"))
+	viper.BindPFlag("generate.negative_labels", generateCmd.Flags().Lookup("negative-labels"))
+	viper.BindPFlag("generate.publish_retries", generateCmd.Flags().Lookup("publish-retries"))
+	viper.BindPFlag("generate.publish_backoff", generateCmd.Flags().Lookup("publish-backoff"))
+	viper.BindPFlag("generate.reconnect_buffer", generateCmd.Flags().Lookup("reconnect-buffer"))
+	viper.BindPFlag("generate.batch_size", generateCmd.Flags().Lookup("batch-size"))
+	viper.BindPFlag("generate.workers", generateCmd.Flags().Lookup("workers"))
+	viper.BindPFlag("generate.queue_size", generateCmd.Flags().Lookup("queue-size"))
+	viper.BindPFlag("generate.queue_full", generateCmd.Flags().Lookup("queue-full"))
+	viper.BindPFlag("generate.flush_interval", generateCmd.Flags().Lookup("flush-interval"))
+	viper.BindPFlag("generate.metrics_addr", generateCmd.Flags().Lookup("metrics-addr"))
+	viper.BindPFlag("generate.admin_addr", generateCmd.Flags().Lookup("admin-addr"))
+	viper.BindPFlag("generate.stall_timeout", generateCmd.Flags().Lookup("stall-timeout"))
 	viper.BindPFlag("generate.verbose", generateCmd.Flags().Lookup("verbose"))
 	viper.BindPFlag("generate.stats_interval", generateCmd.Flags().Lookup("stats-interval"))
+	viper.BindPFlag("generate.stats_file", generateCmd.Flags().Lookup("stats-file"))
+	viper.BindPFlag("generate.stats_format", generateCmd.Flags().Lookup("stats-format"))
+	viper.BindPFlag("generate.progress", generateCmd.Flags().Lookup("progress"))
+	viper.BindPFlag("generate.snapshot_file", generateCmd.Flags().Lookup("snapshot-file"))
+	viper.BindPFlag("generate.snapshot_interval", generateCmd.Flags().Lookup("snapshot-interval"))
+	viper.BindPFlag("generate.resume", generateCmd.Flags().Lookup("resume"))
 }
 
 func runGenerate(cmd *cobra.Command, args []string) error {
 	// Load configuration
+	fraudEveryOverridesRate(cmd)
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Connect to Redis
-	redisConfig := models.RedisConfig{
-		Host:     cfg.Redis.Host,
-		Port:     cfg.Redis.Port,
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
+	// Show the merged result of flags, env vars, config file and defaults
+	if format, _ := cmd.Flags().GetString("print-config"); format != "" {
+		return printConfig(os.Stdout, cfg, format)
 	}
 
-	redisClient, err := redis.NewRedisClient(redisConfig)
+	// A line redrawn in place would clobber per-trade or structured output
+	if cfg.Generate.Progress && !progressAllowed() {
+		cfg.Generate.Progress = false
+	}
+
+	traderProfiles, err := loadProfiles(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to connect to Redis: %w", err)
+		return err
 	}
-	defer redisClient.Close()
 
-	// Test Redis connection
-	ctx := context.Background()
-	if err := redisClient.Ping(ctx); err != nil {
-		return fmt.Errorf("failed to ping Redis: %w", err)
+	// Check the resolved configuration end to end without touching Redis
+	if validateOnly, _ := cmd.Flags().GetBool("validate-only"); validateOnly {
+		if err := generator.NewGenerator(cfg, traderProfiles, nil, nil).Validate(); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		fmt.Printf("✅ Configuration OK\n")
+		return nil
+	}
+
+	// Capture the resolved configuration for later reproduction. An unseeded
+	// run gets a seed here so the bundle reproduces the exact trades.
+	if path, _ := cmd.Flags().GetString("dump-reproduction"); path != "" {
+		if cfg.Generate.Seed == 0 {
+			cfg.Generate.Seed = rand.Int63()
+		}
+		if err := config.WriteBundle(path, rootCmd.Version, cfg); err != nil {
+			return err
+		}
+		slog.Info("reproduction bundle written", "path", path,
+			logging.Text("📦 Reproduction bundle written to %s", path))
+	}
+
+	return runGenerator(cfg, traderProfiles)
+}
+
+// progressAllowed reports whether the in-place progress line can be shown
+// without mixing into the logs: not with JSON records or debug-level logging
+func progressAllowed() bool {
+	if strings.EqualFold(viper.GetString("log.format"), "json") {
+		return false
 	}
+	return !slog.Default().Enabled(context.Background(), slog.LevelDebug)
+}
 
-	fmt.Printf("✅ Connected to Redis at %s\n", cfg.RedisAddress())
+// runGenerator opens the output and runs the generator until completion or shutdown
+func runGenerator(cfg *config.Config, traderProfiles []profiles.TraderProfile) error {
+	out, err := openSink(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeSink(out)
+
+	// Create the consumer group downstream workers read the stream through
+	if group := cfg.Generate.CreateGroup; group != "" {
+		if creator, ok := out.(sink.ConsumerGroupCreator); ok {
+			if err := creator.EnsureConsumerGroup(context.Background(), cfg.Generate.Stream, group); err != nil {
+				return fmt.Errorf("failed to create consumer group %s: %w", group, err)
+			}
+			slog.Info("consumer group ready", "stream", cfg.Generate.Stream, "group", group,
+				logging.Text("✅ Consumer group %s ready on %s", group, cfg.Generate.Stream))
+		}
+	}
+
+	labels, err := openLabelSink(cfg, out)
+	if err != nil {
+		return err
+	}
+	// Labels sent to Redis alongside Redis output share the trade connection
+	if labels != nil && any(labels) != any(out) {
+		defer closeSink(labels)
+	}
 
 	// Create generator
-	gen := generator.NewGenerator(cfg, redisClient)
+	gen := generator.NewGenerator(cfg, traderProfiles, out, labels)
 
 	// Handle graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -113,10 +509,46 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	go func() {
 		<-sigChan
-		fmt.Printf("\n\n⚠️  Shutdown signal received, stopping generator...\n")
+		slog.Warn("shutdown signal received, stopping generator",
+			logging.Text("\n\n⚠️  Shutdown signal received, stopping generator..."))
 		cancel()
 	}()
 
+	// Expose live statistics to Prometheus
+	if addr := cfg.Generate.MetricsAddr; addr != "" {
+		stopped, err := metrics.Start(ctx, addr, gen.Stats())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			cancel()
+			<-stopped
+		}()
+		slog.Info("serving metrics", "addr", addr,
+			logging.Text("📈 Serving metrics at http://%s/metrics", addr))
+	}
+
+	// Accept live TPS and fraud rate changes
+	if addr := cfg.Generate.AdminAddr; addr != "" {
+		stopped, err := admin.Start(ctx, addr, gen)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			cancel()
+			<-stopped
+		}()
+		slog.Info("serving admin API", "addr", addr,
+			logging.Text("🎚️  Serving admin API at http://%s", addr))
+	}
+
+	// Profile the run itself, not sink and server setup
+	stopProfiling, err := startProfiling()
+	if err != nil {
+		return err
+	}
+	defer stopProfiling()
+
 	// Run generator
 	if err := gen.Run(ctx); err != nil {
 		return fmt.Errorf("generator error: %w", err)
@@ -124,3 +556,224 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// loadProfiles returns the trader profiles from the configured file, or the
+// built-in profiles when none is set
+func loadProfiles(cfg *config.Config) ([]profiles.TraderProfile, error) {
+	if cfg.Profiles.File == "" {
+		return profiles.GetDefaultProfiles(), nil
+	}
+
+	traderProfiles, err := profiles.LoadProfiles(cfg.Profiles.File)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("profiles loaded", "count", len(traderProfiles), "path", cfg.Profiles.File,
+		logging.Text("✅ Loaded %d profiles from %s", len(traderProfiles), cfg.Profiles.File))
+	return traderProfiles, nil
+}
+
+// openSink creates the configured output: nothing for a dry run, otherwise
+// the output backends, fanned out to when there are several
+func openSink(cfg *config.Config) (sink.Sink, error) {
+	if cfg.Generate.DryRun {
+		return sink.Discard{}, nil
+	}
+	return connectBackends(cfg, cfg.OutputBackends())
+}
+
+// connectBackends opens each of the named backends, behind a fan-out sink
+// when there are several
+func connectBackends(cfg *config.Config, backends []string) (sink.Sink, error) {
+	var sinks []sink.Sink
+	for _, backend := range backends {
+		out, err := connectBackend(cfg, backend)
+		if err != nil {
+			for _, opened := range sinks {
+				closeSink(opened)
+			}
+			return nil, err
+		}
+		sinks = append(sinks, out)
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return sink.NewMultiSink(cfg.Generate.OutputFailFast, sinks...), nil
+}
+
+// connectBackend opens one output backend: Redis, Kafka, the output file or
+// the WebSocket server
+func connectBackend(cfg *config.Config, backend string) (sink.Sink, error) {
+	switch backend {
+	case "kafka":
+		return connectKafka(cfg)
+	case "ws":
+		wsSink, err := sink.NewWSSink(cfg.Generate.WSAddr, cfg.Generate.WSBuffer)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("serving trades over websocket", "addr", wsSink.Addr().String(),
+			logging.Text("✅ Serving trades to WebSocket clients on %s", wsSink.Addr()))
+		return wsSink, nil
+	case "file":
+		fileSink, err := sink.NewFileSink(cfg.Generate.OutputFile, cfg.Generate.OutputFormat)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("writing trades to file", "path", cfg.Generate.OutputFile,
+			logging.Text("✅ Writing trades to %s", cfg.Generate.OutputFile))
+		return fileSink, nil
+	}
+
+	redisSink, err := connectRedis(cfg)
+	if err != nil {
+		return nil, err
+	}
+	redisSink.SetPayloadFormat(cfg.Generate.PayloadFormat)
+	if n := cfg.Generate.ReconnectBuffer; n > 0 {
+		redisSink.EnableReconnect(n)
+	}
+	return redisSink, nil
+}
+
+// redisOutput returns the Redis sink among the outputs, if there is one
+func redisOutput(out sink.Sink) (*sink.RedisSink, bool) {
+	if multi, ok := out.(*sink.MultiSink); ok {
+		for _, s := range multi.Sinks() {
+			if redisSink, ok := s.(*sink.RedisSink); ok {
+				return redisSink, true
+			}
+		}
+	}
+	redisSink, ok := out.(*sink.RedisSink)
+	return redisSink, ok
+}
+
+// openLabelSink creates the configured ground-truth labels output, or returns
+// nil when labels are disabled
+func openLabelSink(cfg *config.Config, out sink.Sink) (sink.LabelSink, error) {
+	switch cfg.Generate.LabelsOutput {
+	case "":
+		return nil, nil
+	case labelsToRedis:
+		if cfg.Generate.DryRun {
+			return sink.Discard{}, nil
+		}
+		if redisSink, ok := redisOutput(out); ok {
+			return redisSink, nil
+		}
+		return connectRedis(cfg)
+	default:
+		return sink.NewLabelFile(cfg.Generate.LabelsOutput)
+	}
+}
+
+// connectRedis creates a Redis sink and verifies the connection
+func connectRedis(cfg *config.Config) (*sink.RedisSink, error) {
+	redisSink, err := sink.NewRedisSink(cfg.Redis, cfg.Generate.Stream, cfg.Generate.StreamMaxLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	// Test Redis connection
+	if err := redisSink.Ping(context.Background()); err != nil {
+		redisSink.Close()
+		return nil, fmt.Errorf("failed to ping Redis: %w", err)
+	}
+
+	slog.Info("connected to Redis", "addr", cfg.RedisAddress(),
+		logging.Text("✅ Connected to Redis at %s", cfg.RedisAddress()))
+	return redisSink, nil
+}
+
+// connectKafka creates a Kafka sink and verifies the brokers and topic
+func connectKafka(cfg *config.Config) (*sink.KafkaSink, error) {
+	kafkaSink, err := sink.NewKafkaSink(cfg.Kafka)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	if err := kafkaSink.Ping(context.Background()); err != nil {
+		kafkaSink.Close()
+		return nil, fmt.Errorf("failed to reach Kafka: %w", err)
+	}
+
+	slog.Info("connected to Kafka", "addr", cfg.KafkaAddress(), "topic", cfg.Kafka.Topic,
+		logging.Text("✅ Connected to Kafka at %s", cfg.KafkaAddress()))
+	return kafkaSink, nil
+}
+
+// printConfig writes the resolved configuration in the given format (yaml or
+// json), with field names as in the Config struct and durations in
+// nanoseconds, as in reproduction bundles. The Redis password is masked.
+func printConfig(w io.Writer, cfg *config.Config, format string) error {
+	printed := *cfg
+	if printed.Redis.Password != "" {
+		printed.Redis.Password = "********"
+	}
+
+	data, err := json.MarshalIndent(printed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		data = append(data, '\n')
+	case "yaml":
+		// Round-trip through JSON so both formats share field names and values
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var fields map[string]any
+		if err := decoder.Decode(&fields); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		if data, err = yaml.Marshal(yamlNumbers(fields)); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+	default:
+		return fmt.Errorf("print config format must be yaml or json, got %q", format)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// yamlNumbers replaces the JSON numbers in a decoded value with integers or
+// floats, so durations and counts print as written rather than in exponent form
+func yamlNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			v[key] = yamlNumbers(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = yamlNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return value
+}
+
+// fraudEveryOverridesRate lets --fraud-every on the command line replace a
+// fraud rate from the config file or environment. Only a --fraud-rate flag
+// given alongside it conflicts.
+func fraudEveryOverridesRate(cmd *cobra.Command) {
+	if cmd.Flags().Changed("fraud-every") && !cmd.Flags().Changed("fraud-rate") {
+		viper.Set("generate.fraud_rate", 0)
+	}
+}
+
+// closeSink closes an output, warning rather than failing the run on error
+func closeSink(s interface{ Close() error }) {
+	if err := s.Close(); err != nil {
+		slog.Warn("closing output failed", "error", err, logging.Text("⚠️  Warning: %v", err))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestPrintConfigReflectsEnvOverride(t *testing.T) {
+	t.Setenv("FEED_GEN_GENERATE_TPS", "500")
+	t.Setenv("FEED_GEN_REDIS_HOST", "redis.internal")
+	initConfig()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := printConfig(&buf, cfg, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var printed config.Config
+	if err := json.Unmarshal(buf.Bytes(), &printed); err != nil {
+		t.Fatalf("printed config is not JSON: %v\n%s", err, buf.String())
+	}
+	if printed.Generate.TPS != 500 {
+		t.Errorf("printed tps %d, want 500 from FEED_GEN_GENERATE_TPS", printed.Generate.TPS)
+	}
+	if printed.Redis.Host != "redis.internal" {
+		t.Errorf("printed redis host %q, want redis.internal from FEED_GEN_REDIS_HOST", printed.Redis.Host)
+	}
+}
+
+func TestFraudEveryFlagOverridesConfigFraudRate(t *testing.T) {
+	// A nil override falls back to the flag, config file and default again
+	resetFraudRate := func() { viper.Set("generate.fraud_rate", nil) }
+	t.Cleanup(func() {
+		cfgFile = ""
+		resetFraudRate()
+	})
+	rootCmd.SetOut(io.Discard)
+	rootCmd.SetErr(io.Discard)
+
+	// The shipped config sets fraud_rate, which --fraud-every replaces
+	rootCmd.SetArgs([]string{"generate", "--config", "../configs/default.yaml", "--fraud-every", "10", "--validate-only"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("--fraud-every with a config file fraud rate: %v", err)
+	}
+
+	// Both on the command line still conflict
+	resetFraudRate()
+	rootCmd.SetArgs([]string{"generate", "--config", "../configs/default.yaml", "--fraud-every", "10", "--fraud-rate", "0.1", "--validate-only"})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("got %v, want --fraud-every and --fraud-rate rejected together", err)
+	}
+}
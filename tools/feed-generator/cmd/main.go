@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	_ "time/tzdata" // Session timezones resolve even on images without a zoneinfo database
 )
 
 func main() {
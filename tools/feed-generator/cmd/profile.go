@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+)
+
+// Profile outputs set by --cpuprofile and --memprofile
+var cpuProfile, memProfile string
+
+// startProfiling starts the CPU profile if one was requested. The returned
+// stop function ends it and writes the heap profile; run it on every exit
+// path, including signal-triggered shutdown, so both files are complete.
+func startProfiling() (stop func(), err error) {
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		cpuFile, err = os.Create(cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(cpuFile); err != nil {
+			cpuFile.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+	}
+
+	return func() {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			closeSink(cpuFile)
+			slog.Info("CPU profile written", "path", cpuProfile,
+				logging.Text("🔬 CPU profile written to %s", cpuProfile))
+		}
+		if memProfile != "" {
+			if err := writeHeapProfile(memProfile); err != nil {
+				slog.Warn("writing heap profile failed", "error", err, logging.Text("⚠️  Warning: %v", err))
+				return
+			}
+			slog.Info("heap profile written", "path", memProfile,
+				logging.Text("🔬 Heap profile written to %s", memProfile))
+		}
+	}, nil
+}
+
+// writeHeapProfile writes a heap profile reflecting the latest collection
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	defer f.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+)
+
+func TestProfilesWrittenAfterShortRun(t *testing.T) {
+	dir := t.TempDir()
+	cpuProfile = filepath.Join(dir, "cpu.prof")
+	memProfile = filepath.Join(dir, "mem.prof")
+	t.Cleanup(func() { cpuProfile, memProfile = "", "" })
+
+	stop, err := startProfiling()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen, err := generator.New(generator.Options{TPS: 1000, Seed: 1})
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	if _, err := gen.GenerateN(context.Background(), 5000); err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	stop()
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() == 0 {
+			t.Errorf("%s is empty", filepath.Base(path))
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+	"github.com/spf13/cobra"
+)
+
+// maxReplayLine bounds a single NDJSON record
+const maxReplayLine = 1024 * 1024
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <trades.ndjson>",
+	Short: "Re-publish a recorded NDJSON feed",
+	Long: `Re-publish trades recorded with 'generate --output-file' (NDJSON) to the
+configured output backend, in file order and unchanged, so detection can be
+re-run on exactly the same feed without regenerating it.
+
+By default trades are published as fast as the backend accepts them. With
+--preserve-timing the gaps between trade timestamps are reproduced, scaled
+by --speed.
+
+Examples:
+  # Record a feed, then replay it into Redis as fast as possible
+  feed-generator generate --duration 10m --output-file trades.ndjson
+  feed-generator replay trades.ndjson
+
+  # Replay with the original pacing, ten times faster
+  feed-generator replay trades.ndjson --preserve-timing --speed 10`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().Bool("preserve-timing", false,
+		"Reproduce the gaps between trade timestamps instead of publishing as fast as possible")
+	replayCmd.Flags().Float64("speed", 1.0,
+		"Timing multiplier with --preserve-timing (2 = twice as fast, 0.5 = half speed)")
+	replayCmd.Flags().Int("batch-size", 100,
+		"Trades per pipelined publish when not preserving timing")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	preserveTiming, _ := cmd.Flags().GetBool("preserve-timing")
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	if speed <= 0 {
+		return fmt.Errorf("speed must be positive, got %.2f", speed)
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, got %d", batchSize)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open feed: %w", err)
+	}
+	defer file.Close()
+
+	// Never write the replay back to the output file, which may be the one being read
+	backends := slices.DeleteFunc(strings.Split(cfg.Generate.OutputBackend, ","), func(b string) bool { return b == "file" })
+	if len(backends) == 0 {
+		return fmt.Errorf("replay needs a redis or kafka output backend, got %q", cfg.Generate.OutputBackend)
+	}
+	out, err := connectBackends(cfg, backends)
+	if err != nil {
+		return err
+	}
+	defer closeSink(out)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	count, err := replayTrades(ctx, file, out, preserveTiming, speed, batchSize)
+	if err != nil {
+		return fmt.Errorf("replay stopped after %d trades: %w", count, err)
+	}
+
+	fmt.Printf("✅ Replayed %d trades from %s in %v\n", count, args[0], time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// replayTrades publishes the NDJSON trades read from r in order and returns
+// how many were published. With preserveTiming each trade waits until its
+// offset from the first trade, divided by speed, has elapsed, and is published
+// on its own so batching can't hold it back.
+func replayTrades(ctx context.Context, r io.Reader, out sink.Sink, preserveTiming bool, speed float64, batchSize int) (int, error) {
+	if preserveTiming {
+		batchSize = 1
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxReplayLine)
+
+	published := 0
+	var batch []*feed.Trade
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := out.PublishBatch(ctx, batch); err != nil {
+			return err
+		}
+		published += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	var first time.Time
+	start := time.Now()
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var trade feed.Trade
+		if err := json.Unmarshal(scanner.Bytes(), &trade); err != nil {
+			return published, fmt.Errorf("line %d: %w", line, err)
+		}
+		if trade.Trade == nil {
+			return published, fmt.Errorf("line %d: not a trade record", line)
+		}
+
+		if preserveTiming {
+			if first.IsZero() {
+				first = trade.Timestamp
+			}
+			// Out-of-order (skewed) timestamps publish immediately rather than waiting
+			offset := time.Duration(float64(trade.Timestamp.Sub(first)) / speed)
+			if wait := time.Until(start.Add(offset)); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return published, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		batch = append(batch, &trade)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return published, err
+			}
+		}
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return published, fmt.Errorf("failed to read feed: %w", err)
+	}
+
+	return published, flush()
+}
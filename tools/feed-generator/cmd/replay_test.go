@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+	"github.com/google/uuid"
+)
+
+// arrivalSink records each trade with the time it was published
+type arrivalSink struct {
+	sink.Discard
+	trades   []*feed.Trade
+	arrivals []time.Time
+}
+
+func (s *arrivalSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	for _, trade := range trades {
+		s.trades = append(s.trades, trade)
+		s.arrivals = append(s.arrivals, time.Now())
+	}
+	return nil
+}
+
+func TestReplayReproducesCapturedFeed(t *testing.T) {
+	// Capture a feed with uneven gaps through the NDJSON sink
+	path := filepath.Join(t.TempDir(), "trades.ndjson")
+	capture, err := sink.NewNDJSONSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gaps := []time.Duration{0, 100 * time.Millisecond, 300 * time.Millisecond, 200 * time.Millisecond}
+	timestamp := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	var captured []*feed.Trade
+	for i, gap := range gaps {
+		timestamp = timestamp.Add(gap)
+		trade := &feed.Trade{Trade: &models.Trade{
+			ID:        uuid.New(),
+			UserID:    "USER_001",
+			Symbol:    []string{"AAPL", "MSFT"}[i%2],
+			Amount:    float64(100 * (i + 1)),
+			Price:     175.5,
+			Type:      models.TradeTypeBuy,
+			Timestamp: timestamp,
+		}}
+		captured = append(captured, trade)
+	}
+	if err := capture.PublishBatch(context.Background(), captured); err != nil {
+		t.Fatal(err)
+	}
+	if err := capture.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	out := &arrivalSink{}
+	count, err := replayTrades(context.Background(), file, out, true, 2, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if count != len(captured) || len(out.trades) != len(captured) {
+		t.Fatalf("replayed %d trades and published %d, want %d", count, len(out.trades), len(captured))
+	}
+	for i, want := range captured {
+		got := out.trades[i]
+		if got.ID != want.ID || got.Symbol != want.Symbol || got.Amount != want.Amount || !got.Timestamp.Equal(want.Timestamp) {
+			t.Errorf("trade %d: got %+v, want %+v", i, *got.Trade, *want.Trade)
+		}
+		if i == 0 {
+			continue
+		}
+		// At double speed each original gap is halved
+		gap := out.arrivals[i].Sub(out.arrivals[i-1])
+		if want := gaps[i] / 2; gap < want-10*time.Millisecond || gap > want+40*time.Millisecond {
+			t.Errorf("gap before trade %d: got %v, want about %v", i, gap, want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var reproduceCmd = &cobra.Command{
+	Use:   "reproduce <bundle>",
+	Short: "Regenerate a feed from a reproduction bundle",
+	Long: `Regenerate a trade feed from a bundle written by
+'generate --dump-reproduction'.
+
+The bundle's generation and profile settings are used as-is. Redis
+connection settings come from the current flags, environment and config
+file, so a feed captured elsewhere can be replayed against a local Redis.
+
+Examples:
+  # Capture a run
+  feed-generator generate --tps 50 --fraud-rate 0.2 --dump-reproduction run.json
+
+  # Regenerate it
+  feed-generator reproduce run.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReproduce,
+}
+
+func init() {
+	rootCmd.AddCommand(reproduceCmd)
+}
+
+func runReproduce(cmd *cobra.Command, args []string) error {
+	bundle, err := config.LoadBundle(args[0])
+	if err != nil {
+		return err
+	}
+
+	if bundle.Version != rootCmd.Version {
+		fmt.Printf("⚠️  Warning: bundle was written by version %s, running %s\n",
+			bundle.Version, rootCmd.Version)
+	}
+
+	// Take connection settings from the current environment
+	current, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cfg := bundle.Config
+	cfg.Redis = current.Redis
+
+	fmt.Printf("Reproducing feed from %s (captured %s)\n",
+		args[0], bundle.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+
+	traderProfiles, err := loadProfiles(&cfg)
+	if err != nil {
+		return err
+	}
+
+	return runGenerator(&cfg, traderProfiles)
+}
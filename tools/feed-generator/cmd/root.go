@@ -2,7 +2,11 @@ package main
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
 
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -24,6 +28,9 @@ The generator simulates three types of traders:
 It can inject various fraud patterns including wash trades,
 velocity spikes, and anomalies for testing detection algorithms.`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging()
+	},
 }
 
 // Execute runs the root command
@@ -43,11 +50,37 @@ func init() {
 		"Redis port")
 	rootCmd.PersistentFlags().String("redis-password", "",
 		"Redis password")
+	rootCmd.PersistentFlags().String("log-format", "text",
+		"Log format: text (human-readable console lines), json (one structured record per line)")
+	rootCmd.PersistentFlags().String("log-level", "info",
+		"Minimum log level: debug, info, warn, error (debug includes every published trade)")
+	rootCmd.PersistentFlags().StringVar(&cpuProfile, "cpuprofile", "",
+		"Write a pprof CPU profile of the generation run to this file")
+	rootCmd.PersistentFlags().StringVar(&memProfile, "memprofile", "",
+		"Write a pprof heap profile to this file when the generation run ends")
 
 	// Bind flags to viper
 	viper.BindPFlag("redis.host", rootCmd.PersistentFlags().Lookup("redis-host"))
 	viper.BindPFlag("redis.port", rootCmd.PersistentFlags().Lookup("redis-port"))
 	viper.BindPFlag("redis.password", rootCmd.PersistentFlags().Lookup("redis-password"))
+	viper.BindPFlag("log.format", rootCmd.PersistentFlags().Lookup("log-format"))
+	viper.BindPFlag("log.level", rootCmd.PersistentFlags().Lookup("log-level"))
+}
+
+// setupLogging installs the default logger on stdout. --verbose is shorthand
+// for debug level.
+func setupLogging() error {
+	level := viper.GetString("log.level")
+	if viper.GetBool("generate.verbose") {
+		level = "debug"
+	}
+
+	logger, err := logging.New(os.Stdout, viper.GetString("log.format"), level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
 }
 
 func initConfig() {
@@ -62,12 +95,13 @@ func initConfig() {
 		viper.SetConfigType("yaml")
 	}
 
-	// Environment variables
+	// Environment variables; nested keys use underscores (FEED_GEN_GENERATE_TPS)
 	viper.SetEnvPrefix("FEED_GEN")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	// Read config file
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
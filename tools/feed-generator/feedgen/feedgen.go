@@ -0,0 +1,49 @@
+// Package feedgen exposes the feed generator as a library, so detection
+// system tests can drive it in-process instead of shelling out to the CLI.
+// The generator lives in internal packages; this package re-exports the types
+// an embedding program needs.
+package feedgen
+
+import (
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+)
+
+type (
+	Generator     = generator.Generator
+	Options       = generator.Options
+	Config        = config.Config
+	TraderProfile = profiles.TraderProfile
+	Sink          = sink.Sink
+	LabelSink     = sink.LabelSink
+	Trade         = feed.Trade
+	Label         = feed.Label
+	Clock         = clock.Clock
+	FakeClock     = clock.Fake
+)
+
+// New creates a generator from plain options; see generator.Options
+func New(opts Options) (*Generator, error) {
+	return generator.New(opts)
+}
+
+// DefaultConfig returns the built-in configuration, for use as Options.Config
+func DefaultConfig() *Config {
+	return config.Default()
+}
+
+// DefaultProfiles returns the built-in trader profiles
+func DefaultProfiles() []TraderProfile {
+	return profiles.GetDefaultProfiles()
+}
+
+// NewFakeClock creates a clock stopped at t, for deterministic timestamps
+func NewFakeClock(t time.Time) *FakeClock {
+	return clock.NewFake(t)
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+)
+
+// shutdownTimeout bounds how long in-flight requests may delay shutdown
+const shutdownTimeout = 5 * time.Second
+
+// settings is the body of the TPS and fraud rate endpoints. Requests set
+// one field; responses report both.
+type settings struct {
+	TPS       *int     `json:"tps,omitempty"`
+	FraudRate *float64 `json:"fraud_rate,omitempty"`
+}
+
+// Handler serves the admin API over a running generator:
+//
+//	POST /tps         {"tps": 500}
+//	POST /fraud-rate  {"fraud_rate": 0.1}
+//	GET  /stats       current statistics as JSON
+func Handler(gen *generator.Generator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /tps", func(w http.ResponseWriter, r *http.Request) {
+		var req settings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TPS == nil {
+			http.Error(w, `expected a JSON body like {"tps": 500}`, http.StatusBadRequest)
+			return
+		}
+		if err := gen.SetTPS(*req.TPS); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Info("admin set tps", "tps", *req.TPS,
+			logging.Text("🎚️  Admin: throughput set to %d trades/sec", *req.TPS))
+		writeSettings(w, gen)
+	})
+	mux.HandleFunc("POST /fraud-rate", func(w http.ResponseWriter, r *http.Request) {
+		var req settings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.FraudRate == nil {
+			http.Error(w, `expected a JSON body like {"fraud_rate": 0.1}`, http.StatusBadRequest)
+			return
+		}
+		if err := gen.SetFraudRate(*req.FraudRate); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Info("admin set fraud rate", "fraud_rate", *req.FraudRate,
+			logging.Text("🎚️  Admin: fraud rate set to %.1f%%", *req.FraudRate*100))
+		writeSettings(w, gen)
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, gen.Stats().Snapshot())
+	})
+	return mux
+}
+
+// writeSettings responds with the generator's current live settings
+func writeSettings(w http.ResponseWriter, gen *generator.Generator) {
+	tps, fraudRate := gen.TPS(), gen.FraudRate()
+	writeJSON(w, settings{TPS: &tps, FraudRate: &fraudRate})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("writing admin response failed", "error", err,
+			logging.Text("Error writing admin response: %v", err))
+	}
+}
+
+// Start serves the admin API on addr until ctx is cancelled. The returned
+// channel is closed once the server has shut down.
+func Start(ctx context.Context, addr string, gen *generator.Generator) (<-chan struct{}, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	server := &http.Server{Handler: Handler(gen)}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("serving admin API failed", "error", err,
+				logging.Text("Error serving admin API: %v", err))
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("shutting down admin API failed", "error", err,
+				logging.Text("Error shutting down admin API: %v", err))
+		}
+	}()
+
+	return done, nil
+}
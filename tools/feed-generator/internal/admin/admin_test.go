@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+)
+
+// testStart is a Monday afternoon, inside every built-in profile's active hours
+var testStart = time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+
+// post sends a JSON body to the admin API and returns the status code
+func post(t *testing.T, server *httptest.Server, path, body string) int {
+	t.Helper()
+	resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestPostTPSChangesGenerationRate(t *testing.T) {
+	fake := clock.NewFake(testStart)
+	gen, err := generator.New(generator.Options{Clock: fake, TPS: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(Handler(gen))
+	defer server.Close()
+
+	// Simulated time taken to generate n trades at the current rate
+	elapsed := func(n int) time.Duration {
+		before := fake.Now()
+		if _, err := gen.GenerateN(context.Background(), n); err != nil {
+			t.Fatal(err)
+		}
+		return fake.Now().Sub(before)
+	}
+
+	if got := elapsed(100); got != 10*time.Second {
+		t.Fatalf("100 trades at 10 tps took %v, want 10s", got)
+	}
+	if status := post(t, server, "/tps", `{"tps": 50}`); status != http.StatusOK {
+		t.Fatalf("got status %d, want %d", status, http.StatusOK)
+	}
+	if got := elapsed(100); got != 2*time.Second {
+		t.Errorf("100 trades after posting 50 tps took %v, want 2s", got)
+	}
+
+	if status := post(t, server, "/tps", `{"tps": 0}`); status != http.StatusBadRequest {
+		t.Errorf("tps 0: got status %d, want %d", status, http.StatusBadRequest)
+	}
+	if gen.TPS() != 50 {
+		t.Errorf("rejected tps changed the rate to %d", gen.TPS())
+	}
+}
+
+func TestSteeredSettingsRejectChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *config.Config)
+		path      string
+		body      string
+	}{
+		{"schedule", func(cfg *config.Config) { cfg.Generate.Schedule = "100@10s" }, "/tps", `{"tps": 50}`},
+		{"stream depth", func(cfg *config.Config) { cfg.Generate.TargetStreamLength = 1000 }, "/tps", `{"tps": 50}`},
+		{"lag", func(cfg *config.Config) {
+			cfg.Generate.FraudLagGroup = "detectors"
+			cfg.Generate.FraudRateMax = 0.5
+		}, "/fraud-rate", `{"fraud_rate": 0.2}`},
+	}
+
+	for _, tt := range tests {
+		cfg := config.Default()
+		tt.configure(cfg)
+		gen, err := generator.New(generator.Options{Config: cfg, Clock: clock.NewFake(testStart), TPS: 10, FraudRate: 0.1, Seed: 1})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		server := httptest.NewServer(Handler(gen))
+
+		if status := post(t, server, tt.path, tt.body); status != http.StatusBadRequest {
+			t.Errorf("%s: POST %s got status %d, want %d", tt.name, tt.path, status, http.StatusBadRequest)
+		}
+		if gen.TPS() != 10 || gen.FraudRate() != 0.1 {
+			t.Errorf("%s: rejected change left tps %d and fraud rate %.2f", tt.name, gen.TPS(), gen.FraudRate())
+		}
+		server.Close()
+	}
+}
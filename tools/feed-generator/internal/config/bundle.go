@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ReproductionBundle captures the resolved generation state so a feed can be regenerated
+type ReproductionBundle struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Config    Config    `json:"config"`
+}
+
+// WriteBundle writes a reproduction bundle for the given configuration
+func WriteBundle(path, version string, cfg *Config) error {
+	bundle := ReproductionBundle{
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+		Config:    *cfg,
+	}
+
+	// Connection settings (and credentials) are supplied at reproduction time
+	bundle.Config.Redis = RedisConfig{}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode reproduction bundle: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write reproduction bundle: %w", err)
+	}
+
+	return nil
+}
+
+// LoadBundle reads and validates a reproduction bundle
+func LoadBundle(path string) (*ReproductionBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reproduction bundle: %w", err)
+	}
+
+	var bundle ReproductionBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode reproduction bundle: %w", err)
+	}
+
+	if err := bundle.Config.Generate.resolveLocation(); err != nil {
+		return nil, fmt.Errorf("invalid bundle config: %w", err)
+	}
+	if err := bundle.Config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid bundle config: %w", err)
+	}
+
+	return &bundle, nil
+}
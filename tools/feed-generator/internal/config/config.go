@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -9,9 +12,54 @@ import (
 
 // Config holds all configuration for the feed generator
 type Config struct {
-	Redis    RedisConfig
-	Generate GenerateConfig
-	Profiles ProfilesConfig
+	Redis          RedisConfig
+	Kafka          KafkaConfig
+	Generate       GenerateConfig
+	Profiles       ProfilesConfig
+	Session        SessionConfig
+	FraudSymbols   map[string][]string      // Symbol universe per fraud type, overriding profile symbols
+	PriceDynamics  map[string]PriceDynamics // Per-symbol random walk parameters, overriding the generate defaults
+	Prices         map[string]float64       // Per-symbol base prices, merged over the built-in table
+	PriceJitter    map[string]float64       // Per-symbol per-trade price jitter, overriding the ±1% default
+	Spreads        map[string]float64       // Per-symbol bid/ask spread as a fraction of price, overriding generate.spread
+	SymbolMetadata map[string]SymbolInfo    // Per-symbol sector and asset class, merged over the built-in table
+	AnomalyWeights map[string]float64       // Relative selection weight per anomaly type, 0 = disabled
+	FraudWindows   []FraudWindow            // Scheduled fraud bursts, overriding the fraud rate and type
+	NewsEvents     []NewsEvent              // Scheduled price-moving news, traded ahead of by insiders
+}
+
+// NewsEvent moves Symbol's price by Change (0.15 = up 15%) At after the run
+// starts. Insider profiles accumulate the symbol in the lead-up.
+type NewsEvent struct {
+	At     time.Duration
+	Symbol string
+	Change float64
+}
+
+// FraudWindow overrides the fraud rate, and optionally the fraud type, from
+// Start until End after the run starts
+type FraudWindow struct {
+	Start time.Duration
+	End   time.Duration
+	Rate  float64
+	Type  string // Fraud type within the window (empty = the global fraud type)
+}
+
+// AnomalyTypes lists the kinds of single-trade anomaly, in selection order
+var AnomalyTypes = []string{"size", "off_hours", "penny_stock", "price"}
+
+// PriceDynamics holds the random walk parameters for a symbol's price
+type PriceDynamics struct {
+	Drift      float64 // Expected log return per hour (gbm model)
+	Volatility float64 // Standard deviation of log return per square-root hour
+	Reversion  float64 // Speed the log price is pulled back toward Mean, per hour (mean_reverting model)
+	Mean       float64 // Long-run price of the mean_reverting model, 0 = the symbol's base price
+}
+
+// SymbolInfo classifies a symbol for detectors that segment by sector
+type SymbolInfo struct {
+	Sector     string
+	AssetClass string
 }
 
 // RedisConfig holds Redis connection settings
@@ -22,20 +70,127 @@ type RedisConfig struct {
 	DB       int
 }
 
+// KafkaConfig holds Kafka producer settings for the kafka output backend
+type KafkaConfig struct {
+	Brokers      []string
+	Topic        string
+	RequiredAcks string // Acknowledgement level: none, one or all
+}
+
 // GenerateConfig holds generation settings
 type GenerateConfig struct {
-	TPS           int
-	Duration      time.Duration
-	FraudRate     float64
-	FraudType     string
-	Verbose       bool
-	StatsInterval time.Duration
+	TPS                   int
+	Stream                string // Redis stream trades are published to
+	StreamMaxLen          int64  // Approximate stream length cap, 0 = unbounded
+	CreateGroup           string // Consumer group created on the stream at startup, empty = none
+	Duration              time.Duration
+	MaxTrades             int64  // Stop once this many trades are generated, 0 = no cap
+	RampFrom              int    // Linear ramp start TPS, 0 = no ramp
+	RampTo                int    // Linear ramp end TPS, reached at the end of the run
+	Schedule              string // Step schedule, e.g. "100@0s,500@1m"
+	FraudRate             float64
+	FraudType             string
+	FraudOnly             bool
+	FraudEvery            int // Fraud pattern on every Nth tick instead of at FraudRate, 0 = off
+	FraudSizeMultiplier   float64
+	AnomalyPriceSigmas    float64
+	AnomalyType           string // Only inject this anomaly type (empty = weighted mix)
+	ImbalanceRatio        float64
+	TargetStreamLength    int64
+	StreamDepthGain       float64
+	FraudLagGroup         string  // Consumer group whose lag raises the fraud rate, empty = fixed rate
+	FraudRateMax          float64 // Fraud rate reached at FraudLagMax lag
+	FraudLagMax           int64   // Lag at which the fraud rate reaches FraudRateMax
+	AmountMode            string  // Unit of trade amounts and avg_trade_size: shares or notional
+	RoundLotSize          int
+	OddLotProbability     float64
+	TimestampSkewRate     float64
+	TimestampSkewRange    time.Duration
+	TimestampJitter       string  // Spread of normal trade times within a tick: none, uniform or poisson
+	UserSessionTrades     float64 // Mean normal trades per trader session, 0 or 1 = a fresh trader every trade
+	SyntheticSymbols      int
+	Symbols               []string // Only trade these symbols, overriding every profile's, empty = profile symbols
+	ReportResources       bool
+	FragmentedWashPairs   int
+	FragmentedWashSize    float64
+	PumpDumpWindow        time.Duration
+	RingSize              int     // Accounts in a trading ring, 0 = 3-6 at random
+	FatFingerCorrection   float64 // Fraction of fat-finger errors followed by a correcting trade
+	HaltThreshold         float64 // Price move that halts a symbol (0.1 = 10%), 0 = never halt
+	HaltWindow            time.Duration
+	HaltDuration          time.Duration
+	HaltMarkers           bool // Publish a marker when a halt starts and ends
+	InsiderLead           time.Duration
+	ComboPatterns         []string
+	QuoteStuffSize        int     // Orders per quote stuffing burst
+	QuoteStuffCancelRatio float64 // Fraction of quote stuffing orders cancelled
+	CancelOrders          bool    // Publish the cancelled orders implied by each profile's order_to_trade
+	PriceModel            string  // How prices move between trades: gbm, mean_reverting or jitter
+	PriceDrift            float64
+	PriceVolatility       float64
+	PriceReversion        float64 // Mean reversion speed per hour of the mean_reverting model
+	PriceJumpRate         float64
+	PriceJumpMin          float64
+	PriceJumpMax          float64
+	Spread                float64 // Bid/ask spread normal trades cross, as a fraction of price, 0 = print at the mid
+	MarketHours           bool
+	VolumeProfile         string    // flat, u-shape or custom
+	VolumeWeights         []float64 // Relative volume per hour of day for the custom profile
+	SizeBuckets           []float64 // Upper bounds of the final trade size histogram buckets
+	Seed                  int64
+	TimingSeed            int64
+	Timezone              string         // IANA zone for profile active hours
+	Location              *time.Location `json:"-"` // Resolved Timezone; time.Local when unset
+	TagTraderType         bool
+	TagSector             bool   // Publish each trade's sector and asset class
+	DefaultSector         string // Sector of symbols missing from the metadata table
+	DefaultAssetClass     string // Asset class of symbols missing from the metadata table
+	EnforcePositions      bool   // Stop normal traders selling shares they do not hold
+	SequenceNumbers       bool   // Number published trades for gap detection
+	StallTimeout          time.Duration
+	MetricsAddr           string
+	AdminAddr             string // Admin API for live TPS and fraud rate changes
+	PublishRetries        int
+	PublishBackoff        time.Duration
+	ReconnectBuffer       int // Trades buffered through a Redis outage, 0 = no buffering
+	BatchSize             int
+	Workers               int    // Publisher goroutines; 1 publishes inline
+	QueueSize             int    // Batches waiting for publisher workers
+	QueueFull             string // What generation does when the queue is full: block or drop
+	FlushInterval         time.Duration
+	OutputBackend         string // Comma-separated outputs trades fan out to: redis, kafka, file, ws
+	OutputFailFast        bool   // Stop a fan-out batch at the first output that fails
+	PayloadFormat         string // Encoding of Redis trade stream entries: fields, json or protobuf
+	WSAddr                string // Address the ws output backend serves WebSocket clients on
+	WSBuffer              int    // Messages buffered per WebSocket client before it misses trades
+	OutputFile            string
+	DryRun                bool // Discard trades instead of publishing them
+	OutputFormat          string
+	LabelsOutput          string
+	NegativeLabels        bool
+	StatsInterval         time.Duration
+	StatsFile             string // Final statistics as JSON, written here at exit
+	StatsFormat           string // Final summary on stdout: text or json
+	Progress              bool   // In-place elapsed/total display on stderr
+
+	SnapshotFile     string        // Run state saved here periodically and at exit, empty = none
+	SnapshotInterval time.Duration // How often the run state is saved
+	Resume           string        // Snapshot to restore the run state from at startup, empty = fresh run
+}
+
+// SessionConfig holds the trading session used in market-hours mode
+type SessionConfig struct {
+	Open     string // Session open, HH:MM in the session timezone
+	Close    string // Session close, HH:MM in the session timezone
+	Timezone string // IANA timezone name, e.g. America/New_York
 }
 
 // ProfilesConfig holds trader profile distribution settings
 type ProfilesConfig struct {
+	File         string // Trader profiles file; empty uses the built-in profiles
 	HFTRatio     float64
 	RegularRatio float64
+	MMRatio      float64 // Market makers; 0 leaves them out of the population
 	CasualRatio  float64
 }
 
@@ -48,45 +203,219 @@ func LoadConfig() (*Config, error) {
 			Password: viper.GetString("redis.password"),
 			DB:       viper.GetInt("redis.db"),
 		},
+		Kafka: KafkaConfig{
+			Brokers:      viper.GetStringSlice("kafka.brokers"),
+			Topic:        viper.GetString("kafka.topic"),
+			RequiredAcks: strings.ToLower(viper.GetString("kafka.required_acks")),
+		},
 		Generate: GenerateConfig{
-			TPS:           viper.GetInt("generate.tps"),
-			Duration:      viper.GetDuration("generate.duration"),
-			FraudRate:     viper.GetFloat64("generate.fraud_rate"),
-			FraudType:     viper.GetString("generate.fraud_type"),
-			Verbose:       viper.GetBool("generate.verbose"),
-			StatsInterval: viper.GetDuration("generate.stats_interval"),
+			TPS:                   viper.GetInt("generate.tps"),
+			Stream:                viper.GetString("generate.stream"),
+			StreamMaxLen:          viper.GetInt64("generate.stream_maxlen"),
+			CreateGroup:           viper.GetString("generate.create_group"),
+			Duration:              viper.GetDuration("generate.duration"),
+			MaxTrades:             viper.GetInt64("generate.max_trades"),
+			RampFrom:              viper.GetInt("generate.ramp_from"),
+			RampTo:                viper.GetInt("generate.ramp_to"),
+			Schedule:              viper.GetString("generate.schedule"),
+			FraudRate:             viper.GetFloat64("generate.fraud_rate"),
+			FraudType:             viper.GetString("generate.fraud_type"),
+			FraudOnly:             viper.GetBool("generate.fraud_only"),
+			FraudEvery:            viper.GetInt("generate.fraud_every"),
+			FraudSizeMultiplier:   viper.GetFloat64("generate.fraud_size_multiplier"),
+			AnomalyPriceSigmas:    viper.GetFloat64("generate.anomaly_price_sigmas"),
+			AnomalyType:           strings.ToLower(viper.GetString("generate.anomaly_type")),
+			ImbalanceRatio:        viper.GetFloat64("generate.imbalance_ratio"),
+			TargetStreamLength:    viper.GetInt64("generate.target_stream_length"),
+			StreamDepthGain:       viper.GetFloat64("generate.stream_depth_gain"),
+			FraudLagGroup:         viper.GetString("generate.fraud_lag_group"),
+			FraudRateMax:          viper.GetFloat64("generate.fraud_rate_max"),
+			FraudLagMax:           viper.GetInt64("generate.fraud_lag_max"),
+			AmountMode:            strings.ToLower(viper.GetString("generate.amount_mode")),
+			RoundLotSize:          viper.GetInt("generate.round_lot_size"),
+			OddLotProbability:     viper.GetFloat64("generate.odd_lot_probability"),
+			TimestampSkewRate:     viper.GetFloat64("generate.timestamp_skew_rate"),
+			TimestampSkewRange:    viper.GetDuration("generate.timestamp_skew_range"),
+			TimestampJitter:       strings.ToLower(viper.GetString("generate.timestamp_jitter")),
+			UserSessionTrades:     viper.GetFloat64("generate.user_session_trades"),
+			SyntheticSymbols:      viper.GetInt("generate.synthetic_symbols"),
+			Symbols:               viper.GetStringSlice("generate.symbols"),
+			ReportResources:       viper.GetBool("generate.report_resources"),
+			FragmentedWashPairs:   viper.GetInt("generate.fragmented_wash_pairs"),
+			FragmentedWashSize:    viper.GetFloat64("generate.fragmented_wash_size"),
+			PumpDumpWindow:        viper.GetDuration("generate.pump_dump_window"),
+			RingSize:              viper.GetInt("generate.ring_size"),
+			FatFingerCorrection:   viper.GetFloat64("generate.fat_finger_correction"),
+			HaltThreshold:         viper.GetFloat64("generate.halt_threshold"),
+			HaltWindow:            viper.GetDuration("generate.halt_window"),
+			HaltDuration:          viper.GetDuration("generate.halt_duration"),
+			HaltMarkers:           viper.GetBool("generate.halt_markers"),
+			InsiderLead:           viper.GetDuration("generate.insider_lead"),
+			QuoteStuffSize:        viper.GetInt("generate.quote_stuff_size"),
+			QuoteStuffCancelRatio: viper.GetFloat64("generate.quote_stuff_cancel_ratio"),
+			CancelOrders:          viper.GetBool("generate.cancel_orders"),
+			ComboPatterns:         viper.GetStringSlice("generate.combo_patterns"),
+			PriceModel:            strings.ToLower(viper.GetString("generate.price_model")),
+			PriceDrift:            viper.GetFloat64("generate.price_drift"),
+			PriceVolatility:       viper.GetFloat64("generate.price_volatility"),
+			PriceReversion:        viper.GetFloat64("generate.price_reversion"),
+			PriceJumpRate:         viper.GetFloat64("generate.price_jump_rate"),
+			PriceJumpMin:          viper.GetFloat64("generate.price_jump_min"),
+			PriceJumpMax:          viper.GetFloat64("generate.price_jump_max"),
+			Spread:                viper.GetFloat64("generate.spread"),
+			MarketHours:           viper.GetBool("generate.market_hours"),
+			Timezone:              viper.GetString("generate.timezone"),
+			VolumeProfile:         strings.ToLower(viper.GetString("generate.volume_profile")),
+			Seed:                  viper.GetInt64("generate.seed"),
+			TimingSeed:            viper.GetInt64("generate.timing_seed"),
+			TagTraderType:         viper.GetBool("generate.tag_trader_type"),
+			TagSector:             viper.GetBool("generate.tag_sector"),
+			DefaultSector:         strings.ToUpper(viper.GetString("generate.default_sector")),
+			DefaultAssetClass:     strings.ToUpper(viper.GetString("generate.default_asset_class")),
+			EnforcePositions:      viper.GetBool("generate.enforce_positions"),
+			SequenceNumbers:       viper.GetBool("generate.sequence_numbers"),
+			StallTimeout:          viper.GetDuration("generate.stall_timeout"),
+			MetricsAddr:           viper.GetString("generate.metrics_addr"),
+			AdminAddr:             viper.GetString("generate.admin_addr"),
+			PublishRetries:        viper.GetInt("generate.publish_retries"),
+			PublishBackoff:        viper.GetDuration("generate.publish_backoff"),
+			ReconnectBuffer:       viper.GetInt("generate.reconnect_buffer"),
+			BatchSize:             viper.GetInt("generate.batch_size"),
+			Workers:               viper.GetInt("generate.workers"),
+			QueueSize:             viper.GetInt("generate.queue_size"),
+			QueueFull:             strings.ToLower(viper.GetString("generate.queue_full")),
+			FlushInterval:         viper.GetDuration("generate.flush_interval"),
+			OutputBackend:         strings.ToLower(strings.ReplaceAll(viper.GetString("generate.output_backend"), " ", "")),
+			OutputFailFast:        viper.GetBool("generate.output_fail_fast"),
+			WSAddr:                viper.GetString("generate.ws_addr"),
+			WSBuffer:              viper.GetInt("generate.ws_buffer"),
+			PayloadFormat:         strings.ToLower(viper.GetString("generate.payload_format")),
+			OutputFile:            viper.GetString("generate.output_file"),
+			DryRun:                viper.GetBool("generate.dry_run"),
+			OutputFormat:          strings.ToLower(viper.GetString("generate.output_format")),
+			LabelsOutput:          viper.GetString("generate.labels_output"),
+			NegativeLabels:        viper.GetBool("generate.negative_labels"),
+			StatsInterval:         viper.GetDuration("generate.stats_interval"),
+			StatsFile:             viper.GetString("generate.stats_file"),
+			StatsFormat:           strings.ToLower(viper.GetString("generate.stats_format")),
+			Progress:              viper.GetBool("generate.progress"),
+			SnapshotFile:          viper.GetString("generate.snapshot_file"),
+			SnapshotInterval:      viper.GetDuration("generate.snapshot_interval"),
+			Resume:                viper.GetString("generate.resume"),
+		},
+		Session: SessionConfig{
+			Open:     viper.GetString("session.open"),
+			Close:    viper.GetString("session.close"),
+			Timezone: viper.GetString("session.timezone"),
 		},
 		Profiles: ProfilesConfig{
+			File:         viper.GetString("profiles.file"),
 			HFTRatio:     viper.GetFloat64("profiles.hft_ratio"),
 			RegularRatio: viper.GetFloat64("profiles.regular_ratio"),
+			MMRatio:      viper.GetFloat64("profiles.mm_ratio"),
 			CasualRatio:  viper.GetFloat64("profiles.casual_ratio"),
 		},
 	}
 
-	// Set defaults if not specified
-	if cfg.Redis.Port == 0 {
-		cfg.Redis.Port = 6379
+	// Viper lower-cases map keys; fraud types and symbols are upper case
+	cfg.FraudSymbols = make(map[string][]string)
+	for fraudType, symbols := range viper.GetStringMapStringSlice("fraud_symbols") {
+		for i := range symbols {
+			symbols[i] = strings.ToUpper(strings.TrimSpace(symbols[i]))
+		}
+		cfg.FraudSymbols[strings.ToUpper(fraudType)] = symbols
 	}
-	if cfg.Generate.TPS == 0 {
-		cfg.Generate.TPS = 100
+
+	if err := viper.UnmarshalKey("fraud_windows", &cfg.FraudWindows); err != nil {
+		return nil, fmt.Errorf("invalid fraud windows: %w", err)
 	}
-	if cfg.Generate.Duration == 0 {
-		cfg.Generate.Duration = 5 * time.Minute
+	for i := range cfg.FraudWindows {
+		cfg.FraudWindows[i].Type = strings.ToUpper(cfg.FraudWindows[i].Type)
 	}
-	if cfg.Generate.StatsInterval == 0 {
-		cfg.Generate.StatsInterval = 10 * time.Second
+	if err := viper.UnmarshalKey("news_events", &cfg.NewsEvents); err != nil {
+		return nil, fmt.Errorf("invalid news events: %w", err)
 	}
-	if cfg.Generate.FraudType == "" {
-		cfg.Generate.FraudType = "ALL"
+	for i := range cfg.NewsEvents {
+		cfg.NewsEvents[i].Symbol = strings.ToUpper(cfg.NewsEvents[i].Symbol)
 	}
-	if cfg.Profiles.HFTRatio == 0 {
-		cfg.Profiles.HFTRatio = 0.20
+	for i := range cfg.Generate.ComboPatterns {
+		cfg.Generate.ComboPatterns[i] = strings.ToUpper(cfg.Generate.ComboPatterns[i])
+	}
+	for i := range cfg.Generate.Symbols {
+		cfg.Generate.Symbols[i] = strings.ToUpper(strings.TrimSpace(cfg.Generate.Symbols[i]))
+	}
+
+	// Anomaly types missing from the config keep an equal share
+	cfg.AnomalyWeights = defaultAnomalyWeights()
+	for anomalyType := range viper.GetStringMap("anomaly_weights") {
+		cfg.AnomalyWeights[strings.ToLower(anomalyType)] = viper.GetFloat64("anomaly_weights." + anomalyType)
+	}
+
+	for _, weight := range viper.GetStringSlice("generate.volume_weights") {
+		value, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid volume weight %q: %w", weight, err)
+		}
+		cfg.Generate.VolumeWeights = append(cfg.Generate.VolumeWeights, value)
+	}
+	for _, bound := range viper.GetStringSlice("generate.size_buckets") {
+		value, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size bucket %q: %w", bound, err)
+		}
+		cfg.Generate.SizeBuckets = append(cfg.Generate.SizeBuckets, value)
+	}
+
+	cfg.applyDefaults(viper.IsSet)
+
+	if err := cfg.Generate.resolveLocation(); err != nil {
+		return nil, err
+	}
+
+	// Symbols missing a parameter inherit the generate default
+	cfg.PriceDynamics = make(map[string]PriceDynamics)
+	for symbol := range viper.GetStringMap("price_dynamics") {
+		key := "price_dynamics." + symbol
+		dynamics := PriceDynamics{
+			Drift:      cfg.Generate.PriceDrift,
+			Volatility: cfg.Generate.PriceVolatility,
+			Reversion:  cfg.Generate.PriceReversion,
+		}
+		if viper.IsSet(key + ".drift") {
+			dynamics.Drift = viper.GetFloat64(key + ".drift")
+		}
+		if viper.IsSet(key + ".volatility") {
+			dynamics.Volatility = viper.GetFloat64(key + ".volatility")
+		}
+		if viper.IsSet(key + ".reversion") {
+			dynamics.Reversion = viper.GetFloat64(key + ".reversion")
+		}
+		dynamics.Mean = viper.GetFloat64(key + ".mean")
+		cfg.PriceDynamics[strings.ToUpper(symbol)] = dynamics
+	}
+
+	cfg.Prices = make(map[string]float64)
+	for symbol := range viper.GetStringMap("prices") {
+		cfg.Prices[strings.ToUpper(symbol)] = viper.GetFloat64("prices." + symbol)
+	}
+
+	cfg.PriceJitter = make(map[string]float64)
+	for symbol := range viper.GetStringMap("price_jitter") {
+		cfg.PriceJitter[strings.ToUpper(symbol)] = viper.GetFloat64("price_jitter." + symbol)
 	}
-	if cfg.Profiles.RegularRatio == 0 {
-		cfg.Profiles.RegularRatio = 0.70
+
+	cfg.Spreads = make(map[string]float64)
+	for symbol := range viper.GetStringMap("spreads") {
+		cfg.Spreads[strings.ToUpper(symbol)] = viper.GetFloat64("spreads." + symbol)
 	}
-	if cfg.Profiles.CasualRatio == 0 {
-		cfg.Profiles.CasualRatio = 0.10
+
+	cfg.SymbolMetadata = make(map[string]SymbolInfo)
+	for symbol := range viper.GetStringMap("symbol_metadata") {
+		key := "symbol_metadata." + symbol
+		cfg.SymbolMetadata[strings.ToUpper(symbol)] = SymbolInfo{
+			Sector:     strings.ToUpper(viper.GetString(key + ".sector")),
+			AssetClass: strings.ToUpper(viper.GetString(key + ".asset_class")),
+		}
 	}
 
 	// Validate
@@ -97,24 +426,800 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// Default returns the built-in configuration, with every setting at the
+// value LoadConfig gives it when unset. Flag defaults are not applied, so the
+// fraud rate is zero.
+func Default() *Config {
+	cfg := &Config{
+		FraudSymbols:   make(map[string][]string),
+		AnomalyWeights: defaultAnomalyWeights(),
+		PriceDynamics:  make(map[string]PriceDynamics),
+		Prices:         make(map[string]float64),
+		PriceJitter:    make(map[string]float64),
+		Spreads:        make(map[string]float64),
+		SymbolMetadata: make(map[string]SymbolInfo),
+	}
+	cfg.applyDefaults(func(string) bool { return false })
+	cfg.Generate.Location = time.Local
+	return cfg
+}
+
+// defaultAnomalyWeights gives every anomaly type an equal share
+func defaultAnomalyWeights() map[string]float64 {
+	weights := make(map[string]float64)
+	for _, anomalyType := range AnomalyTypes {
+		weights[anomalyType] = 1
+	}
+	return weights
+}
+
+// applyDefaults fills in settings left unset. isSet reports whether a setting
+// was given explicitly, for settings whose zero value is valid.
+func (c *Config) applyDefaults(isSet func(key string) bool) {
+	// Zero retries is valid (drop on the first failure), so only default it when unset
+	if !isSet("generate.publish_retries") {
+		c.Generate.PublishRetries = 3
+	}
+
+	// The fraud rate flag always has a value; only an explicit rate conflicts
+	// with fraud-every mode
+	if c.Generate.FraudEvery > 0 && !isSet("generate.fraud_rate") {
+		c.Generate.FraudRate = 0
+	}
+
+	// Zero turns outage buffering off, so only default it when unset
+	if !isSet("generate.reconnect_buffer") {
+		c.Generate.ReconnectBuffer = 10000
+	}
+
+	// An explicitly empty stream name is rejected by validation, not defaulted
+	if !isSet("generate.stream") {
+		c.Generate.Stream = "trades:stream"
+	}
+
+	// Zero volatility is valid (static prices), so only default it when unset
+	if !isSet("generate.price_volatility") {
+		c.Generate.PriceVolatility = 0.05
+	}
+	if c.Generate.PriceModel == "" {
+		c.Generate.PriceModel = "gbm"
+	}
+	if !isSet("generate.price_reversion") {
+		c.Generate.PriceReversion = 1
+	}
+	if c.Generate.PriceJumpMin == 0 {
+		c.Generate.PriceJumpMin = 0.05
+	}
+	if c.Generate.PriceJumpMax == 0 {
+		c.Generate.PriceJumpMax = 0.20
+	}
+
+	if c.Redis.Port == 0 {
+		c.Redis.Port = 6379
+	}
+	if c.Generate.TPS == 0 {
+		c.Generate.TPS = 100
+	}
+	// Zero duration means run until stopped, so only default it when unset
+	if !isSet("generate.duration") {
+		c.Generate.Duration = 5 * time.Minute
+	}
+	if c.Generate.StatsInterval == 0 {
+		c.Generate.StatsInterval = 10 * time.Second
+	}
+	if c.Generate.SnapshotInterval == 0 {
+		c.Generate.SnapshotInterval = time.Minute
+	}
+	if c.Generate.WSAddr == "" {
+		c.Generate.WSAddr = ":9102"
+	}
+	if c.Generate.WSBuffer == 0 {
+		c.Generate.WSBuffer = 256
+	}
+	if len(c.Kafka.Brokers) == 0 {
+		c.Kafka.Brokers = []string{"localhost:9092"}
+	}
+	if c.Kafka.Topic == "" {
+		c.Kafka.Topic = "trades"
+	}
+	if c.Kafka.RequiredAcks == "" {
+		c.Kafka.RequiredAcks = "all"
+	}
+	if c.Generate.OutputBackend == "" {
+		c.Generate.OutputBackend = "redis"
+	}
+	if c.Generate.PayloadFormat == "" {
+		c.Generate.PayloadFormat = "fields"
+	}
+	if c.Generate.StatsFormat == "" {
+		c.Generate.StatsFormat = "text"
+	}
+	if c.Generate.FraudType == "" {
+		c.Generate.FraudType = "ALL"
+	}
+	if c.Generate.FraudSizeMultiplier == 0 {
+		c.Generate.FraudSizeMultiplier = 1.0
+	}
+	if c.Generate.AnomalyPriceSigmas == 0 {
+		c.Generate.AnomalyPriceSigmas = 10
+	}
+	if !isSet("generate.imbalance_ratio") {
+		c.Generate.ImbalanceRatio = 0.95
+	}
+	if c.Generate.StreamDepthGain == 0 {
+		c.Generate.StreamDepthGain = 0.5
+	}
+	if !isSet("generate.fraud_rate_max") {
+		c.Generate.FraudRateMax = 0.5
+	}
+	if c.Generate.FraudLagMax == 0 {
+		c.Generate.FraudLagMax = 10000
+	}
+	if !isSet("generate.odd_lot_probability") {
+		c.Generate.OddLotProbability = 0.1
+	}
+	if c.Generate.TimestampSkewRange == 0 {
+		c.Generate.TimestampSkewRange = 5 * time.Second
+	}
+	if c.Generate.TimestampJitter == "" {
+		c.Generate.TimestampJitter = "none"
+	}
+	if c.Generate.OutputFormat == "" {
+		c.Generate.OutputFormat = "ndjson"
+	}
+	if c.Generate.AmountMode == "" {
+		c.Generate.AmountMode = "shares"
+	}
+	if c.Generate.BatchSize == 0 {
+		c.Generate.BatchSize = 1
+	}
+	if c.Generate.Workers == 0 {
+		c.Generate.Workers = 1
+	}
+	if c.Generate.QueueSize == 0 {
+		c.Generate.QueueSize = c.Generate.Workers * 2
+	}
+	if c.Generate.QueueFull == "" {
+		c.Generate.QueueFull = "block"
+	}
+	if len(c.Generate.SizeBuckets) == 0 {
+		c.Generate.SizeBuckets = []float64{10, 100, 1000, 10000, 100000}
+	}
+	if c.Generate.PublishBackoff == 0 {
+		c.Generate.PublishBackoff = 100 * time.Millisecond
+	}
+	if c.Generate.FlushInterval == 0 {
+		c.Generate.FlushInterval = 100 * time.Millisecond
+	}
+	if c.Generate.FragmentedWashPairs == 0 {
+		c.Generate.FragmentedWashPairs = 10
+	}
+	if c.Generate.FragmentedWashSize == 0 {
+		c.Generate.FragmentedWashSize = 500
+	}
+	if c.Generate.DefaultSector == "" {
+		c.Generate.DefaultSector = "UNKNOWN"
+	}
+	if c.Generate.DefaultAssetClass == "" {
+		c.Generate.DefaultAssetClass = "UNKNOWN"
+	}
+	if c.Generate.PumpDumpWindow == 0 {
+		c.Generate.PumpDumpWindow = 30 * time.Minute
+	}
+	// Zero means errors are never corrected, so only default it when unset
+	if !isSet("generate.fat_finger_correction") {
+		c.Generate.FatFingerCorrection = 0.8
+	}
+	if c.Generate.HaltWindow == 0 {
+		c.Generate.HaltWindow = 5 * time.Minute
+	}
+	if c.Generate.HaltDuration == 0 {
+		c.Generate.HaltDuration = 5 * time.Minute
+	}
+	if c.Generate.InsiderLead == 0 {
+		c.Generate.InsiderLead = 5 * time.Minute
+	}
+	if c.Generate.QuoteStuffSize == 0 {
+		c.Generate.QuoteStuffSize = 100
+	}
+	// A zero cancel ratio is valid (every order fills), so only default it when unset
+	if !isSet("generate.quote_stuff_cancel_ratio") {
+		c.Generate.QuoteStuffCancelRatio = 0.95
+	}
+	if len(c.Generate.ComboPatterns) == 0 {
+		c.Generate.ComboPatterns = []string{"VELOCITY", "WASH"}
+	}
+	if c.Generate.VolumeProfile == "" {
+		c.Generate.VolumeProfile = "flat"
+	}
+	if c.Session.Open == "" {
+		c.Session.Open = "09:30"
+	}
+	if c.Session.Close == "" {
+		c.Session.Close = "16:00"
+	}
+	if c.Session.Timezone == "" {
+		c.Session.Timezone = "America/New_York"
+	}
+	if c.Profiles.HFTRatio == 0 {
+		c.Profiles.HFTRatio = 0.20
+	}
+	if c.Profiles.RegularRatio == 0 {
+		c.Profiles.RegularRatio = 0.70
+	}
+	if c.Profiles.CasualRatio == 0 {
+		c.Profiles.CasualRatio = 0.10
+	}
+
+}
+
+// resolveLocation loads the configured timezone. Active hours follow the
+// process timezone unless one is set.
+func (g *GenerateConfig) resolveLocation() error {
+	g.Location = time.Local
+	if g.Timezone == "" {
+		return nil
+	}
+
+	location, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", g.Timezone, err)
+	}
+	g.Location = location
+	return nil
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.Generate.TPS < 1 || c.Generate.TPS > 10000 {
 		return fmt.Errorf("tps must be between 1 and 10000, got %d", c.Generate.TPS)
 	}
+	if strings.TrimSpace(c.Generate.Stream) == "" {
+		return fmt.Errorf("stream name must not be empty")
+	}
+	if c.Generate.StreamMaxLen < 0 {
+		return fmt.Errorf("stream maxlen must be non-negative, got %d", c.Generate.StreamMaxLen)
+	}
+	if c.Generate.StreamMaxLen > 0 && c.Generate.StreamMaxLen < c.Generate.TargetStreamLength {
+		return fmt.Errorf("stream maxlen %d is below the target stream length %d", c.Generate.StreamMaxLen, c.Generate.TargetStreamLength)
+	}
+	if c.Generate.MaxTrades < 0 {
+		return fmt.Errorf("max trades must be non-negative, got %d", c.Generate.MaxTrades)
+	}
+	if c.Generate.Duration < 0 {
+		return fmt.Errorf("duration must be non-negative, got %v", c.Generate.Duration)
+	}
 	if c.Generate.FraudRate < 0 || c.Generate.FraudRate > 1 {
 		return fmt.Errorf("fraud rate must be between 0.0 and 1.0, got %.2f", c.Generate.FraudRate)
 	}
+	if c.Generate.FraudEvery < 0 {
+		return fmt.Errorf("fraud every must be non-negative, got %d", c.Generate.FraudEvery)
+	}
+	if c.Generate.FraudEvery > 0 {
+		switch {
+		case c.Generate.FraudRate > 0:
+			return fmt.Errorf("fraud every and fraud rate are mutually exclusive")
+		case c.Generate.FraudOnly:
+			return fmt.Errorf("fraud every and fraud only are mutually exclusive")
+		case len(c.FraudWindows) > 0:
+			return fmt.Errorf("fraud every cannot be combined with fraud windows")
+		}
+	}
+	if c.Generate.FraudSizeMultiplier <= 0 {
+		return fmt.Errorf("fraud size multiplier must be positive, got %.2f", c.Generate.FraudSizeMultiplier)
+	}
+	if c.Generate.AnomalyPriceSigmas <= 0 {
+		return fmt.Errorf("anomaly price sigmas must be positive, got %.2f", c.Generate.AnomalyPriceSigmas)
+	}
+	if c.Generate.ImbalanceRatio < 0 || c.Generate.ImbalanceRatio > 1 {
+		return fmt.Errorf("imbalance ratio must be between 0.0 and 1.0, got %.2f", c.Generate.ImbalanceRatio)
+	}
+	if c.Generate.TargetStreamLength < 0 {
+		return fmt.Errorf("target stream length must be non-negative, got %d", c.Generate.TargetStreamLength)
+	}
+	if c.Generate.StreamDepthGain <= 0 {
+		return fmt.Errorf("stream depth gain must be positive, got %.2f", c.Generate.StreamDepthGain)
+	}
+	if c.Generate.FraudLagGroup != "" {
+		switch {
+		case c.Generate.FraudRateMax < c.Generate.FraudRate || c.Generate.FraudRateMax > 1:
+			return fmt.Errorf("fraud rate max must be between the fraud rate %.2f and 1.0, got %.2f", c.Generate.FraudRate, c.Generate.FraudRateMax)
+		case c.Generate.FraudLagMax < 1:
+			return fmt.Errorf("fraud lag max must be positive, got %d", c.Generate.FraudLagMax)
+		case c.Generate.FraudOnly:
+			return fmt.Errorf("fraud lag group cannot be combined with fraud only")
+		case c.Generate.FraudEvery > 0:
+			return fmt.Errorf("fraud lag group cannot be combined with fraud every")
+		}
+	}
+	if c.Generate.AmountMode != "shares" && c.Generate.AmountMode != "notional" {
+		return fmt.Errorf("amount mode must be shares or notional, got %q", c.Generate.AmountMode)
+	}
+	if c.Generate.RoundLotSize < 0 {
+		return fmt.Errorf("round lot size must be non-negative, got %d", c.Generate.RoundLotSize)
+	}
+	if c.Generate.OddLotProbability < 0 || c.Generate.OddLotProbability > 1 {
+		return fmt.Errorf("odd lot probability must be between 0.0 and 1.0, got %.2f", c.Generate.OddLotProbability)
+	}
+	if c.Generate.TimestampSkewRate < 0 || c.Generate.TimestampSkewRate > 1 {
+		return fmt.Errorf("timestamp skew rate must be between 0.0 and 1.0, got %.2f", c.Generate.TimestampSkewRate)
+	}
+	if c.Generate.TimestampSkewRange < 0 {
+		return fmt.Errorf("timestamp skew range must be non-negative, got %v", c.Generate.TimestampSkewRange)
+	}
+	switch c.Generate.TimestampJitter {
+	case "none", "uniform", "poisson":
+	default:
+		return fmt.Errorf("timestamp jitter must be none, uniform or poisson, got %q", c.Generate.TimestampJitter)
+	}
+	if c.Generate.UserSessionTrades < 0 {
+		return fmt.Errorf("user session trades must be non-negative, got %.2f", c.Generate.UserSessionTrades)
+	}
+	if c.Generate.SyntheticSymbols < 0 {
+		return fmt.Errorf("synthetic symbols must be non-negative, got %d", c.Generate.SyntheticSymbols)
+	}
+	if err := c.validateSymbols(); err != nil {
+		return err
+	}
+	if c.Generate.FragmentedWashPairs < 1 {
+		return fmt.Errorf("fragmented wash pairs must be at least 1, got %d", c.Generate.FragmentedWashPairs)
+	}
+	if c.Generate.FragmentedWashSize <= 0 {
+		return fmt.Errorf("fragmented wash size must be positive, got %.2f", c.Generate.FragmentedWashSize)
+	}
+	if c.Generate.PriceVolatility < 0 {
+		return fmt.Errorf("price volatility must be non-negative, got %.4f", c.Generate.PriceVolatility)
+	}
+	switch c.Generate.PriceModel {
+	case "gbm", "mean_reverting", "jitter":
+	default:
+		return fmt.Errorf("price model must be gbm, mean_reverting or jitter, got %q", c.Generate.PriceModel)
+	}
+	if c.Generate.PriceReversion < 0 {
+		return fmt.Errorf("price reversion must be non-negative, got %.4f", c.Generate.PriceReversion)
+	}
+	if c.Generate.PriceJumpRate < 0 {
+		return fmt.Errorf("price jump rate must be non-negative, got %.4f", c.Generate.PriceJumpRate)
+	}
+	if c.Generate.Spread < 0 || c.Generate.Spread >= 1 {
+		return fmt.Errorf("spread must be in [0, 1), got %.4f", c.Generate.Spread)
+	}
+	if c.Generate.PriceJumpMin <= 0 || c.Generate.PriceJumpMax >= 1 || c.Generate.PriceJumpMin > c.Generate.PriceJumpMax {
+		return fmt.Errorf("price jump sizes must satisfy 0 < min <= max < 1, got %.2f-%.2f", c.Generate.PriceJumpMin, c.Generate.PriceJumpMax)
+	}
+	// Orders are at least a millisecond apart within the one second burst
+	if c.Generate.QuoteStuffSize < 1 || c.Generate.QuoteStuffSize > 1000 {
+		return fmt.Errorf("quote stuff size must be between 1 and 1000, got %d", c.Generate.QuoteStuffSize)
+	}
+	if c.Generate.QuoteStuffCancelRatio < 0 || c.Generate.QuoteStuffCancelRatio > 1 {
+		return fmt.Errorf("quote stuff cancel ratio must be between 0.0 and 1.0, got %.2f", c.Generate.QuoteStuffCancelRatio)
+	}
+	if len(c.Generate.ComboPatterns) < 2 {
+		return fmt.Errorf("combo patterns must list at least two fraud types, got %d", len(c.Generate.ComboPatterns))
+	}
+	if c.Generate.PumpDumpWindow < 0 {
+		return fmt.Errorf("pump and dump window must be positive, got %v", c.Generate.PumpDumpWindow)
+	}
+	if c.Generate.FatFingerCorrection < 0 || c.Generate.FatFingerCorrection > 1 {
+		return fmt.Errorf("fat finger correction must be between 0.0 and 1.0, got %.2f", c.Generate.FatFingerCorrection)
+	}
+	if c.Generate.HaltThreshold < 0 {
+		return fmt.Errorf("halt threshold must be non-negative, got %.2f", c.Generate.HaltThreshold)
+	}
+	if c.Generate.HaltWindow < 0 {
+		return fmt.Errorf("halt window must be positive, got %v", c.Generate.HaltWindow)
+	}
+	if c.Generate.HaltDuration < 0 {
+		return fmt.Errorf("halt duration must be positive, got %v", c.Generate.HaltDuration)
+	}
+	if c.Generate.RingSize != 0 && (c.Generate.RingSize < 3 || c.Generate.RingSize > 6) {
+		return fmt.Errorf("ring size must be between 3 and 6 (or 0 for random), got %d", c.Generate.RingSize)
+	}
+	if c.Generate.StallTimeout < 0 {
+		return fmt.Errorf("stall timeout must be non-negative, got %v", c.Generate.StallTimeout)
+	}
+	if c.Generate.SnapshotInterval < 0 {
+		return fmt.Errorf("snapshot interval must be positive, got %v", c.Generate.SnapshotInterval)
+	}
+	switch c.Generate.PayloadFormat {
+	case "fields", "json", "protobuf":
+	default:
+		return fmt.Errorf("payload format must be fields, json or protobuf, got %q", c.Generate.PayloadFormat)
+	}
+	if c.Generate.OutputFormat != "ndjson" && c.Generate.OutputFormat != "csv" {
+		return fmt.Errorf("output format must be ndjson or csv, got %q", c.Generate.OutputFormat)
+	}
+	if c.Generate.StatsFormat != "text" && c.Generate.StatsFormat != "json" {
+		return fmt.Errorf("stats format must be text or json, got %q", c.Generate.StatsFormat)
+	}
+	if c.Generate.NegativeLabels && c.Generate.LabelsOutput == "" {
+		return fmt.Errorf("negative labels require a labels output")
+	}
+	if err := c.validateOutputs(); err != nil {
+		return err
+	}
+	if c.Generate.DryRun && c.Generate.OutputFile != "" {
+		return fmt.Errorf("dry run discards trades, so it can't be combined with an output file")
+	}
+	if c.Generate.DryRun && c.Generate.TargetStreamLength > 0 {
+		return fmt.Errorf("target stream length requires Redis output, not a dry run")
+	}
+	if c.Generate.DryRun && c.Generate.CreateGroup != "" {
+		return fmt.Errorf("creating a consumer group requires Redis output, not a dry run")
+	}
+	if c.Generate.DryRun && c.Generate.FraudLagGroup != "" {
+		return fmt.Errorf("fraud lag group requires Redis output, not a dry run")
+	}
+	if c.Generate.PublishRetries < 0 {
+		return fmt.Errorf("publish retries must be non-negative, got %d", c.Generate.PublishRetries)
+	}
+	if c.Generate.PublishBackoff < 0 {
+		return fmt.Errorf("publish backoff must be positive, got %v", c.Generate.PublishBackoff)
+	}
+	if c.Generate.ReconnectBuffer < 0 {
+		return fmt.Errorf("reconnect buffer must be non-negative, got %d", c.Generate.ReconnectBuffer)
+	}
+	if c.Generate.BatchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, got %d", c.Generate.BatchSize)
+	}
+	if c.Generate.Workers < 1 {
+		return fmt.Errorf("workers must be at least 1, got %d", c.Generate.Workers)
+	}
+	if c.Generate.Workers > 1 && slices.Contains(c.OutputBackends(), "file") {
+		return fmt.Errorf("multiple workers require Redis output, not an output file")
+	}
+	if c.Generate.QueueSize < 1 {
+		return fmt.Errorf("queue size must be at least 1, got %d", c.Generate.QueueSize)
+	}
+	if c.Generate.QueueFull != "block" && c.Generate.QueueFull != "drop" {
+		return fmt.Errorf("queue full policy must be block or drop, got %q", c.Generate.QueueFull)
+	}
+	for i, bound := range c.Generate.SizeBuckets {
+		if bound <= 0 {
+			return fmt.Errorf("size bucket %d must be positive, got %v", i+1, bound)
+		}
+		if i > 0 && bound <= c.Generate.SizeBuckets[i-1] {
+			return fmt.Errorf("size buckets must be ascending, got %v after %v", bound, c.Generate.SizeBuckets[i-1])
+		}
+	}
+	if c.Generate.FlushInterval <= 0 {
+		return fmt.Errorf("flush interval must be positive, got %v", c.Generate.FlushInterval)
+	}
+
+	for i, window := range c.FraudWindows {
+		if window.Start < 0 || window.End <= window.Start {
+			return fmt.Errorf("fraud window %d must end after it starts, got %v-%v", i+1, window.Start, window.End)
+		}
+		if window.Rate < 0 || window.Rate > 1 {
+			return fmt.Errorf("fraud window %d rate must be between 0.0 and 1.0, got %.2f", i+1, window.Rate)
+		}
+	}
+
+	if c.Generate.InsiderLead < 0 {
+		return fmt.Errorf("insider lead must be positive, got %v", c.Generate.InsiderLead)
+	}
+	for i, event := range c.NewsEvents {
+		if event.Symbol == "" {
+			return fmt.Errorf("news event %d must name a symbol", i+1)
+		}
+		if event.At < c.Generate.InsiderLead {
+			return fmt.Errorf("news event %d must be at least the insider lead (%v) into the run, got %v",
+				i+1, c.Generate.InsiderLead, event.At)
+		}
+		if event.Change == 0 || event.Change <= -1 {
+			return fmt.Errorf("news event %d change must be non-zero and above -1.0, got %.2f", i+1, event.Change)
+		}
+	}
 
-	// Validate profile ratios sum to 1.0
-	sum := c.Profiles.HFTRatio + c.Profiles.RegularRatio + c.Profiles.CasualRatio
+	if err := c.validateAnomalies(); err != nil {
+		return err
+	}
+
+	for fraudType, symbols := range c.FraudSymbols {
+		if len(symbols) == 0 {
+			return fmt.Errorf("fraud symbols for %s must not be empty", fraudType)
+		}
+	}
+
+	if err := c.validateRate(); err != nil {
+		return err
+	}
+
+	switch c.Generate.VolumeProfile {
+	case "flat", "u-shape":
+	case "custom":
+		if err := validateVolumeWeights(c.Generate.VolumeWeights); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("volume profile must be flat, u-shape or custom, got %q", c.Generate.VolumeProfile)
+	}
+	if c.Generate.VolumeProfile != "flat" && c.Generate.TargetStreamLength > 0 {
+		return fmt.Errorf("volume profile %s cannot be combined with a target stream length", c.Generate.VolumeProfile)
+	}
+
+	// The session anchors market-hours mode and the u-shaped volume curve
+	if c.Generate.MarketHours || c.Generate.VolumeProfile == "u-shape" {
+		if _, _, _, err := c.Session.Parse(); err != nil {
+			return err
+		}
+	}
+
+	for symbol, dynamics := range c.PriceDynamics {
+		if dynamics.Volatility < 0 {
+			return fmt.Errorf("price volatility for %s must be non-negative, got %.4f", symbol, dynamics.Volatility)
+		}
+		if dynamics.Reversion < 0 {
+			return fmt.Errorf("price reversion for %s must be non-negative, got %.4f", symbol, dynamics.Reversion)
+		}
+		if dynamics.Mean < 0 {
+			return fmt.Errorf("price mean for %s must be positive, got %.2f", symbol, dynamics.Mean)
+		}
+	}
+
+	for symbol, price := range c.Prices {
+		if price <= 0 {
+			return fmt.Errorf("price for %s must be positive, got %.2f", symbol, price)
+		}
+	}
+
+	for symbol, jitter := range c.PriceJitter {
+		if jitter < 0 || jitter >= 1 {
+			return fmt.Errorf("price jitter for %s must be in [0, 1), got %.4f", symbol, jitter)
+		}
+	}
+
+	for symbol, spread := range c.Spreads {
+		if spread < 0 || spread >= 1 {
+			return fmt.Errorf("spread for %s must be in [0, 1), got %.4f", symbol, spread)
+		}
+	}
+
+	return c.Profiles.validateRatios()
+}
+
+// profileRatio is the population share of one normal trader type
+type profileRatio struct {
+	Key   string // Config key, e.g. hft_ratio
+	Value float64
+}
+
+// ratios returns the share of every normal trader type, in selection order
+func (p ProfilesConfig) ratios() []profileRatio {
+	return []profileRatio{
+		{"hft_ratio", p.HFTRatio},
+		{"regular_ratio", p.RegularRatio},
+		{"mm_ratio", p.MMRatio},
+		{"casual_ratio", p.CasualRatio},
+	}
+}
+
+// validateRatios checks every trader type ratio is non-negative and that
+// together they sum to 1.0, naming the components when they don't
+func (p ProfilesConfig) validateRatios() error {
+	ratios := p.ratios()
+	var sum float64
+	terms := make([]string, len(ratios))
+	for i, ratio := range ratios {
+		if ratio.Value < 0 {
+			return fmt.Errorf("%s must be non-negative, got %.2f", ratio.Key, ratio.Value)
+		}
+		sum += ratio.Value
+		terms[i] = fmt.Sprintf("%s %.2f", ratio.Key, ratio.Value)
+	}
 	if sum < 0.99 || sum > 1.01 {
-		return fmt.Errorf("profile ratios must sum to 1.0, got %.2f", sum)
+		return fmt.Errorf("profile ratios must sum to 1.0, got %.2f (%s)", sum, strings.Join(terms, " + "))
 	}
+	return nil
+}
 
+// Parse resolves the session timezone and its open and close times as
+// offsets from midnight
+func (s SessionConfig) Parse() (*time.Location, time.Duration, time.Duration, error) {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid session timezone %q: %w", s.Timezone, err)
+	}
+	open, err := parseClock(s.Open)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid session open: %w", err)
+	}
+	close, err := parseClock(s.Close)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid session close: %w", err)
+	}
+	if open == close {
+		return nil, 0, 0, fmt.Errorf("session open and close must differ, both %s", s.Open)
+	}
+	return location, open, close, nil
+}
+
+// validateRate checks the ramp and step schedule settings, which replace the
+// constant TPS and so exclude the other rate controls
+func (c *Config) validateRate() error {
+	ramp := c.Generate.RampFrom != 0 || c.Generate.RampTo != 0
+	if !ramp && c.Generate.Schedule == "" {
+		return nil
+	}
+
+	if ramp && c.Generate.Schedule != "" {
+		return fmt.Errorf("a ramp and a step schedule cannot be combined")
+	}
+	if ramp {
+		if c.Generate.RampFrom < 1 || c.Generate.RampFrom > 10000 || c.Generate.RampTo < 1 || c.Generate.RampTo > 10000 {
+			return fmt.Errorf("ramp tps must be between 1 and 10000, got %d to %d", c.Generate.RampFrom, c.Generate.RampTo)
+		}
+		// The ramp spans the run, so an unbounded run has nothing to ramp over
+		if c.Generate.Duration == 0 {
+			return fmt.Errorf("a ramp requires a duration to ramp over, got an infinite run")
+		}
+	} else if _, err := ParseSchedule(c.Generate.Schedule); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	if c.Generate.VolumeProfile != "flat" {
+		return fmt.Errorf("a rate schedule cannot be combined with volume profile %s", c.Generate.VolumeProfile)
+	}
+	if c.Generate.TargetStreamLength > 0 {
+		return fmt.Errorf("a rate schedule cannot be combined with a target stream length")
+	}
+	return nil
+}
+
+// validateVolumeWeights checks a custom volume profile has one non-negative
+// weight per hour of day and is not all zero
+func validateVolumeWeights(weights []float64) error {
+	if len(weights) != 24 {
+		return fmt.Errorf("volume weights must have 24 entries (one per hour), got %d", len(weights))
+	}
+	total := 0.0
+	for hour, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("volume weight for hour %d must be non-negative, got %.2f", hour, weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("volume weights must not all be zero")
+	}
 	return nil
 }
 
+// parseClock parses an HH:MM time of day into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// validateAnomalies checks the anomaly type filter and weights
+func (c *Config) validateAnomalies() error {
+	known := func(anomalyType string) bool {
+		for _, t := range AnomalyTypes {
+			if t == anomalyType {
+				return true
+			}
+		}
+		return false
+	}
+
+	if c.Generate.AnomalyType != "" && !known(c.Generate.AnomalyType) {
+		return fmt.Errorf("anomaly type must be one of %s, got %q", strings.Join(AnomalyTypes, ", "), c.Generate.AnomalyType)
+	}
+
+	total := 0.0
+	for anomalyType, weight := range c.AnomalyWeights {
+		if !known(anomalyType) {
+			return fmt.Errorf("anomaly weights: unknown anomaly type %q (available: %s)", anomalyType, strings.Join(AnomalyTypes, ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("anomaly weight for %s must be non-negative, got %.2f", anomalyType, weight)
+		}
+		total += weight
+	}
+	if c.Generate.AnomalyType == "" && total == 0 {
+		return fmt.Errorf("anomaly weights must enable at least one anomaly type")
+	}
+	return nil
+}
+
+// validateOutputs checks the output backends and the options that need a
+// particular one
+func (c *Config) validateOutputs() error {
+	backends := strings.Split(c.Generate.OutputBackend, ",")
+	for i, backend := range backends {
+		switch backend {
+		case "redis", "kafka":
+		case "ws":
+			if c.Generate.WSBuffer < 1 {
+				return fmt.Errorf("websocket buffer must be positive, got %d", c.Generate.WSBuffer)
+			}
+		case "file":
+			if c.Generate.OutputFile == "" {
+				return fmt.Errorf("the file output backend requires an output file")
+			}
+		default:
+			return fmt.Errorf("output backend must be redis, kafka, file or ws, got %q", backend)
+		}
+		if slices.Contains(backends[:i], backend) {
+			return fmt.Errorf("output backend %s is listed more than once", backend)
+		}
+	}
+	if c.Generate.OutputFile != "" && !slices.Contains(backends, "file") && c.Generate.OutputBackend != "redis" {
+		return fmt.Errorf("output file replaces the output backend, so it can't be combined with %s; add file to the output backends to write both", c.Generate.OutputBackend)
+	}
+
+	outputs := c.OutputBackends()
+	if !slices.Contains(outputs, "redis") {
+		if c.Generate.TargetStreamLength > 0 {
+			return fmt.Errorf("target stream length requires Redis output, not %s", strings.Join(outputs, ","))
+		}
+		if c.Generate.CreateGroup != "" {
+			return fmt.Errorf("creating a consumer group requires Redis output, not %s", strings.Join(outputs, ","))
+		}
+		if c.Generate.FraudLagGroup != "" {
+			return fmt.Errorf("fraud lag group requires Redis output, not %s", strings.Join(outputs, ","))
+		}
+	}
+	if slices.Contains(outputs, "kafka") {
+		if c.Kafka.RequiredAcks != "none" && c.Kafka.RequiredAcks != "one" && c.Kafka.RequiredAcks != "all" {
+			return fmt.Errorf("kafka required acks must be none, one or all, got %q", c.Kafka.RequiredAcks)
+		}
+	}
+	return nil
+}
+
+// validateSymbols checks the traded symbol override, and that symbols named
+// elsewhere in the config stay inside it
+func (c *Config) validateSymbols() error {
+	symbols := c.Generate.Symbols
+	if len(symbols) == 0 {
+		return nil
+	}
+	if c.Generate.SyntheticSymbols > 0 {
+		return fmt.Errorf("symbols and synthetic symbols are mutually exclusive")
+	}
+	for i, symbol := range symbols {
+		if symbol == "" {
+			return fmt.Errorf("symbols must not contain an empty symbol")
+		}
+		if slices.Contains(symbols[:i], symbol) {
+			return fmt.Errorf("symbol %s is listed more than once", symbol)
+		}
+	}
+	for fraudType, fraudSymbols := range c.FraudSymbols {
+		for _, symbol := range fraudSymbols {
+			if !slices.Contains(symbols, strings.ToUpper(symbol)) {
+				return fmt.Errorf("fraud symbol %s for %s is not in the traded symbols %s", symbol, fraudType, strings.Join(symbols, ","))
+			}
+		}
+	}
+	for i, event := range c.NewsEvents {
+		if !slices.Contains(symbols, event.Symbol) {
+			return fmt.Errorf("news event %d symbol %s is not in the traded symbols %s", i+1, event.Symbol, strings.Join(symbols, ","))
+		}
+	}
+	return nil
+}
+
+// OutputBackends returns the outputs trades are published to, in order. An
+// output file without the file backend listed replaces the default Redis
+// backend.
+func (c *Config) OutputBackends() []string {
+	backends := strings.Split(c.Generate.OutputBackend, ",")
+	if c.Generate.OutputFile != "" && !slices.Contains(backends, "file") {
+		return []string{"file"}
+	}
+	return backends
+}
+
+// KafkaAddress returns the Kafka brokers as a comma-separated list
+func (c *Config) KafkaAddress() string {
+	return strings.Join(c.Kafka.Brokers, ",")
+}
+
 // RedisAddress returns the full Redis address
 func (c *Config) RedisAddress() string {
 	return fmt.Sprintf("%s:%d", c.Redis.Host, c.Redis.Port)
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"strings"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestValidateRampRequiresDuration(t *testing.T) {
+	cfg := Default()
+	cfg.Generate.RampFrom = 10
+	cfg.Generate.RampTo = 100
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("ramp over the default duration should validate: %v", err)
+	}
+
+	cfg.Generate.Duration = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("ramp over an infinite run should be rejected")
+	}
+}
+
+func TestValidateScheduleExclusions(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"ramp and schedule", func(c *Config) { c.Generate.RampFrom, c.Generate.RampTo = 1, 10 }},
+		{"volume profile", func(c *Config) { c.Generate.VolumeProfile = "u-shape" }},
+		{"target stream length", func(c *Config) { c.Generate.TargetStreamLength = 1000 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Generate.Schedule = "100@0s,500@1m"
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestValidateVolumeProfileExcludesTargetStreamLength(t *testing.T) {
+	cfg := Default()
+	cfg.Generate.VolumeProfile = "u-shape"
+	cfg.Generate.TargetStreamLength = 1000
+	if err := cfg.Validate(); err == nil {
+		t.Error("a volume profile with a target stream length should be rejected")
+	}
+}
+
+func TestValidateSymbols(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"with synthetic symbols", func(c *Config) { c.Generate.SyntheticSymbols = 10 }},
+		{"empty symbol", func(c *Config) { c.Generate.Symbols = append(c.Generate.Symbols, "") }},
+		{"duplicate symbol", func(c *Config) { c.Generate.Symbols = append(c.Generate.Symbols, "AAPL") }},
+		{"fraud symbol outside", func(c *Config) { c.FraudSymbols["WASH"] = []string{"PENNY_A"} }},
+		{"news symbol outside", func(c *Config) { c.NewsEvents = []NewsEvent{{At: time.Hour, Symbol: "NVDA", Change: 0.1}} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Generate.Symbols = []string{"AAPL", "TSLA"}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("valid symbols rejected: %v", err)
+			}
+			tt.modify(cfg)
+			if err := cfg.Validate(); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestValidateRatiosAndWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string // Empty when the config is valid
+	}{
+		{"profile ratios over 1", func(c *Config) { c.Profiles.MMRatio = 0.2 }, "mm_ratio 0.20"},
+		{"profile ratios under 1", func(c *Config) { c.Profiles.RegularRatio = 0.5 }, "regular_ratio 0.50"},
+		{"negative profile ratio", func(c *Config) {
+			c.Profiles.CasualRatio = -0.1
+			c.Profiles.RegularRatio = 0.9
+		}, "casual_ratio must be non-negative"},
+		// Anomaly weights are relative, so any non-negative total is valid
+		{"anomaly weights over 1", func(c *Config) {
+			for anomalyType := range c.AnomalyWeights {
+				c.AnomalyWeights[anomalyType] = 2
+			}
+		}, ""},
+		{"anomaly weights under 1", func(c *Config) {
+			for anomalyType := range c.AnomalyWeights {
+				c.AnomalyWeights[anomalyType] = 0.01
+			}
+		}, ""},
+		{"negative anomaly weight", func(c *Config) { c.AnomalyWeights["size"] = -1 }, "anomaly weight for size must be non-negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("valid config rejected: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("expected an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("got error %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigNormalisesFraudSymbols(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	viper.Set("generate.symbols", []string{"AAPL", "TSLA"})
+	viper.Set("fraud_symbols", map[string]any{"wash": []string{" aapl", "tsla "}})
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cfg.FraudSymbols["WASH"], []string{"AAPL", "TSLA"}; !slices.Equal(got, want) {
+		t.Errorf("got WASH symbols %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateStep switches the generation rate to TPS once At has elapsed
+type RateStep struct {
+	TPS int
+	At  time.Duration
+}
+
+// ParseSchedule parses a step schedule such as "100@0s,500@1m,2000@2m" into
+// rate steps in ascending time order
+func ParseSchedule(schedule string) ([]RateStep, error) {
+	var steps []RateStep
+	for _, entry := range strings.Split(schedule, ",") {
+		rate, at, found := strings.Cut(strings.TrimSpace(entry), "@")
+		if !found {
+			return nil, fmt.Errorf("schedule entry %q must be TPS@offset, e.g. 500@1m", entry)
+		}
+
+		tps, err := strconv.Atoi(rate)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %q: invalid tps %q", entry, rate)
+		}
+		if tps < 1 || tps > 10000 {
+			return nil, fmt.Errorf("schedule entry %q: tps must be between 1 and 10000, got %d", entry, tps)
+		}
+
+		offset, err := time.ParseDuration(at)
+		if err != nil {
+			return nil, fmt.Errorf("schedule entry %q: invalid offset %q", entry, at)
+		}
+		if len(steps) > 0 && offset <= steps[len(steps)-1].At {
+			return nil, fmt.Errorf("schedule entry %q: offsets must increase", entry)
+		}
+		if offset < 0 {
+			return nil, fmt.Errorf("schedule entry %q: offset must be non-negative", entry)
+		}
+
+		steps = append(steps, RateStep{TPS: tps, At: offset})
+	}
+	return steps, nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	steps, err := ParseSchedule("100@0s,500@1m,2000@2m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RateStep{{At: 0, TPS: 100}, {At: time.Minute, TPS: 500}, {At: 2 * time.Minute, TPS: 2000}}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(steps), len(want))
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d: got %+v, want %+v", i, steps[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"100", "x@1m", "100@1m,50@30s"} {
+		if _, err := ParseSchedule(bad); err == nil {
+			t.Errorf("schedule %q should be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package feed
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of the Trade message in trade.proto
+const (
+	protoID         protowire.Number = 1
+	protoUserID     protowire.Number = 2
+	protoSymbol     protowire.Number = 3
+	protoAmount     protowire.Number = 4
+	protoPrice      protowire.Number = 5
+	protoTradeType  protowire.Number = 6
+	protoTimestamp  protowire.Number = 7
+	protoConditions protowire.Number = 8
+	protoLiquidity  protowire.Number = 9
+	protoTraderType protowire.Number = 10
+	protoSector     protowire.Number = 11
+	protoAssetClass protowire.Number = 12
+	protoCancelled  protowire.Number = 13
+	protoSeq        protowire.Number = 14
+)
+
+// MarshalProto encodes the trade as the Trade message in trade.proto. Zero
+// values are omitted, as proto3 does.
+func (t *Trade) MarshalProto() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, protoID, protowire.BytesType)
+	b = protowire.AppendBytes(b, t.ID[:])
+	b = appendString(b, protoUserID, t.UserID)
+	b = appendString(b, protoSymbol, t.Symbol)
+	b = appendDouble(b, protoAmount, t.Amount)
+	b = appendDouble(b, protoPrice, t.Price)
+	b = appendString(b, protoTradeType, string(t.Type))
+	if !t.Timestamp.IsZero() {
+		b = protowire.AppendTag(b, protoTimestamp, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(t.Timestamp.UnixNano()))
+	}
+	for _, condition := range t.Conditions {
+		b = protowire.AppendTag(b, protoConditions, protowire.BytesType)
+		b = protowire.AppendString(b, string(condition))
+	}
+	b = appendString(b, protoLiquidity, string(t.Liquidity))
+	b = appendString(b, protoTraderType, t.TraderType)
+	b = appendString(b, protoSector, t.Sector)
+	b = appendString(b, protoAssetClass, t.AssetClass)
+	if t.Cancelled {
+		b = protowire.AppendTag(b, protoCancelled, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	if t.Seq != 0 {
+		b = protowire.AppendTag(b, protoSeq, protowire.VarintType)
+		b = protowire.AppendVarint(b, t.Seq)
+	}
+	return b
+}
+
+// UnmarshalProto decodes a Trade message. Timestamps come back in UTC, and
+// unknown fields are skipped so older readers accept newer payloads.
+func UnmarshalProto(data []byte) (*Trade, error) {
+	t := &Trade{Trade: &models.Trade{}}
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid trade payload: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid trade field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+			if err := t.setBytes(num, v); err != nil {
+				return nil, err
+			}
+		case protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid trade field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+			switch num {
+			case protoAmount:
+				t.Amount = math.Float64frombits(v)
+			case protoPrice:
+				t.Price = math.Float64frombits(v)
+			}
+		case protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid trade field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+			switch num {
+			case protoTimestamp:
+				t.Timestamp = time.Unix(0, int64(v)).UTC()
+			case protoCancelled:
+				t.Cancelled = v != 0
+			case protoSeq:
+				t.Seq = v
+			}
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return nil, fmt.Errorf("invalid trade field %d: %w", num, protowire.ParseError(n))
+			}
+			data = data[n:]
+		}
+	}
+	return t, nil
+}
+
+// setBytes sets a length-delimited field of the Trade message
+func (t *Trade) setBytes(num protowire.Number, v []byte) error {
+	switch num {
+	case protoID:
+		id, err := uuid.FromBytes(v)
+		if err != nil {
+			return fmt.Errorf("invalid trade id: %w", err)
+		}
+		t.ID = id
+	case protoUserID:
+		t.UserID = string(v)
+	case protoSymbol:
+		t.Symbol = string(v)
+	case protoTradeType:
+		t.Type = models.TradeType(v)
+	case protoConditions:
+		t.Conditions = append(t.Conditions, Condition(v))
+	case protoLiquidity:
+		t.Liquidity = Liquidity(v)
+	case protoTraderType:
+		t.TraderType = string(v)
+	case protoSector:
+		t.Sector = string(v)
+	case protoAssetClass:
+		t.AssetClass = string(v)
+	}
+	return nil
+}
+
+// appendString appends a string field, omitting it when empty
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendDouble appends a double field, omitting it when zero
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
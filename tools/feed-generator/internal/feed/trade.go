@@ -0,0 +1,175 @@
+package feed
+
+import (
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/google/uuid"
+)
+
+// Condition represents a sale condition code attached to a trade print
+type Condition string
+
+const (
+	OddLot        Condition = "ODD_LOT"
+	ExtendedHours Condition = "EXTENDED_HOURS"
+)
+
+// Liquidity marks whether a trade took liquidity (crossed the spread) or provided it
+type Liquidity string
+
+const (
+	Aggressive Liquidity = "AGGRESSIVE"
+	Passive    Liquidity = "PASSIVE"
+)
+
+// DefaultRoundLotSize is the number of shares in a standard round lot
+const DefaultRoundLotSize = 100
+
+// AmountMode is the unit of a trade's Amount
+type AmountMode string
+
+const (
+	AmountShares   AmountMode = "shares"   // Amount is a share quantity; notional is Amount * Price
+	AmountNotional AmountMode = "notional" // Amount is a dollar value; shares are Amount / Price
+)
+
+// Shares converts an amount at the given price to a share quantity
+func (m AmountMode) Shares(amount, price float64) float64 {
+	if m == AmountNotional && price > 0 {
+		return amount / price
+	}
+	return amount
+}
+
+// Amount converts a share quantity at the given price to an amount
+func (m AmountMode) Amount(shares, price float64) float64 {
+	if m == AmountNotional {
+		return shares * price
+	}
+	return shares
+}
+
+// Notional returns the dollar value of an amount at the given price
+func (m AmountMode) Notional(amount, price float64) float64 {
+	if m == AmountNotional {
+		return amount
+	}
+	return amount * price
+}
+
+// Session is the regular trading session; trades printed outside it are
+// extended hours
+type Session struct {
+	Location *time.Location // Zone Open and Close are read in
+	Open     time.Duration  // Offset from midnight
+	Close    time.Duration  // Offset from midnight; earlier than Open wraps past midnight
+}
+
+// DefaultSession returns the 9:30 AM - 4:00 PM session in location
+func DefaultSession(location *time.Location) Session {
+	return Session{Location: location, Open: 9*time.Hour + 30*time.Minute, Close: 16 * time.Hour}
+}
+
+// Trade wraps a core trade with feed-level annotations that the
+// detection system's model does not carry. The unit of the core trade's
+// Amount is set by the generator's AmountMode: shares by default, or dollars
+// in notional mode.
+type Trade struct {
+	*models.Trade
+	Conditions []Condition `json:"conditions,omitempty"`
+	Liquidity  Liquidity   `json:"liquidity,omitempty"`
+	TraderType string      `json:"trader_type,omitempty"` // Generating profile archetype, only set when tagging is enabled
+	Sector     string      `json:"sector,omitempty"`      // Symbol's sector, only set when sector tagging is enabled
+	AssetClass string      `json:"asset_class,omitempty"` // Symbol's asset class, only set when sector tagging is enabled
+	Cancelled  bool        `json:"cancelled,omitempty"`   // Order was cancelled before execution (spoofing layers)
+	Seq        uint64      `json:"seq,omitempty"`         // Publish sequence number, only set when sequencing is enabled
+}
+
+// NewTrade wraps a core trade and derives its sale conditions
+func NewTrade(trade *models.Trade, roundLotSize float64, mode AmountMode, session Session) *Trade {
+	t := &Trade{Trade: trade}
+	t.Classify(roundLotSize, mode, session)
+	return t
+}
+
+// Classify recomputes the trade's conditions from its size and timestamp.
+// Trades smaller than roundLotSize shares are odd lots, and trades outside
+// session are extended hours.
+func (t *Trade) Classify(roundLotSize float64, mode AmountMode, session Session) {
+	t.Conditions = nil
+	if IsExtendedHours(t.Timestamp, session) {
+		t.Conditions = append(t.Conditions, ExtendedHours)
+	}
+	if mode.Shares(t.Amount, t.Price) < roundLotSize {
+		t.Conditions = append(t.Conditions, OddLot)
+	}
+}
+
+// HasCondition reports whether the trade carries the given condition
+func (t *Trade) HasCondition(c Condition) bool {
+	for _, condition := range t.Conditions {
+		if condition == c {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExtendedHours reports whether a timestamp falls outside the regular
+// session, read in the session's zone rather than the timestamp's. The
+// session only runs on weekdays, so weekend trades are all extended hours.
+func IsExtendedHours(ts time.Time, session Session) bool {
+	if session.Location != nil {
+		ts = ts.In(session.Location)
+	}
+	if ts.Weekday() == time.Saturday || ts.Weekday() == time.Sunday {
+		return true
+	}
+	offset := time.Duration(ts.Hour())*time.Hour + time.Duration(ts.Minute())*time.Minute
+	if session.Open < session.Close {
+		return offset < session.Open || offset >= session.Close
+	}
+	return offset < session.Open && offset >= session.Close
+}
+
+// HaltStatus is the change a halt marker announces
+type HaltStatus string
+
+const (
+	Halted  HaltStatus = "HALTED"
+	Resumed HaltStatus = "RESUMED"
+)
+
+// Halt marks a symbol's trading halt starting or ending
+type Halt struct {
+	Symbol    string     `json:"symbol"`
+	Status    HaltStatus `json:"status"`
+	Price     float64    `json:"price,omitempty"` // Trade price that tripped the halt
+	Move      float64    `json:"move,omitempty"`  // Fractional move that tripped the halt (0.1 = up 10%)
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+// Label is the ground truth for one published fraud pattern or normal trade
+type Label struct {
+	TradeIDs   []uuid.UUID `json:"trade_ids"`
+	FraudType  string      `json:"fraud_type"` // NONE for normal trades
+	UserID     string      `json:"user_id"`
+	InjectedAt time.Time   `json:"injected_at"`
+}
+
+// NewLabel labels a group of trades generated together
+func NewLabel(trades []*Trade, fraudType string, injectedAt time.Time) *Label {
+	label := &Label{
+		TradeIDs:   make([]uuid.UUID, len(trades)),
+		FraudType:  fraudType,
+		InjectedAt: injectedAt,
+	}
+	for i, trade := range trades {
+		label.TradeIDs[i] = trade.ID
+	}
+	if len(trades) > 0 {
+		label.UserID = trades[0].UserID
+	}
+	return label
+}
@@ -0,0 +1,66 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+)
+
+func TestIsExtendedHoursUsesSessionZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	session := DefaultSession(newYork)
+
+	tests := []struct {
+		name string
+		ts   time.Time
+		want bool
+	}{
+		// 13:00 New York is 17:00 UTC, which a UTC-local check called extended
+		{"midday stamped in UTC", time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC), false},
+		{"before open", time.Date(2026, 3, 2, 9, 29, 0, 0, newYork), true},
+		{"at open", time.Date(2026, 3, 2, 9, 30, 0, 0, newYork), false},
+		{"at close", time.Date(2026, 3, 2, 16, 0, 0, 0, newYork), true},
+		{"saturday midday", time.Date(2026, 3, 7, 12, 0, 0, 0, newYork), true},
+		// Friday 21:00 New York is already Saturday in UTC
+		{"friday afternoon stamped in UTC", time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC), true},
+		{"friday midday", time.Date(2026, 3, 6, 12, 0, 0, 0, newYork), false},
+	}
+	for _, tt := range tests {
+		if got := IsExtendedHours(tt.ts, session); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsExtendedHoursOvernightSession(t *testing.T) {
+	session := Session{Location: time.UTC, Open: 22 * time.Hour, Close: 4 * time.Hour}
+	for hour, want := range map[int]bool{23: false, 2: false, 4: true, 12: true} {
+		ts := time.Date(2026, 3, 2, hour, 0, 0, 0, time.UTC)
+		if got := IsExtendedHours(ts, session); got != want {
+			t.Errorf("%02d:00: got %v, want %v", hour, got, want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	session := DefaultSession(time.UTC)
+	trade := NewTrade(&models.Trade{
+		Amount:    50,
+		Price:     10,
+		Timestamp: time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC),
+	}, DefaultRoundLotSize, AmountShares, session)
+	if !trade.HasCondition(ExtendedHours) || !trade.HasCondition(OddLot) {
+		t.Errorf("want extended hours odd lot, got %v", trade.Conditions)
+	}
+
+	trade.Amount = 2000 // $2000 notional is 200 shares at $10
+	trade.Timestamp = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	trade.Classify(DefaultRoundLotSize, AmountNotional, session)
+	if len(trade.Conditions) != 0 {
+		t.Errorf("want no conditions, got %v", trade.Conditions)
+	}
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+)
+
+const (
+	// finalFlushTimeout bounds the drain of in-flight and buffered trades on shutdown
+	finalFlushTimeout = 5 * time.Second
+
+	// maxPublishBackoff caps the delay between publish retries
+	maxPublishBackoff = 5 * time.Second
+)
+
+// pendingGroup is a normal trade or fraud pattern waiting to be flushed to the sink
+type pendingGroup struct {
+	trades     []*feed.Trade
+	profile    *profiles.TraderProfile
+	isFraud    bool
+	injectedAt time.Time
+}
+
+// enqueue buffers the trades of one normal trade or fraud pattern and flushes
+// once the batch size is reached. A pattern is never split across flushes, so
+// its trades stay contiguous in the stream.
+func (g *Generator) enqueue(ctx context.Context, trades []*feed.Trade, profile *profiles.TraderProfile, isFraud bool) error {
+	g.pending = append(g.pending, pendingGroup{
+		trades:     trades,
+		profile:    profile,
+		isFraud:    isFraud,
+		injectedAt: g.clock.Now(),
+	})
+	g.pendingTrades += len(trades)
+	g.generated += int64(len(trades))
+
+	if g.pendingTrades < g.cfg.Generate.BatchSize {
+		return nil
+	}
+	return g.flush(ctx)
+}
+
+// flush publishes all buffered trades, handing them to the publisher pool
+// when one is running
+func (g *Generator) flush(ctx context.Context) error {
+	if len(g.pending) == 0 {
+		return nil
+	}
+
+	groups := g.pending
+	g.pending = nil
+	g.pendingTrades = 0
+
+	if g.publishers != nil {
+		return g.publishers.submit(ctx, groups)
+	}
+	return g.publish(ctx, groups)
+}
+
+// publish writes a batch of groups in one round trip, then their labels.
+// Trades still unpublished after the configured retries are dropped. Safe to
+// call from several publisher workers at once.
+func (g *Generator) publish(ctx context.Context, groups []pendingGroup) error {
+	var trades []*feed.Trade
+	for _, group := range groups {
+		trades = append(trades, group.trades...)
+	}
+	if g.cfg.Generate.SequenceNumbers {
+		g.assignSequence(trades)
+	}
+
+	published, publishErr := g.publishWithRetry(ctx, trades)
+
+	// Account for what reached the sink; a group is only labelled if all of its trades did
+	remaining := published
+	complete := 0
+	for _, group := range groups {
+		n := min(remaining, len(group.trades))
+		for _, trade := range group.trades[:n] {
+			g.updateStats(trade, group.profile, group.isFraud)
+			if slog.Default().Enabled(ctx, slog.LevelDebug) {
+				logTrade(trade, group)
+			}
+		}
+		remaining -= n
+		if n < len(group.trades) {
+			break
+		}
+		complete++
+	}
+
+	if g.labels != nil && complete > 0 {
+		if err := g.publishLabels(ctx, groups[:complete]); err != nil {
+			return err
+		}
+	}
+
+	if publishErr != nil {
+		g.stats.DroppedTrades.Add(int64(len(trades) - published))
+		return fmt.Errorf("failed to publish %d of %d trades: %w", len(trades)-published, len(trades), publishErr)
+	}
+	return nil
+}
+
+// assignSequence numbers trades contiguously in emission order, reserving the
+// block atomically so concurrent publishers never share numbers. Retries keep
+// the numbers, and dropped trades leave a gap for consumers to detect.
+func (g *Generator) assignSequence(trades []*feed.Trade) {
+	first := g.seq.Add(uint64(len(trades))) - uint64(len(trades)) + 1
+	for i, trade := range trades {
+		trade.Seq = first + uint64(i)
+	}
+}
+
+// publishWithRetry publishes trades in order, retrying failures with jittered
+// exponential backoff. A partly published batch resumes after the trades the
+// sink accepted, so retries never reorder or duplicate them. It returns how
+// many trades were published.
+func (g *Generator) publishWithRetry(ctx context.Context, trades []*feed.Trade) (int, error) {
+	published := 0
+	backoff := g.cfg.Generate.PublishBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := g.sink.PublishBatch(ctx, trades[published:])
+		if err == nil {
+			return len(trades), nil
+		}
+
+		var batchErr *sink.BatchError
+		if errors.As(err, &batchErr) {
+			published += batchErr.Published
+		}
+		if attempt >= g.cfg.Generate.PublishRetries {
+			return published, err
+		}
+
+		// Sleep between half and all of the backoff so retries from a blip don't line up
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return published, err
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, maxPublishBackoff)
+		g.stats.PublishRetries.Add(1)
+	}
+}
+
+// publishLabels writes ground-truth labels for published groups: one per
+// fraud pattern and, if enabled, one per normal trade
+func (g *Generator) publishLabels(ctx context.Context, groups []pendingGroup) error {
+	var labels []*feed.Label
+	for _, group := range groups {
+		fraudType := string(profiles.NoFraud)
+		if group.isFraud {
+			fraudType = string(group.profile.FraudPattern)
+		} else if !g.cfg.Generate.NegativeLabels {
+			continue
+		}
+		labels = append(labels, feed.NewLabel(group.trades, fraudType, group.injectedAt))
+	}
+
+	if len(labels) == 0 {
+		return nil
+	}
+	if err := g.labels.PublishLabels(ctx, labels); err != nil {
+		return fmt.Errorf("failed to publish %d labels: %w", len(labels), err)
+	}
+	g.stats.LabelsWritten.Add(int64(len(labels)))
+	return nil
+}
+
+// flushRemaining publishes whatever is still buffered when generation stops,
+// draining the publisher pool if one is running. ctx is the drain context,
+// which survives shutdown; the flush also gets its own deadline for runs that
+// end by duration.
+func (g *Generator) flushRemaining(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, finalFlushTimeout)
+	defer cancel()
+
+	if err := g.flush(ctx); err != nil {
+		logError("flushing trades failed", "Error flushing trades", err)
+	}
+	if g.publishers != nil {
+		g.publishers.stop(ctx)
+	}
+}
+
+// logTrade logs a published trade at debug level
+func logTrade(trade *feed.Trade, group pendingGroup) {
+	attrs := []any{
+		"trade_id", trade.ID,
+		"user_id", trade.UserID,
+		"symbol", trade.Symbol,
+		"side", trade.Type,
+		"amount", trade.Amount,
+		"price", trade.Price,
+		"timestamp", trade.Timestamp,
+	}
+	if group.isFraud {
+		slog.Debug("fraud trade published", append(attrs,
+			"fraud_type", group.profile.FraudPattern,
+			logging.Text("[%s] 🚨 FRAUD %s: %s %.2f @ $%.2f (%s)%s",
+				trade.Timestamp.Format("15:04:05"),
+				group.profile.FraudPattern,
+				trade.Type,
+				trade.Amount,
+				trade.Price,
+				trade.Symbol,
+				formatConditions(trade.Conditions),
+			))...)
+		return
+	}
+
+	slog.Debug("trade published", append(attrs,
+		logging.Text("[%s] %s: %s %.2f @ $%.2f (%s)%s",
+			trade.Timestamp.Format("15:04:05"),
+			trade.UserID,
+			trade.Type,
+			trade.Amount,
+			trade.Price,
+			trade.Symbol,
+			formatConditions(trade.Conditions),
+		))...)
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// liveSettings holds the generation parameters that can change while the
+// generator runs. The generation loop reads them every tick; tpsChanged wakes
+// it to reset the ticker.
+type liveSettings struct {
+	tps        atomic.Int64
+	fraudRate  atomic.Uint64 // math.Float64bits of the global fraud rate
+	tpsChanged chan struct{}
+}
+
+// TPS returns the base trades per second, before any volume curve
+func (g *Generator) TPS() int {
+	return int(g.live.tps.Load())
+}
+
+// SetTPS changes the base trades per second of a running generator. It is
+// rejected while a rate schedule or the stream depth controller sets the rate.
+func (g *Generator) SetTPS(tps int) error {
+	if tps < 1 || tps > maxTPS {
+		return fmt.Errorf("tps must be between 1 and %d, got %d", maxTPS, tps)
+	}
+	if g.schedule != nil {
+		return fmt.Errorf("tps follows the rate schedule and cannot be set")
+	}
+	if g.cfg.Generate.TargetStreamLength > 0 {
+		return fmt.Errorf("tps is steered by the target stream length and cannot be set")
+	}
+
+	g.live.tps.Store(int64(tps))
+	select {
+	case g.live.tpsChanged <- struct{}{}:
+	default: // A reset is already pending and will pick up the new rate
+	}
+	return nil
+}
+
+// FraudRate returns the global fraud rate; fraud windows override it
+func (g *Generator) FraudRate() float64 {
+	return math.Float64frombits(g.live.fraudRate.Load())
+}
+
+// SetFraudRate changes the global fraud rate of a running generator, checking
+// the profiles can serve it the same way they are checked at startup
+func (g *Generator) SetFraudRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("fraud rate must be between 0.0 and 1.0, got %.2f", rate)
+	}
+	if g.cfg.Generate.FraudOnly {
+		return fmt.Errorf("fraud rate is fixed at 1.0 in fraud-only mode")
+	}
+	if g.cfg.Generate.FraudEvery > 0 {
+		return fmt.Errorf("fraud rate is unused in fraud-every mode")
+	}
+	if g.cfg.Generate.FraudLagGroup != "" {
+		return fmt.Errorf("fraud rate is steered by consumer group lag and cannot be set")
+	}
+	if err := g.checkProfilesAt(rate); err != nil {
+		return err
+	}
+
+	g.live.fraudRate.Store(math.Float64bits(rate))
+	return nil
+}
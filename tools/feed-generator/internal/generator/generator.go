@@ -2,106 +2,558 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
-	"github.com/gauravdhanuka4/trade-detection-system/internal/redis"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/patterns"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
-	"github.com/google/uuid"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
 )
 
+const (
+	// maxTPS is the upper bound accepted by config validation
+	maxTPS = 10000
+
+	// streamDepthCheckInterval is how often the stream depth controller samples the stream
+	streamDepthCheckInterval = time.Second
+	// fraudLagSmoothing is the fraction of the way each consumer lag reading
+	// moves the fraud rate toward its target, so one spike doesn't swing it
+	fraudLagSmoothing = 0.5
+)
+
+// errStalled is the cancellation cause when the stall watchdog aborts generation
+var errStalled = errors.New("generator stalled")
+
 // Generator handles trade feed generation
 type Generator struct {
 	cfg              *config.Config
-	redisClient      redis.RedisClient
+	sink             sink.Sink
+	labels           sink.LabelSink // nil unless ground-truth labels are enabled
 	profiles         []profiles.TraderProfile
+	allFraudTypes    []profiles.FraudType // Fraud types with a backing profile, drawn from for ALL
 	patternGenerator *patterns.PatternGenerator
+	rng              *rand.Rand   // Trade content, seeded by generate.seed
+	timing           *rand.Rand   // Timestamp draws, seeded by generate.timing_seed
+	clock            clock.Clock  // Trade time; tick pacing and statistics stay on the wall clock
+	startedAt        time.Time    // Clock time the run is measured from, for schedules and fraud windows
+	news             []*newsEvent // Scheduled news events, in config order
+	session          *session     // nil unless market-hours mode is enabled
+	volume           *volumeCurve // nil for a flat volume profile
+	schedule         rateSchedule // nil unless a ramp or step schedule is set
+	positions        *positions   // nil unless positions are enforced
+	halts            *haltTracker // nil unless circuit-breaker halts are enabled
+	lastArrival      time.Time    // Previous jittered normal trade time, for Poisson arrivals
+	ticks            int          // Ticks generated so far, for fraud-every mode
+	generated        int64        // Trades enqueued so far, for the max-trades cap
+	live             liveSettings
+	seq              atomic.Uint64 // Last assigned sequence number
+	userSessions     *userSessions // nil unless normal trades cluster into trader sessions
+	pending          []pendingGroup
+	pendingTrades    int
+	publishers       *publisherPool // nil when publishing inline (one worker)
 	stats            *Statistics
 }
 
-// Statistics tracks generation statistics
+// Statistics tracks generation statistics. Counters are atomic; mu guards the
+// per-key maps so publishers can insert while others read.
 type Statistics struct {
-	TotalTrades     atomic.Int64
-	FraudPatterns   atomic.Int64
-	VolumeGenerated atomic.Uint64 // In cents to avoid float precision issues
-	ByProfile       map[string]*atomic.Int64
-	BySymbol        map[string]*atomic.Int64
-	StartTime       time.Time
+	TotalTrades      atomic.Int64
+	FraudPatterns    atomic.Int64
+	VolumeGenerated  atomic.Uint64 // In cents to avoid float precision issues
+	mu               sync.RWMutex
+	byProfile        map[string]*atomic.Int64 // Per-key counters, only reached through mu (see counter and snapshot)
+	bySymbol         map[string]*atomic.Int64
+	byUser           map[string]*atomic.Int64
+	UniqueSymbols    atomic.Int64 // Cardinalities, readable without taking mu
+	UniqueAccounts   atomic.Int64
+	SkewedTimestamps atomic.Int64 // Trades published with a fault-injected timestamp
+	LastPublish      atomic.Int64 // UnixNano of the last successful publish
+	LabelsWritten    atomic.Int64
+	PublishRetries   atomic.Int64 // Publish attempts repeated after a sink error
+	DroppedTrades    atomic.Int64 // Trades abandoned after exhausting retries or overflowing a buffer
+	Halts            atomic.Int64 // Circuit-breaker halts tripped
+	HaltedTrades     atomic.Int64 // Trades suppressed on halted symbols
+	CancelledOrders  atomic.Int64 // Published records with Cancelled set, from any source
+	requestedTPS     atomic.Int64 // Target rate at the last stats report
+	intervalTrades   atomic.Int64 // Trades published in the last stats interval
+	intervalNanos    atomic.Int64 // Length of the last stats interval
+	StartTime        time.Time
+	sizes            *sizeHistogram // Trade amounts, in the configured amount mode's units
 }
 
-// NewGenerator creates a new trade generator
-func NewGenerator(cfg *config.Config, redisClient redis.RedisClient) *Generator {
-	return &Generator{
+// NewGenerator creates a new trade generator over the given trader profiles.
+// labels may be nil to skip ground-truth labels.
+func NewGenerator(cfg *config.Config, traderProfiles []profiles.TraderProfile, out sink.Sink, labels sink.LabelSink) *Generator {
+	if n := cfg.Generate.SyntheticSymbols; n > 0 {
+		profiles.AssignSymbols(traderProfiles, profiles.SyntheticSymbols(n))
+	}
+	if symbols := cfg.Generate.Symbols; len(symbols) > 0 {
+		profiles.RestrictSymbols(traderProfiles, symbols)
+	}
+
+	rng := newSource(cfg.Generate.Seed)
+	timing := newSource(cfg.Generate.TimingSeed)
+
+	byProfile := make(map[string]*atomic.Int64)
+	for _, profile := range traderProfiles {
+		byProfile[string(profile.Type)] = &atomic.Int64{}
+	}
+
+	patternGenerator := patterns.NewPatternGenerator(cfg, rng, timing)
+	patternGenerator.SetVictims(traderProfiles)
+
+	g := &Generator{
 		cfg:              cfg,
-		redisClient:      redisClient,
-		profiles:         profiles.GetDefaultProfiles(),
-		patternGenerator: patterns.NewPatternGenerator(),
+		sink:             out,
+		labels:           labels,
+		profiles:         traderProfiles,
+		patternGenerator: patternGenerator,
+		rng:              rng,
+		timing:           timing,
+		clock:            clock.Real{},
+		startedAt:        time.Now(),
+		session:          newSession(cfg),
+		userSessions:     newUserSessions(cfg),
+		volume:           newVolumeCurve(cfg),
+		schedule:         newRateSchedule(cfg),
+		positions:        newPositions(cfg),
+		halts:            newHaltTracker(cfg.Generate.HaltThreshold, cfg.Generate.HaltWindow, cfg.Generate.HaltDuration),
+		news:             newNewsEvents(cfg),
 		stats: &Statistics{
-			ByProfile: make(map[string]*atomic.Int64),
-			BySymbol:  make(map[string]*atomic.Int64),
+			byProfile: byProfile,
+			bySymbol:  make(map[string]*atomic.Int64),
+			byUser:    make(map[string]*atomic.Int64),
+			sizes:     newSizeHistogram(cfg.Generate.SizeBuckets),
 			StartTime: time.Now(),
 		},
 	}
+	g.live.tps.Store(int64(cfg.Generate.TPS))
+	g.live.fraudRate.Store(math.Float64bits(cfg.Generate.FraudRate))
+	g.live.tpsChanged = make(chan struct{}, 1)
+
+	for _, fraudType := range patternGenerator.FraudTypes() {
+		if len(profiles.FilterFraudProfiles(traderProfiles, fraudType)) > 0 {
+			g.allFraudTypes = append(g.allFraudTypes, fraudType)
+		}
+	}
+
+	// Trades the sink accepted but later lost still count as dropped
+	if reporter, ok := out.(sink.DropReporter); ok {
+		reporter.OnDrop(func(n int) { g.stats.DroppedTrades.Add(int64(n)) })
+	}
+	return g
+}
+
+// SetClock replaces the generator's time source and restarts simulated time
+// from the clock's current time. Call it before Run.
+func (g *Generator) SetClock(c clock.Clock) {
+	g.clock = c
+	g.startedAt = c.Now()
+}
+
+// newSource returns a seeded random source. A zero seed picks a fresh seed so
+// draws vary from run to run.
+func newSource(seed int64) *rand.Rand {
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	return rand.New(rand.NewSource(seed))
 }
 
 // Run starts the trade generation process
 func (g *Generator) Run(ctx context.Context) error {
-	fmt.Printf("\n🚀 Starting Trade Feed Generator...\n")
-	fmt.Printf("Configuration:\n")
-	fmt.Printf("  Redis: %s\n", g.cfg.RedisAddress())
-	fmt.Printf("  Stream: trades:stream\n")
-	fmt.Printf("  Throughput: %d trades/sec\n", g.cfg.Generate.TPS)
-	fmt.Printf("  Duration: %v\n", g.cfg.Generate.Duration)
-	fmt.Printf("  Fraud Rate: %.1f%%\n\n", g.cfg.Generate.FraudRate*100)
-
-	// Initialize profile counters
-	for _, profile := range g.profiles {
-		g.stats.ByProfile[string(profile.Type)] = &atomic.Int64{}
+	// Fail fast instead of erroring on every tick
+	if err := g.checkProfiles(); err != nil {
+		return err
+	}
+	g.warnUnpricedSymbols()
+	g.warnUnbackedFraudTypes()
+	g.warnUnmarkedHalts()
+
+	if path := g.cfg.Generate.Resume; path != "" {
+		if err := g.loadSnapshot(path); err != nil {
+			return err
+		}
 	}
 
+	g.printBanner()
+
+	// Abort instead of running on silently if the sink stops accepting trades
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	g.stats.LastPublish.Store(time.Now().UnixNano())
+	if g.cfg.Generate.StallTimeout > 0 {
+		go g.watchStall(ctx, cancel)
+	}
+
+	// Publishes run on a context that outlives shutdown by the drain timeout,
+	// so a batch or fraud pattern in flight when the signal arrives lands whole
+	// instead of leaving half a pattern in the stream
+	drainCtx, abortDrain := context.WithCancel(context.WithoutCancel(ctx))
+	defer abortDrain()
+	context.AfterFunc(ctx, func() { time.AfterFunc(finalFlushTimeout, abortDrain) })
+
 	// Start statistics reporter
 	go g.reportStats(ctx)
 
+	if g.cfg.Generate.Workers > 1 {
+		g.startPublishers(ctx)
+	}
+
 	// Calculate tick interval for desired TPS
-	tickInterval := time.Second / time.Duration(g.cfg.Generate.TPS)
+	tps := g.currentTPS(g.clock.Now())
+	tickInterval := time.Second / time.Duration(tps)
 	ticker := time.NewTicker(tickInterval)
 	defer ticker.Stop()
 
+	// Periodically steer TPS toward the target stream depth if enabled
+	var depthCheck <-chan time.Time
+	if g.cfg.Generate.TargetStreamLength > 0 {
+		depthTicker := time.NewTicker(streamDepthCheckInterval)
+		defer depthTicker.Stop()
+		depthCheck = depthTicker.C
+	}
+
+	// Periodically steer the fraud rate by consumer group lag if enabled
+	var lagCheck <-chan time.Time
+	if g.cfg.Generate.FraudLagGroup != "" {
+		lagTicker := time.NewTicker(streamDepthCheckInterval)
+		defer lagTicker.Stop()
+		lagCheck = lagTicker.C
+	}
+
+	// Periodically save the run state, and once more on the way out
+	var snapshotTick <-chan time.Time
+	if g.cfg.Generate.SnapshotFile != "" {
+		snapshotTicker := time.NewTicker(g.cfg.Generate.SnapshotInterval)
+		defer snapshotTicker.Stop()
+		snapshotTick = snapshotTicker.C
+		defer g.logSnapshot()
+	}
+
+	// Flush partial batches so trades don't wait indefinitely at low TPS
+	var flushTick <-chan time.Time
+	if g.cfg.Generate.BatchSize > 1 {
+		flushTicker := time.NewTicker(g.cfg.Generate.FlushInterval)
+		defer flushTicker.Stop()
+		flushTick = flushTicker.C
+	}
+
 	// Set deadline if duration is specified
 	var deadline time.Time
 	if g.cfg.Generate.Duration > 0 {
-		deadline = time.Now().Add(g.cfg.Generate.Duration)
+		deadline = g.clock.Now().Add(g.cfg.Generate.Duration)
 	}
 
+	// Show progress toward the deadline on stderr, so stdout stays clean
+	stopProgress := func() {}
+	if g.cfg.Generate.Progress {
+		stopProgress = sync.OnceFunc(g.startProgress(ctx, os.Stderr, deadline))
+	}
+	defer stopProgress()
+
 	// Generation loop
 	for {
 		select {
 		case <-ctx.Done():
+			stopProgress()
+			g.flushRemaining(drainCtx)
+			if cause := context.Cause(ctx); errors.Is(cause, errStalled) {
+				g.printFinalStats()
+				return cause
+			}
 			return g.printFinalStats()
 		case <-ticker.C:
 			// Check deadline
-			if !deadline.IsZero() && time.Now().After(deadline) {
+			if !deadline.IsZero() && g.clock.Now().After(deadline) {
+				stopProgress()
+				g.flushRemaining(drainCtx)
 				return g.printFinalStats()
 			}
 
+			// Follow the rate schedule or intraday volume curve
+			if newTPS := g.currentTPS(g.clock.Now()); newTPS != tps {
+				tps = newTPS
+				ticker.Reset(time.Second / time.Duration(tps))
+			}
+
+			// Move prices forward by one tick of simulated time, so seeded runs
+			// walk the same path regardless of scheduling jitter
+			g.patternGenerator.StepPrices(time.Second / time.Duration(tps))
+
 			// Generate and publish trade(s)
-			if err := g.generateAndPublish(ctx); err != nil {
-				fmt.Printf("Error generating trade: %v\n", err)
+			if err := g.generateAndPublish(drainCtx); err != nil {
+				logError("generating trade failed", "Error generating trade", err)
+			}
+
+			// Stop at the trade cap; a fraud pattern crossing it lands whole
+			if limit := g.cfg.Generate.MaxTrades; limit > 0 && g.generated >= limit {
+				stopProgress()
+				g.flushRemaining(drainCtx)
+				return g.printFinalStats()
+			}
+		case <-flushTick:
+			if err := g.flush(drainCtx); err != nil {
+				logError("generating trade failed", "Error generating trade", err)
+			}
+		case <-g.live.tpsChanged:
+			if newTPS := g.currentTPS(g.clock.Now()); newTPS != tps {
+				tps = newTPS
+				ticker.Reset(time.Second / time.Duration(tps))
 			}
+		case <-depthCheck:
+			if newTPS := g.adjustTPSForStreamDepth(ctx, tps); newTPS != tps {
+				tps = newTPS
+				ticker.Reset(time.Second / time.Duration(tps))
+			}
+		case <-lagCheck:
+			g.adjustFraudRateForLag(ctx)
+		case <-snapshotTick:
+			g.logSnapshot()
 		}
 	}
 }
 
+// printBanner logs the run configuration
+func (g *Generator) printBanner() {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n🚀 Starting Trade Feed Generator...\n")
+	fmt.Fprintf(&b, "Configuration:\n")
+	if g.cfg.Generate.DryRun {
+		fmt.Fprintf(&b, "  Output: dry run (trades are discarded)\n")
+	} else {
+		for _, backend := range g.cfg.OutputBackends() {
+			switch backend {
+			case "file":
+				fmt.Fprintf(&b, "  Output: %s (%s)\n", g.cfg.Generate.OutputFile, g.cfg.Generate.OutputFormat)
+			case "ws":
+				fmt.Fprintf(&b, "  WebSocket: %s\n", g.cfg.Generate.WSAddr)
+			case "kafka":
+				fmt.Fprintf(&b, "  Kafka: %s\n", g.cfg.KafkaAddress())
+				fmt.Fprintf(&b, "  Topic: %s (acks=%s)\n", g.cfg.Kafka.Topic, g.cfg.Kafka.RequiredAcks)
+			default:
+				fmt.Fprintf(&b, "  Redis: %s\n", g.cfg.RedisAddress())
+				fmt.Fprintf(&b, "  Stream: %s\n", g.cfg.Generate.Stream)
+				if g.cfg.Generate.StreamMaxLen > 0 {
+					fmt.Fprintf(&b, "  Stream Cap: ~%d entries\n", g.cfg.Generate.StreamMaxLen)
+				}
+			}
+		}
+	}
+	switch {
+	case g.cfg.Generate.Schedule != "":
+		fmt.Fprintf(&b, "  Throughput: schedule %s\n", g.cfg.Generate.Schedule)
+	case g.schedule != nil:
+		fmt.Fprintf(&b, "  Throughput: ramp %d → %d trades/sec\n", g.cfg.Generate.RampFrom, g.cfg.Generate.RampTo)
+	default:
+		fmt.Fprintf(&b, "  Throughput: %d trades/sec\n", g.cfg.Generate.TPS)
+	}
+	if g.volume != nil {
+		fmt.Fprintf(&b, "  Volume Profile: %s (throughput is the daily average)\n", g.cfg.Generate.VolumeProfile)
+	}
+	if g.cfg.Generate.BatchSize > 1 {
+		fmt.Fprintf(&b, "  Batching: %d trades or every %v\n", g.cfg.Generate.BatchSize, g.cfg.Generate.FlushInterval)
+	}
+	if g.cfg.Generate.Workers > 1 {
+		fmt.Fprintf(&b, "  Publishers: %d workers\n", g.cfg.Generate.Workers)
+	}
+	if g.cfg.Generate.TargetStreamLength > 0 {
+		fmt.Fprintf(&b, "  Target Stream Length: %d (TPS adjusts to match consumer)\n", g.cfg.Generate.TargetStreamLength)
+	}
+	if g.session != nil {
+		fmt.Fprintf(&b, "  Market Hours: %s-%s %s\n", g.cfg.Session.Open, g.cfg.Session.Close, g.cfg.Session.Timezone)
+	}
+	fmt.Fprintf(&b, "  Duration: %v\n", g.cfg.Generate.Duration)
+	if g.cfg.Generate.MaxTrades > 0 {
+		fmt.Fprintf(&b, "  Max Trades: %d\n", g.cfg.Generate.MaxTrades)
+	}
+	if g.cfg.Generate.FraudOnly {
+		fmt.Fprintln(&b, "  Fraud Rate: fraud only")
+	} else if g.cfg.Generate.FraudEvery > 0 {
+		fmt.Fprintf(&b, "  Fraud Rate: every %d ticks\n", g.cfg.Generate.FraudEvery)
+	} else if g.cfg.Generate.FraudLagGroup != "" {
+		fmt.Fprintf(&b, "  Fraud Rate: %.1f%%-%.1f%% as %s lags up to %d\n", g.cfg.Generate.FraudRate*100,
+			g.cfg.Generate.FraudRateMax*100, g.cfg.Generate.FraudLagGroup, g.cfg.Generate.FraudLagMax)
+	} else {
+		fmt.Fprintf(&b, "  Fraud Rate: %.1f%%\n", g.cfg.Generate.FraudRate*100)
+	}
+	for _, window := range g.cfg.FraudWindows {
+		fraudType := window.Type
+		if fraudType == "" {
+			fraudType = string(g.fraudType())
+		}
+		fmt.Fprintf(&b, "  Fraud Window: %v-%v at %.1f%% (%s)\n", window.Start, window.End, window.Rate*100, fraudType)
+	}
+	for _, event := range g.cfg.NewsEvents {
+		fmt.Fprintf(&b, "  News Event: %s %+.1f%% at %v\n", event.Symbol, event.Change*100, event.At)
+	}
+	if g.halts != nil {
+		fmt.Fprintf(&b, "  Circuit Breaker: %.1f%% move within %v halts for %v\n",
+			g.cfg.Generate.HaltThreshold*100, g.cfg.Generate.HaltWindow, g.cfg.Generate.HaltDuration)
+	}
+	if g.cfg.Generate.Seed != 0 {
+		fmt.Fprintf(&b, "  Seed: %d\n", g.cfg.Generate.Seed)
+	}
+
+	slog.Info("generator starting",
+		logging.Text("%s", b.String()),
+		"tps", g.cfg.Generate.TPS,
+		"duration", g.cfg.Generate.Duration,
+		"fraud_rate", g.cfg.Generate.FraudRate,
+		"fraud_type", g.fraudType(),
+		"seed", g.cfg.Generate.Seed,
+	)
+}
+
+// currentTPS returns the target rate at t: the rate schedule's when one is
+// set, otherwise the base TPS scaled by any volume curve
+func (g *Generator) currentTPS(t time.Time) int {
+	switch {
+	case g.schedule != nil:
+		return g.schedule(t.Sub(g.startedAt))
+	case g.volume != nil:
+		return g.volume.tps(g.TPS(), t)
+	default:
+		return g.TPS()
+	}
+}
+
+// watchStall cancels generation if no trade has been published within the
+// stall timeout. Publishes blocked on an unresponsive sink never return to the
+// generation loop, so this runs on its own goroutine.
+func (g *Generator) watchStall(ctx context.Context, cancel context.CancelCauseFunc) {
+	timeout := g.cfg.Generate.StallTimeout
+	ticker := time.NewTicker(max(timeout/4, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			idle := time.Since(time.Unix(0, g.stats.LastPublish.Load()))
+			if idle > timeout {
+				slog.Error("no trades published, aborting", "idle", idle,
+					logging.Text("\n❌ No trades published for %v, aborting. Is the sink accepting writes?", idle.Round(time.Second)))
+				cancel(fmt.Errorf("%w: no trades published for %v", errStalled, timeout))
+				return
+			}
+		}
+	}
+}
+
+// adjustTPSForStreamDepth applies a proportional correction to TPS based on
+// how far the stream length is from the configured target
+func (g *Generator) adjustTPSForStreamDepth(ctx context.Context, tps int) int {
+	depthSink, ok := g.sink.(sink.StreamLengthReader)
+	if !ok {
+		return tps
+	}
+
+	length, err := depthSink.StreamLength(ctx)
+	if err != nil {
+		logError("reading stream length failed", "Error reading stream length", err)
+		return tps
+	}
+
+	target := float64(g.cfg.Generate.TargetStreamLength)
+	factor := 1 + g.cfg.Generate.StreamDepthGain*(target-float64(length))/target
+
+	// Limit each step so a single reading can't swing the rate wildly
+	factor = math.Max(0.5, math.Min(2.0, factor))
+
+	newTPS := int(math.Round(float64(tps) * factor))
+	if newTPS < 1 {
+		newTPS = 1
+	}
+	if newTPS > maxTPS {
+		newTPS = maxTPS
+	}
+
+	// The controller's rate becomes the base rate, so the per-tick
+	// recompute in Run keeps it rather than reverting to the configured TPS
+	g.live.tps.Store(int64(newTPS))
+
+	if newTPS != tps {
+		slog.Debug("stream depth adjusted tps",
+			"stream_length", length, "target", g.cfg.Generate.TargetStreamLength, "from", tps, "to", newTPS,
+			logging.Text("🎚️  Stream depth %d (target %d): %d → %d trades/sec",
+				length, g.cfg.Generate.TargetStreamLength, tps, newTPS))
+	}
+
+	return newTPS
+}
+
+// adjustFraudRateForLag moves the fraud rate toward a target that rises
+// linearly with the consumer group's lag, from the configured fraud rate at no
+// lag to the maximum at the configured lag and beyond
+func (g *Generator) adjustFraudRateForLag(ctx context.Context) {
+	lagSink, ok := g.sink.(sink.StreamLagReader)
+	if !ok {
+		return
+	}
+
+	lag, err := lagSink.StreamLag(ctx, g.cfg.Generate.Stream, g.cfg.Generate.FraudLagGroup)
+	if err != nil {
+		logError("reading consumer group lag failed", "Error reading consumer group lag", err)
+		return
+	}
+
+	g.setFraudRateForLag(lag)
+}
+
+// setFraudRateForLag applies one consumer lag reading to the fraud rate
+func (g *Generator) setFraudRateForLag(lag int64) {
+	low, high := g.cfg.Generate.FraudRate, g.cfg.Generate.FraudRateMax
+	pressure := math.Min(1, float64(lag)/float64(g.cfg.Generate.FraudLagMax))
+	target := low + (high-low)*pressure
+
+	rate := g.FraudRate()
+	newRate := rate + fraudLagSmoothing*(target-rate)
+	g.live.fraudRate.Store(math.Float64bits(newRate))
+
+	if math.Abs(newRate-rate) >= 0.001 {
+		slog.Debug("consumer lag adjusted fraud rate",
+			"lag", lag, "group", g.cfg.Generate.FraudLagGroup, "from", rate, "to", newRate,
+			logging.Text("🎚️  Consumer lag %d: fraud rate %.1f%% → %.1f%%", lag, rate*100, newRate*100))
+	}
+}
+
 // generateAndPublish generates and publishes a trade or fraud pattern
 func (g *Generator) generateAndPublish(ctx context.Context) error {
+	if err := g.processNews(ctx); err != nil {
+		return err
+	}
+	if g.halts != nil {
+		g.resumeHalts(ctx)
+	}
+
 	// Decide if this should be a fraud pattern
-	if rand.Float64() < g.cfg.Generate.FraudRate {
-		return g.generateFraudPattern(ctx)
+	rate, fraudType := g.fraudSettings(g.clock.Now().Sub(g.startedAt))
+	if every := g.cfg.Generate.FraudEvery; every > 0 {
+		g.ticks++
+		if g.ticks%every == 0 {
+			return g.generateFraudPattern(ctx, rate, fraudType)
+		}
+		return g.generateNormalTrade(ctx)
+	}
+	if g.rng.Float64() < rate {
+		return g.generateFraudPattern(ctx, rate, fraudType)
 	}
 
 	// Generate normal trade
@@ -110,141 +562,580 @@ func (g *Generator) generateAndPublish(ctx context.Context) error {
 
 // generateNormalTrade generates a single normal trade
 func (g *Generator) generateNormalTrade(ctx context.Context) error {
+	now := g.clock.Now()
+	candidates := g.profiles
+	if g.session != nil {
+		// Outside the session only fraud patterns trade
+		if !g.session.isOpen(now) {
+			return nil
+		}
+		candidates = g.session.activeProfiles(g.profiles, now)
+	}
+
 	// Select profile based on weighted distribution
 	profile := profiles.SelectProfile(
-		g.profiles,
+		g.rng,
+		candidates,
 		g.cfg.Profiles.HFTRatio,
 		g.cfg.Profiles.RegularRatio,
+		g.cfg.Profiles.MMRatio,
 		g.cfg.Profiles.CasualRatio,
 	)
 	if profile == nil {
 		return fmt.Errorf("no profile selected")
 	}
-
-	// Generate trade
-	trade := g.generateTrade(profile, time.Now())
-
-	// Publish to Redis
-	if err := g.redisClient.PublishTradeToStream(ctx, trade); err != nil {
-		return fmt.Errorf("failed to publish trade: %w", err)
+	if g.userSessions != nil {
+		profile = g.userSessions.next(g.rng, profile, candidates)
 	}
 
-	// Update statistics
-	g.updateStats(trade, profile, false)
-
-	// Verbose output
-	if g.cfg.Generate.Verbose {
-		fmt.Printf("[%s] %s: %s %.2f @ $%.2f (%s)\n",
-			trade.Timestamp.Format("15:04:05"),
-			trade.UserID,
-			trade.Type,
-			trade.Amount,
-			trade.Price,
-			trade.Symbol,
-		)
+	// Generate trade(s): market makers fill both sides of their quote
+	var trades []*feed.Trade
+	if profile.Type == profiles.MarketMakerTrader {
+		trades = g.patternGenerator.MarketMakerQuotes(profile, g.jitterTimestamp(now))
+	} else {
+		trades = []*feed.Trade{g.generateTrade(profile, g.jitterTimestamp(now))}
+	}
+	if g.halts != nil {
+		if trades = g.suppressHalted(ctx, trades); len(trades) == 0 {
+			return nil
+		}
+	}
+	if g.cfg.Generate.CancelOrders {
+		trades = g.withCancels(profile, trades)
+	}
+	for _, trade := range trades {
+		// Fraud patterns bypass the position book: wash trades sell what they never held
+		if g.positions != nil && !trade.Cancelled {
+			g.positions.apply(trade)
+		}
+		g.annotateTrade(trade, profile)
 	}
 
-	return nil
+	return g.enqueue(ctx, trades, profile, false)
 }
 
-// generateFraudPattern generates a fraud pattern (one or more trades)
-func (g *Generator) generateFraudPattern(ctx context.Context) error {
-	// Parse fraud type
-	fraudType := profiles.AllFraud
-	switch g.cfg.Generate.FraudType {
-	case "WASH":
-		fraudType = profiles.WashTrade
-	case "VELOCITY":
-		fraudType = profiles.VelocitySpike
-	case "ANOMALY":
-		fraudType = profiles.Anomaly
+// withCancels precedes each execution with the orders the profile cancelled
+// around it, so the group carries the profile's order-to-trade ratio
+func (g *Generator) withCancels(profile *profiles.TraderProfile, trades []*feed.Trade) []*feed.Trade {
+	var orders []*feed.Trade
+	for _, trade := range trades {
+		orders = append(orders, g.patternGenerator.CancelledOrders(profile, trade)...)
+		orders = append(orders, trade)
 	}
+	return orders
+}
+
+// generateFraudPattern generates a fraud pattern (one or more trades) of the
+// given fraud type, drawn at the given fraud rate
+func (g *Generator) generateFraudPattern(ctx context.Context, rate float64, configured profiles.FraudType) error {
+	// Pick the fraud type first so every enabled type is equally likely
+	// regardless of how many profiles back it
+	fraudTypes := g.enabledFraudTypes(configured)
+	fraudType := fraudTypes[g.rng.Intn(len(fraudTypes))]
 
 	// Select fraud profile
-	profile := profiles.SelectFraudProfile(g.profiles, fraudType)
+	profile := profiles.SelectFraudProfile(g.rng, g.profiles, fraudType)
 	if profile == nil {
-		// Fall back to normal trade
-		return g.generateNormalTrade(ctx)
+		return g.fraudFallback(ctx, rate, fraudType)
 	}
 
-	var trades []*models.Trade
-	baseTime := time.Now()
+	// A trader with a fraud mix picks its own tactic unless the type is pinned.
+	// The profile is a copy, so labels and logs read the drawn pattern from it.
+	if configured == profiles.AllFraud {
+		profile.FraudPattern = profile.DrawFraudPattern(g.rng)
+	} else {
+		profile.FraudPattern = fraudType
+	}
 
 	// Generate fraud pattern
-	switch profile.FraudPattern {
-	case profiles.WashTrade:
-		trades = g.patternGenerator.InjectWashTrade(profile, baseTime)
-	case profiles.VelocitySpike:
-		trades = g.patternGenerator.InjectVelocitySpike(profile, baseTime)
-	case profiles.Anomaly:
-		trade := g.patternGenerator.InjectAnomaly(profile, baseTime)
-		trades = []*models.Trade{trade}
-	default:
-		return g.generateNormalTrade(ctx)
+	trades, ok := g.patternGenerator.Inject(profile.FraudPattern, profile, g.clock.Now())
+	if !ok || len(trades) == 0 {
+		return g.fraudFallback(ctx, rate, fraudType)
+	}
+	if g.halts != nil {
+		// A pattern cut short by a halt is still labelled with what printed
+		if trades = g.suppressHalted(ctx, trades); len(trades) == 0 {
+			return nil
+		}
 	}
 
-	// Publish all trades
 	for _, trade := range trades {
-		if err := g.redisClient.PublishTradeToStream(ctx, trade); err != nil {
-			return fmt.Errorf("failed to publish fraud trade: %w", err)
-		}
-		g.updateStats(trade, profile, true)
-
-		if g.cfg.Generate.Verbose {
-			fmt.Printf("[%s] 🚨 FRAUD %s: %s %.2f @ $%.2f (%s)\n",
-				trade.Timestamp.Format("15:04:05"),
-				profile.FraudPattern,
-				trade.Type,
-				trade.Amount,
-				trade.Price,
-				trade.Symbol,
-			)
+		g.annotateTrade(trade, profile)
+	}
+
+	// Publish the whole pattern together
+	return g.enqueue(ctx, trades, profile, true)
+}
+
+// fraudFallback handles a fraud tick that could not produce a pattern. Normally
+// a normal trade is emitted instead, but a fraud rate of 1.0 promises a pure
+// fraud stream so the tick fails rather than leaking background trades.
+func (g *Generator) fraudFallback(ctx context.Context, rate float64, fraudType profiles.FraudType) error {
+	if rate >= 1 {
+		return fmt.Errorf("no fraud pattern available for fraud type %s", fraudType)
+	}
+	return g.generateNormalTrade(ctx)
+}
+
+// fraudType returns the configured fraud type
+func (g *Generator) fraudType() profiles.FraudType {
+	return profiles.FraudType(strings.ToUpper(g.cfg.Generate.FraudType))
+}
+
+// fraudSettings returns the fraud rate and type in force once elapsed time has
+// passed since the run started: those of the first fraud window covering it,
+// otherwise the global settings. Fraud-only mode pins the rate at 1.0, and
+// fraud-every mode reports its effective rate of 1/N.
+func (g *Generator) fraudSettings(elapsed time.Duration) (float64, profiles.FraudType) {
+	rate, fraudType := g.FraudRate(), g.fraudType()
+	for _, window := range g.cfg.FraudWindows {
+		if elapsed < window.Start || elapsed >= window.End {
+			continue
+		}
+		rate = window.Rate
+		if window.Type != "" {
+			fraudType = profiles.FraudType(window.Type)
 		}
+		break
+	}
+	if g.cfg.Generate.FraudOnly {
+		rate = 1
+	}
+	if every := g.cfg.Generate.FraudEvery; every > 0 {
+		rate = 1 / float64(every)
+	}
+	return rate, fraudType
+}
+
+// enabledFraudTypes returns the fraud types a configured fraud type can
+// produce. ALL skips fraud types no profile backs.
+func (g *Generator) enabledFraudTypes(fraudType profiles.FraudType) []profiles.FraudType {
+	if fraudType != profiles.AllFraud {
+		return []profiles.FraudType{fraudType}
+	}
+	return g.allFraudTypes
+}
+
+// Validate checks that the resolved configuration can drive generation
+// (profiles and fraud types) without connecting to a sink
+func (g *Generator) Validate() error {
+	return g.checkProfiles()
+}
+
+// warnUnpricedSymbols flags traded symbols missing from the price table, which
+// would otherwise silently trade at the default price
+func (g *Generator) warnUnpricedSymbols() {
+	seen := make(map[string]bool)
+	var unpriced []string
+	check := func(symbol string) {
+		if !seen[symbol] && !g.patternGenerator.HasPrice(symbol) {
+			unpriced = append(unpriced, symbol)
+		}
+		seen[symbol] = true
+	}
+	for _, profile := range g.profiles {
+		for _, symbol := range profile.TypicalSymbols {
+			check(symbol)
+		}
+		for symbol := range profile.SymbolWeights {
+			check(symbol)
+		}
+	}
+	for _, symbols := range g.cfg.FraudSymbols {
+		for _, symbol := range symbols {
+			check(symbol)
+		}
+	}
+	for _, event := range g.cfg.NewsEvents {
+		check(event.Symbol)
+	}
+
+	if len(unpriced) > 0 {
+		sort.Strings(unpriced)
+		slog.Warn("no price configured", "symbols", unpriced, "default_price", patterns.DefaultSymbolPrice,
+			logging.Text("⚠️  Warning: no price configured for %s, using $%.2f", strings.Join(unpriced, ", "), patterns.DefaultSymbolPrice))
+	}
+}
+
+// warnUnmarkedHalts flags halt markers requested of a sink that can't carry them
+func (g *Generator) warnUnmarkedHalts() {
+	if g.halts == nil || !g.cfg.Generate.HaltMarkers {
+		return
+	}
+	if _, ok := g.sink.(sink.HaltPublisher); !ok {
+		slog.Warn("output does not support halt markers, halts will only be logged",
+			logging.Text("⚠️  Warning: this output can't carry halt markers, halts will only be logged"))
+	}
+}
+
+// warnUnbackedFraudTypes flags fraud types that ALL will never produce because
+// no profile backs them
+func (g *Generator) warnUnbackedFraudTypes() {
+	active, _ := g.activeFraudTypes(g.FraudRate())
+	if !slices.Contains(active, profiles.AllFraud) {
+		return
+	}
+
+	var unbacked []profiles.FraudType
+	for _, fraudType := range g.patternGenerator.FraudTypes() {
+		if !slices.Contains(g.allFraudTypes, fraudType) {
+			unbacked = append(unbacked, fraudType)
+		}
+	}
+	if len(unbacked) > 0 {
+		slog.Warn("fraud types without profiles skipped", "fraud_types", unbacked,
+			logging.Text("⚠️  Warning: no fraud profiles for %s, so fraud type ALL skips them", joinFraudTypes(unbacked)))
+	}
+}
+
+// checkProfiles verifies the profile set can drive generation, across the
+// whole range a lag-driven fraud rate can take
+func (g *Generator) checkProfiles() error {
+	if err := g.checkProfilesAt(g.FraudRate()); err != nil {
+		return err
+	}
+	if g.cfg.Generate.FraudLagGroup != "" {
+		return g.checkProfilesAt(g.cfg.Generate.FraudRateMax)
+	}
+	return nil
+}
+
+// checkProfilesAt verifies the profile set can drive generation at the given
+// global fraud rate
+func (g *Generator) checkProfilesAt(fraudRate float64) error {
+	if len(g.profiles) == 0 {
+		return fmt.Errorf("no trader profiles loaded")
+	}
+
+	active, minRate := g.activeFraudTypes(fraudRate)
+
+	// Normal trades need a non-fraud profile unless every emission is a fraud pattern
+	if minRate < 1 && profiles.CountNormalProfiles(g.profiles) == 0 {
+		return fmt.Errorf("no HFT, REGULAR, MM or CASUAL profiles loaded for normal trades")
+	}
+
+	if len(active) == 0 {
+		return nil
+	}
+
+	for _, fraudType := range active {
+		if fraudType != profiles.AllFraud && !g.patternGenerator.HasInjector(fraudType) {
+			return fmt.Errorf("unknown fraud type %s (available: ALL, %s)",
+				fraudType, joinFraudTypes(g.patternGenerator.FraudTypes()))
+		}
+	}
+
+	for configured := range g.cfg.FraudSymbols {
+		if !g.patternGenerator.HasInjector(profiles.FraudType(configured)) {
+			return fmt.Errorf("fraud symbols configured for unknown fraud type %s", configured)
+		}
+	}
+
+	comboProfiles := profiles.FilterFraudProfiles(g.profiles, profiles.Combo)
+	for _, component := range g.cfg.Generate.ComboPatterns {
+		fraudType := profiles.FraudType(component)
+		if fraudType == profiles.Combo || !g.patternGenerator.HasInjector(fraudType) {
+			return fmt.Errorf("invalid combo pattern %s (available: %s)",
+				component, joinFraudTypes(g.patternGenerator.FraudTypes()))
+		}
+		// Collusion and rings trade between linked accounts; without any the
+		// combo account would trade with itself
+		if fraudType != profiles.Collusion && fraudType != profiles.TradingRing {
+			continue
+		}
+		for _, profile := range comboProfiles {
+			if len(profile.LinkedUserIDs) == 0 {
+				return fmt.Errorf("combo pattern %s needs linked accounts, but combo profile %s has none", component, profile.UserID)
+			}
+		}
+	}
+
+	// Every requested fraud type needs a profile, otherwise its ticks silently
+	// become normal trades. ALL only needs one backed type; the rest are skipped.
+	var missing []profiles.FraudType
+	seen := make(map[profiles.FraudType]bool)
+	for _, fraudType := range active {
+		if fraudType == profiles.AllFraud {
+			if len(g.allFraudTypes) == 0 {
+				return fmt.Errorf("no fraud profiles loaded for fraud type ALL")
+			}
+			continue
+		}
+		if !seen[fraudType] && len(profiles.FilterFraudProfiles(g.profiles, fraudType)) == 0 {
+			missing = append(missing, fraudType)
+		}
+		seen[fraudType] = true
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no fraud profiles for fraud types: %s", joinFraudTypes(missing))
 	}
 
 	return nil
 }
 
+// activeFraudTypes returns the fraud types that can fire at the given global
+// fraud rate or in a fraud window, with the lowest rate any of them runs at
+func (g *Generator) activeFraudTypes(fraudRate float64) ([]profiles.FraudType, float64) {
+	if g.cfg.Generate.FraudOnly {
+		fraudRate = 1
+	}
+	if every := g.cfg.Generate.FraudEvery; every > 0 {
+		fraudRate = 1 / float64(every)
+	}
+
+	minRate := fraudRate
+	var active []profiles.FraudType
+	if fraudRate > 0 {
+		active = append(active, g.fraudType())
+	}
+	for _, window := range g.cfg.FraudWindows {
+		rate := window.Rate
+		if g.cfg.Generate.FraudOnly {
+			rate = 1
+		}
+		minRate = math.Min(minRate, rate)
+		if rate > 0 {
+			_, fraudType := g.fraudSettings(window.Start)
+			active = append(active, fraudType)
+		}
+	}
+	return active, minRate
+}
+
+// joinFraudTypes formats fraud types as a comma-separated list
+func joinFraudTypes(fraudTypes []profiles.FraudType) string {
+	names := make([]string, len(fraudTypes))
+	for i, fraudType := range fraudTypes {
+		names[i] = string(fraudType)
+	}
+	return strings.Join(names, ", ")
+}
+
 // generateTrade creates a trade from a profile
-func (g *Generator) generateTrade(profile *profiles.TraderProfile, timestamp time.Time) *models.Trade {
-	symbol := profile.GetRandomSymbol()
-	amount := g.patternGenerator.GenerateAmount(profile)
-	price := g.patternGenerator.GetPrice(symbol)
+func (g *Generator) generateTrade(profile *profiles.TraderProfile, timestamp time.Time) *feed.Trade {
+	symbol := profile.GetRandomSymbol(g.rng)
+	side := g.patternGenerator.RandomTradeType(profile)
+	price := g.patternGenerator.GetTradePrice(symbol, side)
+	amount := g.patternGenerator.RoundToLot(g.patternGenerator.GenerateAmount(profile), price)
 
-	return &models.Trade{
-		ID:        uuid.New(),
+	return g.patternGenerator.NewTrade(&models.Trade{
+		ID:        g.patternGenerator.NewID(),
 		UserID:    profile.UserID,
 		Symbol:    symbol,
 		Amount:    amount,
 		Price:     price,
-		Type:      g.patternGenerator.RandomTradeType(),
+		Type:      side,
 		Timestamp: timestamp,
+	})
+}
+
+// annotateTrade fills in the feed-level fields set just before publishing
+func (g *Generator) annotateTrade(trade *feed.Trade, profile *profiles.TraderProfile) {
+	// Patterns that must cross the spread tag their own trades
+	if trade.Liquidity == "" {
+		trade.Liquidity = g.patternGenerator.RandomLiquidity(profile)
+	}
+	if g.cfg.Generate.TagTraderType {
+		trade.TraderType = string(profile.Type)
+	}
+	// Patterns may switch symbol after building a trade, so classify the final one
+	if g.cfg.Generate.TagSector {
+		metadata := g.patternGenerator.SymbolMetadata(trade.Symbol)
+		trade.Sector = metadata.Sector
+		trade.AssetClass = metadata.AssetClass
+	}
+	g.maybeSkewTimestamp(trade)
+}
+
+// jitterTimestamp spreads a normal trade's timestamp over the tick interval
+// ending at now, so trades at high TPS don't share tick-aligned times. Uniform
+// jitter draws each offset independently; Poisson jitter chains exponential
+// gaps from the previous arrival, kept within the current interval.
+func (g *Generator) jitterTimestamp(now time.Time) time.Time {
+	interval := time.Second / time.Duration(g.currentTPS(now))
+	windowStart := now.Add(-interval)
+
+	switch g.cfg.Generate.TimestampJitter {
+	case "uniform":
+		return windowStart.Add(time.Duration(g.timing.Float64() * float64(interval)))
+	case "poisson":
+		arrival := g.lastArrival
+		if arrival.Before(windowStart) {
+			arrival = windowStart
+		}
+		arrival = arrival.Add(time.Duration(g.timing.ExpFloat64() * float64(interval)))
+		if arrival.After(now) {
+			arrival = now
+		}
+		g.lastArrival = arrival
+		return arrival
+	default:
+		return now
+	}
+}
+
+// maybeSkewTimestamp simulates a clock fault on a configured fraction of trades
+// by shifting the timestamp up to the skew range into the past or future
+func (g *Generator) maybeSkewTimestamp(trade *feed.Trade) {
+	rate := g.cfg.Generate.TimestampSkewRate
+	if rate == 0 || g.timing.Float64() >= rate {
+		return
 	}
+
+	skew := time.Duration((g.timing.Float64()*2 - 1) * float64(g.cfg.Generate.TimestampSkewRange))
+	trade.Timestamp = trade.Timestamp.Add(skew)
+	g.stats.SkewedTimestamps.Add(1)
 }
 
 // updateStats updates generation statistics
-func (g *Generator) updateStats(trade *models.Trade, profile *profiles.TraderProfile, isFraud bool) {
+func (g *Generator) updateStats(trade *feed.Trade, profile *profiles.TraderProfile, isFraud bool) {
 	g.stats.TotalTrades.Add(1)
+	g.stats.LastPublish.Store(time.Now().UnixNano())
 
 	if isFraud {
 		g.stats.FraudPatterns.Add(1)
 	}
+	if trade.Cancelled {
+		g.stats.CancelledOrders.Add(1)
+	}
 
 	// Volume in cents
-	volumeCents := uint64(trade.Amount * trade.Price * 100)
+	volumeCents := uint64(g.patternGenerator.AmountMode().Notional(trade.Amount, trade.Price) * 100)
 	g.stats.VolumeGenerated.Add(volumeCents)
+	g.stats.sizes.observe(trade.Amount)
+
+	// Profile, symbol and account stats
+	g.stats.counter(g.stats.byProfile, string(profile.Type), nil).Add(1)
+	g.stats.counter(g.stats.bySymbol, trade.Symbol, &g.stats.UniqueSymbols).Add(1)
+	g.stats.counter(g.stats.byUser, trade.UserID, &g.stats.UniqueAccounts).Add(1)
+}
 
-	// Profile stats
-	profileType := string(profile.Type)
-	if counter, exists := g.stats.ByProfile[profileType]; exists {
-		counter.Add(1)
+// counter returns the counter for key in one of the per-key maps, creating it
+// (and bumping the cardinality, if tracked) on first use. Publisher workers
+// may insert concurrently, so the common lookup takes a read lock and
+// insertion rechecks under the write lock.
+func (s *Statistics) counter(counters map[string]*atomic.Int64, key string, unique *atomic.Int64) *atomic.Int64 {
+	s.mu.RLock()
+	c, exists := counters[key]
+	s.mu.RUnlock()
+	if exists {
+		return c
 	}
 
-	// Symbol stats
-	if _, exists := g.stats.BySymbol[trade.Symbol]; !exists {
-		g.stats.BySymbol[trade.Symbol] = &atomic.Int64{}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, exists := counters[key]; exists {
+		return c
+	}
+	c = &atomic.Int64{}
+	counters[key] = c
+	if unique != nil {
+		unique.Add(1)
+	}
+	return c
+}
+
+// ProfileCounts returns a snapshot of trade counts by profile type
+func (s *Statistics) ProfileCounts() map[string]int64 {
+	return s.snapshot(s.byProfile)
+}
+
+// SymbolCounts returns a snapshot of trade counts by symbol
+func (s *Statistics) SymbolCounts() map[string]int64 {
+	return s.snapshot(s.bySymbol)
+}
+
+// snapshot copies a per-key counter map, safe to call while generating
+func (s *Statistics) snapshot(counters map[string]*atomic.Int64) map[string]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int64, len(counters))
+	for key, c := range counters {
+		counts[key] = c.Load()
+	}
+	return counts
+}
+
+// statsTotals is a copy of the cumulative counters, diffed between reports to
+// get per-interval figures
+type statsTotals struct {
+	Trades      int64
+	Fraud       int64
+	VolumeCents uint64
+}
+
+// totals reads the cumulative counters
+func (s *Statistics) totals() statsTotals {
+	return statsTotals{
+		Trades:      s.TotalTrades.Load(),
+		Fraud:       s.FraudPatterns.Load(),
+		VolumeCents: s.VolumeGenerated.Load(),
+	}
+}
+
+// since returns the counts accumulated after prev
+func (t statsTotals) since(prev statsTotals) statsTotals {
+	return statsTotals{
+		Trades:      t.Trades - prev.Trades,
+		Fraud:       t.Fraud - prev.Fraud,
+		VolumeCents: t.VolumeCents - prev.VolumeCents,
+	}
+}
+
+// fraudRate returns the fraction of trades from fraud patterns
+func (t statsTotals) fraudRate() float64 {
+	if t.Trades == 0 {
+		return 0
+	}
+	return float64(t.Fraud) / float64(t.Trades)
+}
+
+// volume returns the notional volume in dollars
+func (t statsTotals) volume() float64 {
+	return float64(t.VolumeCents) / 100.0
+}
+
+// recordInterval stores the requested rate and the trades actually published
+// over the last reporting interval
+func (s *Statistics) recordInterval(requested int, trades int64, interval time.Duration) {
+	s.requestedTPS.Store(int64(requested))
+	s.intervalTrades.Store(trades)
+	s.intervalNanos.Store(int64(interval))
+}
+
+// RequestedTPS returns the target rate at the last stats report
+func (s *Statistics) RequestedTPS() int64 {
+	return s.requestedTPS.Load()
+}
+
+// IntervalTPS returns the rate achieved over the last stats interval
+func (s *Statistics) IntervalTPS() float64 {
+	return intervalRate(s.intervalTrades.Load(), time.Duration(s.intervalNanos.Load()))
+}
+
+// TPSDeficit returns how far the last stats interval fell short of the
+// requested rate, or 0 if it kept up
+func (s *Statistics) TPSDeficit() float64 {
+	return tpsDeficit(s.RequestedTPS(), s.IntervalTPS())
+}
+
+// intervalRate returns the rate of trades counted over an interval
+func intervalRate(trades int64, interval time.Duration) float64 {
+	if interval <= 0 {
+		return 0
 	}
-	g.stats.BySymbol[trade.Symbol].Add(1)
+	return float64(trades) / interval.Seconds()
+}
+
+// tpsDeficit returns the shortfall of actual against requested, never negative
+func tpsDeficit(requested int64, actual float64) float64 {
+	return max(float64(requested)-actual, 0)
+}
+
+// Stats returns the generator's live statistics
+func (g *Generator) Stats() *Statistics {
+	return g.stats
 }
 
 // reportStats periodically reports statistics
@@ -252,62 +1143,183 @@ func (g *Generator) reportStats(ctx context.Context) {
 	ticker := time.NewTicker(g.cfg.Generate.StatsInterval)
 	defer ticker.Stop()
 
+	lastReport := time.Now()
+	var last statsTotals
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case now := <-ticker.C:
 			elapsed := time.Since(g.stats.StartTime)
-			totalTrades := g.stats.TotalTrades.Load()
-			fraudTrades := g.stats.FraudPatterns.Load()
-			volumeCents := g.stats.VolumeGenerated.Load()
-			volume := float64(volumeCents) / 100.0
 
-			tps := float64(totalTrades) / elapsed.Seconds()
+			// Diff against the previous report so a burst isn't diluted in
+			// the running totals, and a publisher falling behind shows up in
+			// the interval rate straight away
+			totals := g.stats.totals()
+			window := totals.since(last)
+			requested := g.currentTPS(g.clock.Now())
+			g.stats.recordInterval(requested, window.Trades, now.Sub(lastReport))
+			actual := g.stats.IntervalTPS()
+			lastReport, last = now, totals
+
+			volume := totals.volume()
+			tps := float64(totals.Trades) / elapsed.Seconds()
+			accounts := g.stats.UniqueAccounts.Load()
+			symbols := g.stats.UniqueSymbols.Load()
 
-			fmt.Printf("[%s] %d trades | %d fraud | %.1f tps | $%.1fM volume\n",
+			line := fmt.Sprintf("[%s] %d trades | %d fraud | %.1f tps | $%.1fM volume | %d accounts | %d symbols",
 				formatDuration(elapsed),
-				totalTrades,
-				fraudTrades,
+				totals.Trades,
+				totals.Fraud,
 				tps,
 				volume/1000000.0,
+				accounts,
+				symbols,
 			)
+			line += fmt.Sprintf(" | last %s: +%d trades, +%d fraud (%.1f%%), %.1f of %d tps requested, +$%.1fM volume",
+				g.cfg.Generate.StatsInterval,
+				window.Trades,
+				window.Fraud,
+				window.fraudRate()*100,
+				actual,
+				requested,
+				window.volume()/1000000.0,
+			)
+			attrs := []any{
+				"elapsed", elapsed,
+				"trades", totals.Trades,
+				"fraud_trades", totals.Fraud,
+				"tps", tps,
+				"volume", volume,
+				"accounts", accounts,
+				"symbols", symbols,
+				"interval_trades", window.Trades,
+				"interval_fraud_trades", window.Fraud,
+				"interval_fraud_rate", window.fraudRate(),
+				"interval_volume", window.volume(),
+				"interval_tps", actual,
+				"requested_tps", requested,
+				"tps_deficit", tpsDeficit(int64(requested), actual),
+			}
+			if g.cfg.Generate.ReportResources {
+				usage := sampleResources()
+				line += fmt.Sprintf(" | %d goroutines | %s heap | %d GCs",
+					usage.Goroutines,
+					formatBytes(usage.HeapAlloc),
+					usage.NumGC,
+				)
+				attrs = append(attrs,
+					"goroutines", usage.Goroutines,
+					"heap_bytes", usage.HeapAlloc,
+					"gc_cycles", usage.NumGC,
+				)
+			}
+			slog.Info("stats", append(attrs, logging.Text("%s", line))...)
 		}
 	}
 }
 
-// printFinalStats prints final generation statistics
+// printFinalStats prints final generation statistics in the configured
+// format and writes the JSON report if a stats file is set
 func (g *Generator) printFinalStats() error {
-	elapsed := time.Since(g.stats.StartTime)
-	totalTrades := g.stats.TotalTrades.Load()
-	fraudTrades := g.stats.FraudPatterns.Load()
-	volumeCents := g.stats.VolumeGenerated.Load()
-	volume := float64(volumeCents) / 100.0
+	snap := g.stats.Snapshot()
+
+	if g.cfg.Generate.StatsFormat == "json" {
+		if err := writeStatsFile("-", snap); err != nil {
+			return err
+		}
+	} else {
+		g.printStatsText(snap)
+	}
 
-	tps := float64(totalTrades) / elapsed.Seconds()
+	if path := g.cfg.Generate.StatsFile; path != "" {
+		if err := writeStatsFile(path, snap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// printStatsText prints the human-readable final summary
+func (g *Generator) printStatsText(snap StatsSnapshot) {
 	fmt.Printf("\n=== Final Statistics ===\n")
-	fmt.Printf("Duration:       %v\n", elapsed.Round(time.Second))
-	fmt.Printf("Total Trades:   %d\n", totalTrades)
-	fmt.Printf("Fraud Patterns: %d (%.1f%%)\n",
-		fraudTrades,
-		float64(fraudTrades)/float64(totalTrades)*100)
-	fmt.Printf("Throughput:     %.1f trades/sec\n", tps)
-	fmt.Printf("Total Volume:   $%.2f\n\n", volume)
+	fmt.Printf("Duration:       %v\n", snap.Duration.Round(time.Second))
+	fmt.Printf("Total Trades:   %d\n", snap.TotalTrades)
+	fmt.Printf("Fraud Patterns: %d (%.1f%%)\n", snap.FraudTrades, snap.FraudRate*100)
+	fmt.Printf("Throughput:     %.1f trades/sec\n", snap.Throughput)
+	fmt.Printf("Total Volume:   $%.2f\n", snap.TotalVolume)
+	fmt.Printf("Unique Accounts: %d\n", snap.UniqueAccounts)
+	fmt.Printf("Unique Symbols:  %d\n", snap.UniqueSymbols)
+	if snap.SkewedTimestamps > 0 {
+		fmt.Printf("Skewed Timestamps: %d\n", snap.SkewedTimestamps)
+	}
+	if g.labels != nil {
+		fmt.Printf("Labels Written:  %d\n", snap.LabelsWritten)
+	}
+	if snap.PublishRetries > 0 {
+		fmt.Printf("Publish Retries: %d (%d trades dropped)\n", snap.PublishRetries, snap.DroppedTrades)
+	} else if snap.DroppedTrades > 0 {
+		fmt.Printf("Dropped Trades:  %d\n", snap.DroppedTrades)
+	}
+	if snap.Halts > 0 {
+		fmt.Printf("Trading Halts:   %d (%d trades suppressed)\n", snap.Halts, snap.HaltedTrades)
+	}
+	if snap.CancelledOrders > 0 {
+		fmt.Printf("Cancelled Orders: %d (%.1f orders per execution)\n", snap.CancelledOrders, snap.OrderToTrade)
+	}
+	fmt.Printf("\n")
 
 	fmt.Printf("By Profile Type:\n")
-	for profileType, counter := range g.stats.ByProfile {
-		count := counter.Load()
+	for profileType, count := range snap.ByProfile {
 		if count > 0 {
 			fmt.Printf("  %s: %d (%.1f%%)\n",
 				profileType,
 				count,
-				float64(count)/float64(totalTrades)*100)
+				float64(count)/float64(snap.TotalTrades)*100)
 		}
 	}
 
+	if snap.TotalTrades > 0 {
+		fmt.Printf("\nTrade Sizes (%s):\n", g.cfg.Generate.AmountMode)
+		writeHistogram(os.Stdout, snap.SizeHistogram)
+	}
+
+	if g.cfg.Generate.ReportResources {
+		g.printResourceUsage(snap.Duration)
+	}
+
 	fmt.Printf("\nGeneration complete! ✅\n")
-	return nil
+}
+
+// printResourceUsage prints the generator's own CPU, memory and GC usage
+func (g *Generator) printResourceUsage(elapsed time.Duration) {
+	usage := sampleResources()
+
+	fmt.Printf("\nResource Usage:\n")
+	fmt.Printf("  Goroutines:  %d\n", usage.Goroutines)
+	fmt.Printf("  Heap In Use: %s (%s reserved)\n", formatBytes(usage.HeapAlloc), formatBytes(usage.HeapSys))
+	fmt.Printf("  Allocated:   %s total\n", formatBytes(usage.TotalAlloc))
+	fmt.Printf("  GC Cycles:   %d (%v paused)\n", usage.NumGC, usage.GCPauseTotal.Round(time.Microsecond))
+	fmt.Printf("  CPU Time:    %v (%.1f%% of one core)\n",
+		usage.CPUTime.Round(time.Millisecond),
+		usage.CPUTime.Seconds()/elapsed.Seconds()*100)
+}
+
+// logError logs a failed operation, shown on the console as "<text>: <err>"
+func logError(msg, text string, err error) {
+	slog.Error(msg, "error", err, logging.Text("%s: %v", text, err))
+}
+
+// formatConditions formats trade conditions for verbose output
+func formatConditions(conditions []feed.Condition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	formatted := make([]string, len(conditions))
+	for i, c := range conditions {
+		formatted[i] = string(c)
+	}
+	return " [" + strings.Join(formatted, ",") + "]"
 }
 
 // formatDuration formats a duration as MM:SS
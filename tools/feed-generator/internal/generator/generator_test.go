@@ -0,0 +1,1073 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+)
+
+// recordingSink captures published trades and labels, standing in for Redis
+type recordingSink struct {
+	mu     sync.Mutex
+	trades []*feed.Trade
+	labels []*feed.Label
+}
+
+func (s *recordingSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades = append(s.trades, trade)
+	return nil
+}
+
+func (s *recordingSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trades = append(s.trades, trades...)
+	return nil
+}
+
+func (s *recordingSink) PublishLabels(ctx context.Context, labels []*feed.Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.labels = append(s.labels, labels...)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	return nil
+}
+
+// testStart is a Monday afternoon, inside every built-in profile's active hours
+var testStart = time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+
+// recordTrades generates n trades into a recording sink on a fake clock
+func recordTrades(t *testing.T, opts Options, n int) *recordingSink {
+	t.Helper()
+	recorder := &recordingSink{}
+	opts.Sink = recorder
+	opts.Labels = recorder
+	if opts.Clock == nil {
+		opts.Clock = clock.NewFake(testStart)
+	}
+	g, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GenerateN(context.Background(), n); err != nil {
+		t.Fatal(err)
+	}
+	return recorder
+}
+
+// depthSink reports a fixed stream length to the depth controller
+type depthSink struct {
+	sink.Discard
+	length int64
+}
+
+func (s *depthSink) StreamLength(ctx context.Context) (int64, error) {
+	return s.length, nil
+}
+
+func TestStreamDepthControllerRateSurvivesTick(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.TargetStreamLength = 1000
+	g, err := New(Options{Config: cfg, Sink: &depthSink{length: 0}, TPS: 100, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tps := g.adjustTPSForStreamDepth(context.Background(), 100)
+	if tps <= 100 {
+		t.Fatalf("empty stream should raise tps above 100, got %d", tps)
+	}
+	if got := g.currentTPS(g.clock.Now()); got != tps {
+		t.Errorf("tick recompute reverted controller rate %d to %d", tps, got)
+	}
+}
+
+func TestComboRejectsRingWithoutLinkedAccounts(t *testing.T) {
+	for _, component := range []string{"COLLUSION", "RING"} {
+		cfg := config.Default()
+		cfg.Generate.FraudType = "COMBO"
+		cfg.Generate.ComboPatterns = []string{"VELOCITY", component}
+		if _, err := New(Options{Config: cfg, FraudRate: 0.1, Seed: 1}); err == nil {
+			t.Errorf("combo with %s should be rejected for the unlinked built-in combo profile", component)
+		}
+	}
+}
+
+func TestSameSeedSameTrades(t *testing.T) {
+	opts := Options{FraudRate: 0.2, Seed: 42, TimingSeed: 7}
+	first := recordTrades(t, opts, 1000).trades
+	second := recordTrades(t, opts, 1000).trades
+
+	if len(first) != len(second) {
+		t.Fatalf("runs published %d and %d trades", len(first), len(second))
+	}
+	for i := range first {
+		if a, b := fmt.Sprintf("%+v", *first[i].Trade), fmt.Sprintf("%+v", *second[i].Trade); a != b {
+			t.Fatalf("trade %d differs between runs with the same seeds:\n%s\n%s", i, a, b)
+		}
+	}
+}
+
+func TestSameSeedSameTradeIDs(t *testing.T) {
+	// Fraud patterns draw their IDs too, so cover every injector. Interleaved
+	// patterns are ordered by timestamp, so timing is pinned as well.
+	opts := Options{FraudRate: 0.5, FraudType: "ALL", Seed: 42, TimingSeed: 7}
+	first := recordTrades(t, opts, 2000).trades
+	second := recordTrades(t, opts, 2000).trades
+
+	if len(first) != len(second) {
+		t.Fatalf("runs published %d and %d trades", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Fatalf("trade %d by %s: got IDs %s and %s from the same seed", i, first[i].UserID, first[i].ID, second[i].ID)
+		}
+	}
+
+	other := recordTrades(t, Options{FraudRate: 0.5, FraudType: "ALL", Seed: 43, TimingSeed: 7}, 2000).trades
+	if first[0].ID == other[0].ID {
+		t.Error("a different seed repeated the first trade ID")
+	}
+}
+
+func TestLabelsCoverFraudTradesOnce(t *testing.T) {
+	recorder := recordTrades(t, Options{FraudRate: 0.3, Seed: 1}, 2000)
+
+	labelled := make(map[string]int)
+	for _, label := range recorder.labels {
+		if label.FraudType == "NONE" {
+			t.Fatalf("normal trades labelled without negative labels enabled")
+		}
+		for _, id := range label.TradeIDs {
+			labelled[id.String()]++
+		}
+	}
+	if len(labelled) == 0 {
+		t.Fatal("no fraud labels published")
+	}
+
+	// Fraud accounts only trade in patterns, so each of their trades needs a label
+	published := make(map[string]bool)
+	for _, trade := range recorder.trades {
+		published[trade.ID.String()] = true
+		if strings.HasPrefix(trade.UserID, "FRAUD_") && labelled[trade.ID.String()] == 0 {
+			t.Errorf("fraud trade %s by %s has no label", trade.ID, trade.UserID)
+		}
+	}
+	for id, count := range labelled {
+		if count != 1 {
+			t.Errorf("trade %s labelled %d times", id, count)
+		}
+		if !published[id] {
+			t.Errorf("labelled trade %s was never published", id)
+		}
+	}
+}
+
+// Run with -race: the reporter and final statistics read the per-symbol maps
+// while generation inserts into them
+func TestStatisticsReadDuringGeneration(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.StatsInterval = 5 * time.Millisecond
+	cfg.Generate.SyntheticSymbols = 500 // Keep inserting new symbols
+	g, err := New(Options{Config: cfg, Clock: clock.NewFake(testStart), Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		g.reportStats(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			g.Stats().Snapshot()
+		}
+	}()
+
+	_, err = g.GenerateN(context.Background(), 5000)
+	cancel()
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Stats().Snapshot(); len(got.BySymbol) == 0 {
+		t.Error("no per-symbol counts recorded")
+	}
+}
+
+func TestMissingFraudProfileFails(t *testing.T) {
+	var withoutWash []profiles.TraderProfile
+	for _, profile := range profiles.GetDefaultProfiles() {
+		if len(profiles.FilterFraudProfiles([]profiles.TraderProfile{profile}, profiles.WashTrade)) == 0 {
+			withoutWash = append(withoutWash, profile)
+		}
+	}
+
+	_, err := New(Options{Profiles: withoutWash, FraudRate: 0.1, FraudType: "WASH", Seed: 1})
+	if err == nil || !strings.Contains(err.Error(), "WASH") {
+		t.Errorf("want an error naming WASH, got %v", err)
+	}
+	if _, err := New(Options{Profiles: withoutWash, FraudRate: 0.1, FraudType: "VELOCITY", Seed: 1}); err != nil {
+		t.Errorf("a backed fraud type should still validate: %v", err)
+	}
+}
+
+func TestSequenceNumbersHaveNoGaps(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.SequenceNumbers = true
+	recorder := recordTrades(t, Options{Config: cfg, FraudRate: 0.3, Seed: 1}, 2000)
+
+	for i, trade := range recorder.trades {
+		if want := uint64(i + 1); trade.Seq != want {
+			t.Fatalf("trade %d by %s: got sequence %d, want %d", i, trade.UserID, trade.Seq, want)
+		}
+	}
+}
+
+func TestFraudOnlyWashEmitsOnlyWashPairs(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.FraudOnly = true
+	recorder := recordTrades(t, Options{Config: cfg, FraudType: "WASH", Seed: 1}, 500)
+
+	byID := make(map[string]*feed.Trade)
+	for _, trade := range recorder.trades {
+		byID[trade.ID.String()] = trade
+	}
+	labelled := 0
+	for _, label := range recorder.labels {
+		if label.FraudType != "WASH" || len(label.TradeIDs) != 2 {
+			t.Fatalf("want wash pairs only, got %s with %d trades", label.FraudType, len(label.TradeIDs))
+		}
+		buy, sell := byID[label.TradeIDs[0].String()], byID[label.TradeIDs[1].String()]
+		if buy.Type == sell.Type || buy.UserID != sell.UserID || buy.Symbol != sell.Symbol || buy.Amount != sell.Amount {
+			t.Errorf("pattern is not a wash pair: %+v / %+v", *buy.Trade, *sell.Trade)
+		}
+		labelled += 2
+	}
+	if labelled != len(recorder.trades) {
+		t.Errorf("%d of %d trades are outside a wash pair", len(recorder.trades)-labelled, len(recorder.trades))
+	}
+}
+
+func TestFraudEveryNthTick(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.FraudEvery = 100
+	cfg.Generate.FraudType = "ANOMALY" // One trade per pattern, so ticks map to trades
+	recorder := recordTrades(t, Options{Config: cfg, Seed: 1}, 1000)
+
+	if got := len(recorder.labels); got != 10 {
+		t.Errorf("got %d fraud patterns in 1000 ticks, want 10", got)
+	}
+	for _, label := range recorder.labels {
+		for i, trade := range recorder.trades {
+			if trade.ID == label.TradeIDs[0] && (i+1)%100 != 0 {
+				t.Errorf("fraud pattern on tick %d, not a multiple of 100", i+1)
+			}
+		}
+	}
+}
+
+func TestRunStopsAtMaxTrades(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.MaxTrades = 200
+	cfg.Generate.FraudRate = 0 // Patterns crossing the cap land whole, overshooting it
+	recorder := &recordingSink{}
+	g, err := New(Options{Config: cfg, Sink: recorder, Clock: clock.NewFake(testStart), TPS: 10000, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := g.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("run did not stop at the trade cap")
+	}
+	if got := len(recorder.trades); got != 200 {
+		t.Errorf("published %d trades, want 200", got)
+	}
+}
+
+func TestSymbolsOverrideConfinesEveryTrade(t *testing.T) {
+	for _, fraudType := range []string{"ALL", "PUMP_DUMP", "ANOMALY", "RING", "COMBO", "FRONT_RUN"} {
+		cfg := config.Default()
+		cfg.Generate.Symbols = []string{"AAPL", "TSLA"}
+		recorder := recordTrades(t, Options{Config: cfg, FraudRate: 0.5, FraudType: fraudType, Seed: 1}, 5000)
+
+		for _, trade := range recorder.trades {
+			if trade.Symbol != "AAPL" && trade.Symbol != "TSLA" {
+				t.Fatalf("%s: trade in %s outside the symbol list", fraudType, trade.Symbol)
+			}
+		}
+	}
+}
+
+func TestVolumeProfileDayAveragesTPS(t *testing.T) {
+	for _, profile := range []string{"u-shape", "custom"} {
+		cfg := config.Default()
+		cfg.Generate.VolumeProfile = profile
+		cfg.Generate.VolumeWeights = []float64{0, 0, 0, 0, 0, 0, 0, 1, 2, 6, 4, 3, 2, 2, 3, 4, 6, 2, 1, 0, 0, 0, 0, 0}
+		fake := clock.NewFake(testStart)
+		g, err := New(Options{Config: cfg, Clock: fake, TPS: 100, Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Tick as Run does, resetting the interval to each tick's target rate
+		end := testStart.Add(24 * time.Hour)
+		ticks := 0
+		for now := fake.Now(); now.Before(end); now = fake.Now() {
+			ticks++
+			fake.Advance(time.Second / time.Duration(g.currentTPS(now)))
+		}
+
+		want := 100 * 24 * 60 * 60
+		if diff := float64(ticks-want) / float64(want); diff < -0.02 || diff > 0.02 {
+			t.Errorf("%s: %d trades in a day, want about %d", profile, ticks, want)
+		}
+	}
+}
+
+// flakySink accepts half of each batch and then fails, for its first failures
+// batches
+type flakySink struct {
+	recordingSink
+	failures int
+}
+
+func (s *flakySink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	if s.failures == 0 {
+		return s.recordingSink.PublishBatch(ctx, trades)
+	}
+	s.failures--
+	half := len(trades) / 2
+	s.recordingSink.PublishBatch(ctx, trades[:half])
+	return &sink.BatchError{Published: half, Err: fmt.Errorf("connection reset")}
+}
+
+func TestFlakySinkDeliversEveryTrade(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.SequenceNumbers = true
+	cfg.Generate.PublishBackoff = time.Millisecond
+	opts := Options{Config: cfg, FraudRate: 0.3, Seed: 1, TimingSeed: 1}
+	want := recordTrades(t, opts, 500).trades
+
+	// Fail as many times in a row as the default policy retries
+	flaky := &flakySink{failures: cfg.Generate.PublishRetries}
+	opts.Sink = flaky
+	opts.Labels = flaky
+	opts.Clock = clock.NewFake(testStart)
+	g, err := New(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GenerateN(context.Background(), 500); err != nil {
+		t.Fatal(err)
+	}
+
+	got := flaky.trades
+	if len(got) != len(want) {
+		t.Fatalf("flaky sink received %d trades, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID || got[i].Seq != uint64(i+1) {
+			t.Fatalf("trade %d: got %s seq %d, want %s seq %d", i, got[i].ID, got[i].Seq, want[i].ID, i+1)
+		}
+	}
+	if n := g.Stats().DroppedTrades.Load(); n != 0 {
+		t.Errorf("%d trades dropped despite retries", n)
+	}
+	if n := g.Stats().PublishRetries.Load(); n != int64(cfg.Generate.PublishRetries) {
+		t.Errorf("got %d publish retries, want %d", n, cfg.Generate.PublishRetries)
+	}
+}
+
+func TestDryRunProducesStatsWithoutRedis(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.DryRun = true
+	cfg.Redis.Host = "redis.invalid" // Never dialled
+	g, err := New(Options{Config: cfg, Sink: sink.Discard{}, Clock: clock.NewFake(testStart), FraudRate: 0.2, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades, err := g.GenerateN(context.Background(), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GenerateN cuts a straddling pattern short, but the stats saw all of it
+	snap := g.Stats().Snapshot()
+	if snap.TotalTrades < int64(len(trades)) {
+		t.Errorf("stats count %d trades, want at least the %d generated", snap.TotalTrades, len(trades))
+	}
+	if snap.FraudTrades == 0 || snap.TotalVolume == 0 {
+		t.Errorf("got %d fraud trades and %.2f volume, want both counted", snap.FraudTrades, snap.TotalVolume)
+	}
+	if len(snap.ByProfile) == 0 || len(snap.BySymbol) == 0 {
+		t.Error("dry run recorded no per-profile or per-symbol counts")
+	}
+}
+
+// shutdownSink publishes one trade at a time like a pipelined client, failing
+// once its context ends. The first fraud trade it sees shuts the run down.
+type shutdownSink struct {
+	recordingSink
+	shutdown func()
+	delay    time.Duration
+}
+
+func (s *shutdownSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	time.Sleep(s.delay)
+	for i, trade := range trades {
+		if err := ctx.Err(); err != nil {
+			return &sink.BatchError{Published: i, Err: err}
+		}
+		s.Publish(ctx, trade)
+		if s.shutdown != nil && strings.HasPrefix(trade.UserID, "FRAUD_") {
+			s.shutdown()
+			s.shutdown = nil
+			time.Sleep(10 * time.Millisecond) // Let the cancel reach the run
+		}
+	}
+	return nil
+}
+
+func TestShutdownFinishesPatternInFlight(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Duration = 10 * time.Second
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := &shutdownSink{shutdown: cancel}
+	g, err := New(Options{Config: cfg, Sink: out, Labels: out, TPS: 1000, FraudRate: 0.2, FraudType: "VELOCITY", Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	published := make(map[string]bool)
+	for _, trade := range out.trades {
+		published[trade.ID.String()] = true
+	}
+	fraudLabels := 0
+	for _, label := range out.labels {
+		if label.FraudType == "NONE" {
+			continue
+		}
+		fraudLabels++
+		for _, id := range label.TradeIDs {
+			if !published[id.String()] {
+				t.Fatalf("%s pattern cut short: trade %s never published", label.FraudType, id)
+			}
+		}
+	}
+	if fraudLabels == 0 {
+		t.Error("the pattern in flight at shutdown was never labelled")
+	}
+}
+
+func TestShutdownDrainsQueuedBatches(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Duration = 10 * time.Second
+	cfg.Generate.Workers = 2
+	cfg.Generate.QueueSize = 50
+	cfg.Generate.BatchSize = 10
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// Publishers fall behind, so batches are still queued when the run stops
+	out := &shutdownSink{delay: 20 * time.Millisecond}
+	g, err := New(Options{Config: cfg, Sink: out, Labels: out, TPS: 2000, FraudRate: 0.1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := int64(len(out.trades)); got != g.generated {
+		t.Errorf("published %d trades, want all %d generated", got, g.generated)
+	}
+	if n := g.Stats().DroppedTrades.Load(); n != 0 {
+		t.Errorf("%d trades dropped on shutdown", n)
+	}
+}
+
+func TestStatsFileMatchesCounters(t *testing.T) {
+	recorder := &recordingSink{}
+	g, err := New(Options{Sink: recorder, Labels: recorder, Clock: clock.NewFake(testStart), FraudRate: 0.2, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GenerateN(context.Background(), 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "stats.json")
+	if err := writeStatsFile(path, g.Stats().Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		TotalTrades   int64            `json:"total_trades"`
+		FraudTrades   int64            `json:"fraud_trades"`
+		FraudRate     float64          `json:"fraud_rate"`
+		TotalVolume   float64          `json:"total_volume"`
+		LabelsWritten int64            `json:"labels_written"`
+		ByProfile     map[string]int64 `json:"by_profile"`
+		BySymbol      map[string]int64 `json:"by_symbol"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := g.Stats()
+	for _, field := range []struct {
+		name      string
+		got, want int64
+	}{
+		{"total_trades", report.TotalTrades, stats.TotalTrades.Load()},
+		{"fraud_trades", report.FraudTrades, stats.FraudPatterns.Load()},
+		{"labels_written", report.LabelsWritten, stats.LabelsWritten.Load()},
+	} {
+		if field.got != field.want {
+			t.Errorf("%s: got %d, want %d", field.name, field.got, field.want)
+		}
+	}
+	if want := float64(stats.VolumeGenerated.Load()) / 100; report.TotalVolume != want {
+		t.Errorf("total_volume: got %.2f, want %.2f", report.TotalVolume, want)
+	}
+	if want := float64(report.FraudTrades) / float64(report.TotalTrades); report.FraudRate != want {
+		t.Errorf("fraud_rate: got %f, want %f", report.FraudRate, want)
+	}
+
+	profileTotal, symbolTotal := int64(0), int64(0)
+	for _, n := range report.ByProfile {
+		profileTotal += n
+	}
+	for _, n := range report.BySymbol {
+		symbolTotal += n
+	}
+	if profileTotal != report.TotalTrades || symbolTotal != report.TotalTrades {
+		t.Errorf("breakdowns sum to %d by profile and %d by symbol, want %d", profileTotal, symbolTotal, report.TotalTrades)
+	}
+}
+
+func TestNoFraudOutsideWindowsAtZeroRate(t *testing.T) {
+	cfg := config.Default()
+	cfg.FraudWindows = []config.FraudWindow{{Start: 10 * time.Second, End: 20 * time.Second, Rate: 0.5, Type: "VELOCITY"}}
+	recorder := recordTrades(t, Options{Config: cfg, TPS: 10, Seed: 1}, 500)
+
+	start, end := testStart.Add(10*time.Second), testStart.Add(20*time.Second)
+	fraud := make(map[string]bool)
+	for _, label := range recorder.labels {
+		if label.FraudType == "NONE" {
+			continue
+		}
+		if label.InjectedAt.Before(start) || !label.InjectedAt.Before(end) {
+			t.Errorf("%s pattern injected %v into the run, outside the 10s-20s window", label.FraudType, label.InjectedAt.Sub(testStart))
+		}
+		for _, id := range label.TradeIDs {
+			fraud[id.String()] = true
+		}
+	}
+	if len(fraud) == 0 {
+		t.Fatal("no fraud injected inside the window")
+	}
+	for _, trade := range recorder.trades {
+		if !fraud[trade.ID.String()] && strings.HasPrefix(trade.UserID, "FRAUD_") {
+			t.Errorf("unlabelled fraud trade by %s at %v", trade.UserID, trade.Timestamp.Sub(testStart))
+		}
+	}
+}
+
+func TestEnforcedPositionsNeverGoShort(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.EnforcePositions = true
+	recorder := recordTrades(t, Options{Config: cfg, FraudRate: 0.2, Seed: 1}, 5000)
+
+	fraud := make(map[string]bool)
+	for _, label := range recorder.labels {
+		for _, id := range label.TradeIDs {
+			fraud[id.String()] = true
+		}
+	}
+
+	mode := feed.AmountMode(cfg.Generate.AmountMode)
+	holdings := make(map[positionKey]float64)
+	sells := 0
+	for _, trade := range recorder.trades {
+		if fraud[trade.ID.String()] {
+			continue // Wash and other patterns deliberately ignore holdings
+		}
+		key := positionKey{user: trade.UserID, symbol: trade.Symbol}
+		shares := mode.Shares(trade.Amount, trade.Price)
+		if trade.Type == models.TradeTypeSell {
+			holdings[key] -= shares
+			sells++
+		} else {
+			holdings[key] += shares
+		}
+		if holdings[key] < -1e-6 {
+			t.Fatalf("%s holds %.4f shares of %s", trade.UserID, holdings[key], trade.Symbol)
+		}
+	}
+	if sells == 0 {
+		t.Error("no normal trader ever sold")
+	}
+}
+
+func TestVolumeFollowsAmountMode(t *testing.T) {
+	for _, mode := range []feed.AmountMode{feed.AmountShares, feed.AmountNotional} {
+		cfg := config.Default()
+		cfg.Generate.AmountMode = string(mode)
+		recorder := &recordingSink{}
+		g, err := New(Options{Config: cfg, Sink: recorder, Labels: recorder, Clock: clock.NewFake(testStart), FraudRate: 0.1, Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g.GenerateN(context.Background(), 1000); err != nil {
+			t.Fatal(err)
+		}
+
+		want := 0.0
+		for _, trade := range recorder.trades {
+			notional := trade.Amount * trade.Price // Amount is shares
+			if mode == feed.AmountNotional {
+				notional = trade.Amount // Amount is already dollars
+			}
+			want += notional
+		}
+
+		// Each trade's volume is truncated to whole cents
+		got := g.Stats().Snapshot().TotalVolume
+		if got > want || got < want-0.01*float64(len(recorder.trades)) {
+			t.Errorf("%s: got volume %.2f, want %.2f", mode, got, want)
+		}
+	}
+}
+
+// failingSink rejects every publish
+type failingSink struct {
+	sink.Discard
+}
+
+func (failingSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	return fmt.Errorf("connection refused")
+}
+
+func TestPublishErrorLogsJSONFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	cfg := config.Default()
+	cfg.Generate.Duration = 50 * time.Millisecond
+	cfg.Generate.PublishRetries = 0
+	g, err := New(Options{Config: cfg, Sink: failingSink{}, TPS: 100, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r map[string]any
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		if r["msg"] == "generating trade failed" {
+			record = r
+			break
+		}
+	}
+	if record == nil {
+		t.Fatalf("no publish error logged in:\n%s", buf.String())
+	}
+	if record["level"] != "ERROR" {
+		t.Errorf("got level %v, want ERROR", record["level"])
+	}
+	if msg, _ := record["error"].(string); !strings.Contains(msg, "failed to publish") || !strings.Contains(msg, "connection refused") {
+		t.Errorf("got error %q, want the publish failure and its cause", msg)
+	}
+	if _, ok := record["time"]; !ok {
+		t.Error("record has no time")
+	}
+	if _, ok := record[logging.TextKey]; ok {
+		t.Error("JSON record carries the console text")
+	}
+}
+
+func TestJitteredTimestampsSpreadInsideTick(t *testing.T) {
+	for _, jitter := range []string{"uniform", "poisson"} {
+		cfg := config.Default()
+		cfg.Generate.TimestampJitter = jitter
+		recorder := &recordingSink{}
+		fake := clock.NewFake(testStart)
+		g, err := New(Options{Config: cfg, Sink: recorder, Clock: fake, TPS: 10, Seed: 1, TimingSeed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		interval := 100 * time.Millisecond
+		tickOf := make(map[time.Time]int)
+		jittered := 0
+		for tick := 0; tick < 500; tick++ {
+			now := fake.Now()
+			published := len(recorder.trades)
+			if err := g.generateAndPublish(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			for _, trade := range recorder.trades[published:] {
+				if !trade.Timestamp.After(now.Add(-interval)) || trade.Timestamp.After(now) {
+					t.Fatalf("%s: trade at %v, outside the tick (%v, %v]", jitter, trade.Timestamp, now.Add(-interval), now)
+				}
+				if other, seen := tickOf[trade.Timestamp]; seen && other != tick {
+					t.Fatalf("%s: ticks %d and %d both stamped %v", jitter, other, tick, trade.Timestamp)
+				}
+				tickOf[trade.Timestamp] = tick
+				if trade.Timestamp.Before(now) {
+					jittered++
+				}
+			}
+			fake.Advance(interval)
+		}
+
+		// Poisson gaps that overrun the tick are held at its end
+		if jittered < len(recorder.trades)/2 {
+			t.Errorf("%s: only %d of %d trades moved off the tick time", jitter, jittered, len(recorder.trades))
+		}
+	}
+}
+
+func TestInsiderAccumulatesBeforeNewsJump(t *testing.T) {
+	cfg := config.Default()
+	cfg.NewsEvents = []config.NewsEvent{{At: 10 * time.Minute, Symbol: "NVDA", Change: 0.2}}
+	recorder := &recordingSink{}
+	fake := clock.NewFake(testStart)
+	g, err := New(Options{Config: cfg, Sink: recorder, Labels: recorder, Clock: fake, TPS: 1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := g.patternGenerator.GetPrice("NVDA")
+
+	if _, err := g.GenerateN(context.Background(), 11*60); err != nil {
+		t.Fatal(err)
+	}
+
+	eventTime := testStart.Add(10 * time.Minute)
+	var insider *feed.Label
+	for _, label := range recorder.labels {
+		if label.FraudType == string(profiles.Insider) {
+			insider = label
+		}
+	}
+	if insider == nil {
+		t.Fatal("no insider accumulation before the news event")
+	}
+	accumulation := make(map[string]bool)
+	for _, id := range insider.TradeIDs {
+		accumulation[id.String()] = true
+	}
+	for _, trade := range recorder.trades {
+		if !accumulation[trade.ID.String()] {
+			continue
+		}
+		if trade.Symbol != "NVDA" || trade.Type != models.TradeTypeBuy {
+			t.Errorf("accumulation trade is a %s of %s, want NVDA buys", trade.Type, trade.Symbol)
+		}
+		if !trade.Timestamp.Before(eventTime) || trade.Timestamp.Before(eventTime.Add(-cfg.Generate.InsiderLead)) {
+			t.Errorf("accumulation buy at %v, want within the %v before the event", trade.Timestamp.Sub(testStart), cfg.Generate.InsiderLead)
+		}
+	}
+
+	if after := g.patternGenerator.GetPrice("NVDA"); math.Abs(after/before-1.2) > 0.05 {
+		t.Errorf("NVDA priced %.2f after the event, want about %.2f", after, before*1.2)
+	}
+}
+
+func TestIntervalRateFromKnownCount(t *testing.T) {
+	fake := clock.NewFake(testStart)
+	g, err := New(Options{Sink: sink.Discard{}, Clock: fake, TPS: 100, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := g.Stats()
+	last := stats.totals()
+
+	// 300 trades take 3s at 100 tps; a 2s stall stretches the interval to 5s
+	if _, err := g.GenerateN(context.Background(), 300); err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(2 * time.Second)
+	window := stats.totals().since(last)
+	stats.recordInterval(g.currentTPS(fake.Now()), window.Trades, fake.Now().Sub(testStart))
+
+	if window.Trades != 300 {
+		t.Fatalf("interval counted %d trades, want 300", window.Trades)
+	}
+	snap := stats.Snapshot()
+	if snap.RequestedTPS != 100 || snap.IntervalTPS != 60 || snap.TPSDeficit != 40 {
+		t.Errorf("got %d requested, %.1f interval and %.1f deficit tps, want 100, 60 and 40",
+			snap.RequestedTPS, snap.IntervalTPS, snap.TPSDeficit)
+	}
+}
+
+func TestWindowedStatsPerInterval(t *testing.T) {
+	recorder := &recordingSink{}
+	g, err := New(Options{Sink: recorder, Labels: recorder, Clock: clock.NewFake(testStart), Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := g.Stats()
+	ctx := context.Background()
+
+	// A quiet interval without fraud, then a busier one with a fraud burst
+	var windows []statsTotals
+	published := []int{0}
+	last := stats.totals()
+	for _, interval := range []struct {
+		trades    int
+		fraudRate float64
+	}{{200, 0}, {500, 0.5}} {
+		g.SetFraudRate(interval.fraudRate)
+		if _, err := g.GenerateN(ctx, interval.trades); err != nil {
+			t.Fatal(err)
+		}
+		totals := stats.totals()
+		windows = append(windows, totals.since(last))
+		published = append(published, len(recorder.trades))
+		last = totals
+	}
+
+	fraudIDs := make(map[string]bool)
+	for _, label := range recorder.labels {
+		for _, id := range label.TradeIDs {
+			fraudIDs[id.String()] = true
+		}
+	}
+	for i, window := range windows {
+		trades := recorder.trades[published[i]:published[i+1]]
+		var fraud int64
+		var volume float64
+		for _, trade := range trades {
+			if fraudIDs[trade.ID.String()] {
+				fraud++
+			}
+			volume += trade.Amount * trade.Price
+		}
+		if window.Trades != int64(len(trades)) || window.Fraud != fraud {
+			t.Errorf("interval %d: counted %d trades and %d fraud, want %d and %d", i+1, window.Trades, window.Fraud, len(trades), fraud)
+		}
+		if math.Abs(window.volume()-volume) > float64(len(trades))*0.01 {
+			t.Errorf("interval %d: counted $%.2f volume, want $%.2f", i+1, window.volume(), volume)
+		}
+	}
+
+	if windows[0].Trades != 200 || windows[0].Fraud != 0 || windows[0].fraudRate() != 0 {
+		t.Errorf("quiet interval: got %+v, want 200 trades and no fraud", windows[0])
+	}
+	// The burst shows in its own interval instead of diluted over both
+	if burst := windows[1].fraudRate(); burst < 0.3 || burst <= stats.totals().fraudRate() {
+		t.Errorf("burst interval fraud rate %.2f, cumulative %.2f", burst, stats.totals().fraudRate())
+	}
+}
+
+// haltSink records trades along with the halt markers between them
+type haltSink struct {
+	recordingSink
+	halts []*feed.Halt
+}
+
+func (s *haltSink) PublishHalt(ctx context.Context, halt *feed.Halt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.halts = append(s.halts, halt)
+	return nil
+}
+
+func TestLargeMoveHaltsSymbol(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Symbols = []string{"AAPL", "MSFT"}
+	cfg.Generate.HaltThreshold = 0.1
+	cfg.Generate.HaltDuration = time.Minute
+	cfg.Generate.HaltMarkers = true
+	out := &haltSink{}
+	g, err := New(Options{Config: cfg, Sink: out, Labels: out, Clock: clock.NewFake(testStart), TPS: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Let AAPL trade at its old level, then force a 20% jump
+	if _, err := g.GenerateN(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	g.patternGenerator.Jump("AAPL", 0.2)
+	if _, err := g.GenerateN(ctx, 1500); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out.halts) != 2 || out.halts[0].Symbol != "AAPL" || out.halts[0].Status != feed.Halted ||
+		out.halts[1].Symbol != "AAPL" || out.halts[1].Status != feed.Resumed {
+		t.Fatalf("got halt markers %+v, want AAPL halted then resumed", out.halts)
+	}
+	start, end := out.halts[0].Timestamp, out.halts[1].Timestamp
+	if got := end.Sub(start); got != time.Minute {
+		t.Errorf("halted for %v, want 1m", got)
+	}
+
+	var during, after, others int
+	for _, trade := range out.trades {
+		switch {
+		case trade.Symbol != "AAPL":
+			if trade.Timestamp.After(start) && trade.Timestamp.Before(end) {
+				others++
+			}
+		case trade.Timestamp.After(start) && trade.Timestamp.Before(end):
+			during++
+		case !trade.Timestamp.Before(end):
+			after++
+		}
+	}
+	if during != 0 {
+		t.Errorf("%d AAPL trades published during the halt", during)
+	}
+	if others == 0 {
+		t.Error("MSFT stopped trading during the AAPL halt")
+	}
+	if after == 0 {
+		t.Error("AAPL never resumed trading after the halt")
+	}
+	if g.Stats().HaltedTrades.Load() == 0 {
+		t.Error("no trades counted as halted")
+	}
+}
+
+func TestCancelRatioMatchesOrderToTrade(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.CancelOrders = true
+	trader := profiles.GetDefaultProfiles()[0]
+	trader.OrderToTrade = 4.5
+	recorder := recordTrades(t, Options{Config: cfg, Profiles: []profiles.TraderProfile{trader}, Seed: 1}, 20000)
+
+	// Every execution is preceded by 3.5 cancelled orders on average
+	var cancels, executions int
+	for _, trade := range recorder.trades {
+		if trade.Cancelled {
+			cancels++
+		} else {
+			executions++
+		}
+	}
+	if ratio := float64(cancels) / float64(executions); math.Abs(ratio-3.5) > 0.1 {
+		t.Errorf("got %.2f cancels per execution, want 3.5", ratio)
+	}
+}
+
+// lagSink reports a consumer group lag the test can raise
+type lagSink struct {
+	sink.Discard
+	lag int64
+}
+
+func (s *lagSink) StreamLag(ctx context.Context, stream, group string) (int64, error) {
+	return s.lag, nil
+}
+
+func TestRisingLagPushesFraudRateToMax(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.FraudLagGroup = "detectors"
+	cfg.Generate.FraudRateMax = 0.5
+	cfg.Generate.FraudLagMax = 10000
+	out := &lagSink{}
+	g, err := New(Options{Config: cfg, Sink: out, Clock: clock.NewFake(testStart), FraudRate: 0.1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// No lag holds the configured rate
+	g.adjustFraudRateForLag(ctx)
+	if rate := g.FraudRate(); math.Abs(rate-0.1) > 1e-9 {
+		t.Fatalf("fraud rate %.3f with no lag, want 0.1", rate)
+	}
+
+	// The consumer falls further behind each tick, then stays past the maximum lag
+	previous := g.FraudRate()
+	for tick := 1; tick <= 40; tick++ {
+		out.lag = min(int64(tick)*1000, 20000)
+		g.adjustFraudRateForLag(ctx)
+		rate := g.FraudRate()
+		if rate < previous || rate > 0.5 {
+			t.Fatalf("tick %d at lag %d: fraud rate went from %.3f to %.3f", tick, out.lag, previous, rate)
+		}
+		previous = rate
+	}
+	if math.Abs(previous-0.5) > 0.01 {
+		t.Errorf("fraud rate %.3f after sustained lag, want about the 0.5 maximum", previous)
+	}
+}
+
+func TestUserSessionsClusterConsecutiveTrades(t *testing.T) {
+	// Fraction of consecutive normal trades from the same trader
+	repeatRate := func(sessionTrades float64) float64 {
+		cfg := config.Default()
+		cfg.Generate.UserSessionTrades = sessionTrades
+		recorder := recordTrades(t, Options{Config: cfg, Seed: 1}, 5000)
+		repeats := 0
+		for i := 1; i < len(recorder.trades); i++ {
+			if recorder.trades[i].UserID == recorder.trades[i-1].UserID {
+				repeats++
+			}
+		}
+		return float64(repeats) / float64(len(recorder.trades)-1)
+	}
+
+	random, sessions := repeatRate(0), repeatRate(10)
+	// Each trader type keeps its own session, so types interleaving cut the
+	// repeats well below what the mean session length alone would give
+	if sessions < 2*random {
+		t.Errorf("%.3f of consecutive trades share a trader with sessions, %.3f with random selection", sessions, random)
+	}
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+)
+
+// haltTracker simulates exchange circuit breakers on the generation clock.
+// Each symbol's traded price is measured against a reference taken at the
+// start of its current window; a move of threshold or more halts the symbol
+// for the halt duration, during which its trades are suppressed. Trading
+// resumes at wherever the price has got to, so consumers see a gap followed
+// by a jump.
+type haltTracker struct {
+	threshold float64
+	window    time.Duration
+	duration  time.Duration
+	refs      map[string]priceRef  // Price each symbol's move is measured from
+	halts     map[string]time.Time // When each halted symbol resumes
+}
+
+// priceRef is a symbol's reference price and when it was taken
+type priceRef struct {
+	price float64
+	at    time.Time
+}
+
+// newHaltTracker returns a tracker for the configured circuit breaker, or nil
+// when halts are disabled
+func newHaltTracker(threshold float64, window, duration time.Duration) *haltTracker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &haltTracker{
+		threshold: threshold,
+		window:    window,
+		duration:  duration,
+		refs:      make(map[string]priceRef),
+		halts:     make(map[string]time.Time),
+	}
+}
+
+// halted reports whether symbol is halted at t
+func (h *haltTracker) halted(symbol string, t time.Time) bool {
+	end, exists := h.halts[symbol]
+	return exists && t.Before(end)
+}
+
+// observe records a trade price and reports the move if it trips the
+// breaker. The tripping trade itself prints; the halt starts after it.
+func (h *haltTracker) observe(symbol string, price float64, at time.Time) (float64, bool) {
+	ref, exists := h.refs[symbol]
+	if !exists || at.Sub(ref.at) >= h.window {
+		h.refs[symbol] = priceRef{price: price, at: at}
+		return 0, false
+	}
+
+	move := price/ref.price - 1
+	if math.Abs(move) < h.threshold {
+		return 0, false
+	}
+
+	// Measure afresh once trading resumes
+	delete(h.refs, symbol)
+	h.halts[symbol] = at.Add(h.duration)
+	return move, true
+}
+
+// due removes and returns, in symbol order, the halts that have ended by t
+func (h *haltTracker) due(t time.Time) []string {
+	var symbols []string
+	for symbol, end := range h.halts {
+		if !t.Before(end) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		delete(h.halts, symbol)
+	}
+	return symbols
+}
+
+// suppressHalted drops trades on halted symbols and trips the breaker for
+// those whose price has moved too far. Trades are judged at the time they are
+// generated, not their timestamps, which patterns spread ahead and anomalies
+// move off-hours. Once a trade trips the breaker the rest of its pattern on
+// that symbol is suppressed too. The remaining trades keep their order.
+func (g *Generator) suppressHalted(ctx context.Context, trades []*feed.Trade) []*feed.Trade {
+	now := g.clock.Now()
+	kept := trades[:0]
+	for _, trade := range trades {
+		if g.halts.halted(trade.Symbol, now) {
+			g.stats.HaltedTrades.Add(1)
+			continue
+		}
+		kept = append(kept, trade)
+
+		if move, tripped := g.halts.observe(trade.Symbol, trade.Price, now); tripped {
+			g.stats.Halts.Add(1)
+			slog.Warn("trading halted", "symbol", trade.Symbol, "move", move, "price", trade.Price,
+				"duration", g.halts.duration,
+				logging.Text("⏸️  Trading halted: %s moved %+.1f%% to %.2f, halted for %v",
+					trade.Symbol, move*100, trade.Price, g.halts.duration))
+			g.publishHalt(ctx, &feed.Halt{
+				Symbol:    trade.Symbol,
+				Status:    feed.Halted,
+				Price:     trade.Price,
+				Move:      move,
+				Timestamp: now,
+			})
+		}
+	}
+	return kept
+}
+
+// resumeHalts lifts halts whose duration has passed
+func (g *Generator) resumeHalts(ctx context.Context) {
+	now := g.clock.Now()
+	for _, symbol := range g.halts.due(now) {
+		slog.Info("trading resumed", "symbol", symbol,
+			logging.Text("▶️  Trading resumed: %s", symbol))
+		g.publishHalt(ctx, &feed.Halt{
+			Symbol:    symbol,
+			Status:    feed.Resumed,
+			Timestamp: now,
+		})
+	}
+}
+
+// publishHalt publishes a halt marker when markers are enabled. A failed
+// marker is logged rather than stopping generation.
+func (g *Generator) publishHalt(ctx context.Context, marker *feed.Halt) {
+	if !g.cfg.Generate.HaltMarkers {
+		return
+	}
+	publisher, ok := g.sink.(sink.HaltPublisher)
+	if !ok {
+		return
+	}
+	if err := publisher.PublishHalt(ctx, marker); err != nil {
+		logError("publishing halt marker failed", "Error publishing halt marker", err)
+	}
+}
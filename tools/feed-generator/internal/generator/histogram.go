@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// histogramBarWidth is the length of the longest bar in the text rendering
+const histogramBarWidth = 40
+
+// sizeHistogram counts trade amounts into fixed buckets. Each bound is the
+// inclusive upper edge of its bucket, and a final open-ended bucket catches
+// everything larger. Counts are atomic so publisher workers can observe
+// concurrently.
+type sizeHistogram struct {
+	bounds []float64
+	counts []atomic.Int64 // One per bound, plus the open-ended bucket
+}
+
+// SizeBucket is one histogram bucket in a statistics snapshot. Max is 0 for
+// the last bucket, which has no upper bound.
+type SizeBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+	Count int64   `json:"count"`
+}
+
+// newSizeHistogram creates a histogram over ascending bucket bounds
+func newSizeHistogram(bounds []float64) *sizeHistogram {
+	return &sizeHistogram{
+		bounds: bounds,
+		counts: make([]atomic.Int64, len(bounds)+1),
+	}
+}
+
+// observe counts one amount
+func (h *sizeHistogram) observe(amount float64) {
+	h.counts[sort.SearchFloat64s(h.bounds, amount)].Add(1)
+}
+
+// buckets returns a snapshot of the bucket counts
+func (h *sizeHistogram) buckets() []SizeBucket {
+	buckets := make([]SizeBucket, len(h.counts))
+	for i := range h.counts {
+		if i > 0 {
+			buckets[i].Min = h.bounds[i-1]
+		}
+		if i < len(h.bounds) {
+			buckets[i].Max = h.bounds[i]
+		}
+		buckets[i].Count = h.counts[i].Load()
+	}
+	return buckets
+}
+
+// writeHistogram renders buckets as a text bar chart, scaling bars to the
+// fullest bucket
+func writeHistogram(w io.Writer, buckets []SizeBucket) {
+	var total, peak int64
+	labels := make([]string, len(buckets))
+	width := 0
+	for i, bucket := range buckets {
+		total += bucket.Count
+		peak = max(peak, bucket.Count)
+		labels[i] = bucketLabel(bucket, i == len(buckets)-1)
+		width = max(width, len(labels[i]))
+	}
+	if total == 0 {
+		return
+	}
+
+	for i, bucket := range buckets {
+		// Pad by hand, since the bar's runes are wider than a byte
+		filled := int(bucket.Count * histogramBarWidth / peak)
+		bar := strings.Repeat("█", filled) + strings.Repeat(" ", histogramBarWidth-filled)
+		fmt.Fprintf(w, "  %-*s %s %d (%.1f%%)\n",
+			width, labels[i],
+			bar,
+			bucket.Count,
+			float64(bucket.Count)/float64(total)*100)
+	}
+}
+
+// bucketLabel describes a bucket's range, e.g. "100-1000" or ">1000"
+func bucketLabel(bucket SizeBucket, last bool) string {
+	if last {
+		return ">" + formatAmount(bucket.Min)
+	}
+	return formatAmount(bucket.Min) + "-" + formatAmount(bucket.Max)
+}
+
+// formatAmount prints a bucket bound without trailing zeros
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
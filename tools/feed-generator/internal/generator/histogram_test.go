@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSizeHistogramBucketsKnownAmounts(t *testing.T) {
+	h := newSizeHistogram([]float64{10, 100, 1000})
+	// Bounds are inclusive upper edges, so 10 and 100 fall in the lower bucket
+	for _, amount := range []float64{1, 10, 10.5, 50, 100, 999, 1000, 1000.01, 5000, 250000} {
+		h.observe(amount)
+	}
+
+	want := []SizeBucket{
+		{Min: 0, Max: 10, Count: 2},
+		{Min: 10, Max: 100, Count: 3},
+		{Min: 100, Max: 1000, Count: 2},
+		{Min: 1000, Count: 3},
+	}
+	got := h.buckets()
+	if len(got) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	writeHistogram(&buf, got)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("rendered %d lines, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	if !strings.Contains(lines[1], strings.Repeat("█", histogramBarWidth)) || !strings.HasSuffix(lines[1], "3 (30.0%)") {
+		t.Errorf("fullest bucket rendered as %q", lines[1])
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lines[3]), ">1000") {
+		t.Errorf("open-ended bucket rendered as %q", lines[3])
+	}
+}
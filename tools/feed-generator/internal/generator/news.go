@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+)
+
+// newsEvent tracks a scheduled news event through the run: insiders trade
+// ahead of it once its lead-up opens, then its price jump is released
+type newsEvent struct {
+	config.NewsEvent
+	accumulated bool
+	released    bool
+}
+
+// newNewsEvents returns the configured news events, or nil when none are set
+func newNewsEvents(cfg *config.Config) []*newsEvent {
+	var events []*newsEvent
+	for _, event := range cfg.NewsEvents {
+		events = append(events, &newsEvent{NewsEvent: event})
+	}
+	return events
+}
+
+// processNews injects insider accumulation for news events whose lead-up has
+// opened and releases the price jump of those that are due. With no insider
+// profile loaded the jump still happens, unanticipated.
+func (g *Generator) processNews(ctx context.Context) error {
+	now := g.clock.Now()
+	elapsed := now.Sub(g.startedAt)
+
+	for _, event := range g.news {
+		eventTime := g.startedAt.Add(event.At)
+
+		if !event.accumulated && elapsed >= event.At-g.cfg.Generate.InsiderLead {
+			event.accumulated = true
+			if profile := profiles.SelectFraudProfile(g.rng, g.profiles, profiles.Insider); profile != nil {
+				if err := g.accumulate(ctx, profile, event, eventTime); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !event.released && elapsed >= event.At {
+			event.released = true
+			slog.Info("news event", "symbol", event.Symbol, "change", event.Change, "at", event.At,
+				logging.Text("📰 News: %s %+.1f%%", event.Symbol, event.Change*100))
+			g.patternGenerator.Jump(event.Symbol, event.Change)
+		}
+	}
+	return nil
+}
+
+// accumulate enqueues an insider's buys ahead of a news event, less any that
+// fall in a trading halt
+func (g *Generator) accumulate(ctx context.Context, profile *profiles.TraderProfile, event *newsEvent, eventTime time.Time) error {
+	trades := g.patternGenerator.InjectInsiderAccumulation(profile, event.Symbol, eventTime)
+	if g.halts != nil {
+		if trades = g.suppressHalted(ctx, trades); len(trades) == 0 {
+			return nil
+		}
+	}
+
+	for _, trade := range trades {
+		g.annotateTrade(trade, profile)
+	}
+	return g.enqueue(ctx, trades, profile, true)
+}
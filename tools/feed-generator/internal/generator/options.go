@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/sink"
+)
+
+// Options configures a generator embedded in another program, such as a
+// detection system integration test, without going through Viper. Zero values
+// take the built-in defaults.
+type Options struct {
+	Sink     sink.Sink                // nil discards published trades
+	Labels   sink.LabelSink           // nil skips ground-truth labels
+	Profiles []profiles.TraderProfile // nil uses the built-in profiles
+	Clock    clock.Clock              // nil uses the wall clock
+
+	TPS        int
+	Duration   time.Duration
+	FraudRate  float64 // 0 = no fraud
+	FraudEvery int     // Fraud pattern every Nth tick instead of FraudRate, 0 = off
+	FraudType  string  // Empty = ALL
+	Seed       int64   // 0 = random
+	TimingSeed int64   // 0 = random
+
+	// Config holds every other setting; nil uses config.Default(). The
+	// fields above override it when set.
+	Config *config.Config
+}
+
+// New creates a generator from plain options, validating the configuration
+// and profiles the same way the CLI does
+func New(opts Options) (*Generator, error) {
+	cfg := config.Default()
+	if opts.Config != nil {
+		copied := *opts.Config
+		cfg = &copied
+	}
+	if opts.TPS != 0 {
+		cfg.Generate.TPS = opts.TPS
+	}
+	if opts.Duration != 0 {
+		cfg.Generate.Duration = opts.Duration
+	}
+	if opts.FraudRate != 0 {
+		cfg.Generate.FraudRate = opts.FraudRate
+	}
+	if opts.FraudEvery != 0 {
+		cfg.Generate.FraudEvery = opts.FraudEvery
+	}
+	if opts.FraudType != "" {
+		cfg.Generate.FraudType = opts.FraudType
+	}
+	if opts.Seed != 0 {
+		cfg.Generate.Seed = opts.Seed
+	}
+	if opts.TimingSeed != 0 {
+		cfg.Generate.TimingSeed = opts.TimingSeed
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	traderProfiles := opts.Profiles
+	if traderProfiles == nil {
+		traderProfiles = profiles.GetDefaultProfiles()
+	}
+	out := opts.Sink
+	if out == nil {
+		out = sink.Discard{}
+	}
+
+	g := NewGenerator(cfg, traderProfiles, out, opts.Labels)
+	if opts.Clock != nil {
+		g.SetClock(opts.Clock)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// maxEmptySteps bounds how many steps in a row GenerateN tolerates producing
+// nothing on a real clock, where waiting in a loop cannot move time forward
+const maxEmptySteps = 10000
+
+// GenerateN synchronously generates n trades without the ticker, publishing
+// them to the sink as Run would and returning them in emission order. Each
+// step moves prices one tick forward, and a fake clock is advanced one tick
+// too, so trades are spaced as in a run at the configured TPS. A fraud pattern
+// straddling the nth trade is cut short in the result. With a real clock,
+// which steps don't advance, it fails after maxEmptySteps steps in a row
+// produce nothing, such as outside market hours or while every symbol is
+// halted.
+func (g *Generator) GenerateN(ctx context.Context, n int) ([]*models.Trade, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("trade count must be non-negative, got %d", n)
+	}
+
+	out := g.sink
+	collector := &collectingSink{Sink: out}
+	g.sink = collector
+	defer func() { g.sink = out }()
+
+	interval := time.Second / time.Duration(g.TPS())
+	fake, _ := g.clock.(*clock.Fake)
+	empty := 0
+	for produced := 0; produced < n; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		g.patternGenerator.StepPrices(interval)
+		if err := g.generateAndPublish(ctx); err != nil {
+			return nil, err
+		}
+		if fake != nil {
+			fake.Advance(interval)
+		}
+
+		before := produced
+		produced = len(collector.trades) + g.pendingTrades
+		if produced > before {
+			empty = 0
+		} else if empty++; fake == nil && empty >= maxEmptySteps {
+			return nil, fmt.Errorf("no trades generated in %d steps (after %d of %d); outside market hours or halted?", empty, produced, n)
+		}
+	}
+	if err := g.flush(ctx); err != nil {
+		return nil, err
+	}
+
+	trades := make([]*models.Trade, n)
+	for i := range trades {
+		trades[i] = collector.trades[i].Trade
+	}
+	return trades, nil
+}
+
+// collectingSink records the trades its wrapped sink accepts
+type collectingSink struct {
+	sink.Sink
+	trades []*feed.Trade
+}
+
+func (c *collectingSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	if err := c.Sink.Publish(ctx, trade); err != nil {
+		return err
+	}
+	c.trades = append(c.trades, trade)
+	return nil
+}
+
+func (c *collectingSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	if err := c.Sink.PublishBatch(ctx, trades); err != nil {
+		return err
+	}
+	c.trades = append(c.trades, trades...)
+	return nil
+}
+
+// PublishHalt passes halt markers through to the wrapped sink, if it carries them
+func (c *collectingSink) PublishHalt(ctx context.Context, halt *feed.Halt) error {
+	if publisher, ok := c.Sink.(sink.HaltPublisher); ok {
+		return publisher.PublishHalt(ctx, halt)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+func TestGenerateNFailsWhenNothingCanBeGenerated(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.MarketHours = true
+	cfg.Session.Timezone = "UTC"
+	// A one-minute session two hours from now is closed for the whole test
+	opens := time.Now().UTC().Add(2 * time.Hour)
+	cfg.Session.Open = opens.Format("15:04")
+	cfg.Session.Close = opens.Add(time.Minute).Format("15:04")
+
+	g, err := New(Options{Config: cfg, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := g.GenerateN(ctx, 10); err == nil || ctx.Err() != nil {
+		t.Fatalf("want an error before the deadline, got %v", err)
+	}
+}
+
+func TestGenerateNWaitsForSessionOnFakeClock(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	cfg := config.Default()
+	cfg.Generate.MarketHours = true
+
+	// 30 minutes before the open at 10 TPS is more empty steps than a real
+	// clock tolerates; the fake clock advances into the session instead
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, newYork)
+	g, err := New(Options{Config: cfg, Clock: clock.NewFake(start), TPS: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trades, err := g.GenerateN(context.Background(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 10 {
+		t.Errorf("got %d trades, want 10", len(trades))
+	}
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+)
+
+// publisherPool publishes flushed batches on worker goroutines, so sink
+// latency doesn't hold up generation. Each batch is published whole by one
+// worker, keeping fraud patterns contiguous; batches may land out of order.
+type publisherPool struct {
+	batches chan []pendingGroup
+	wg      sync.WaitGroup
+	cancel  context.CancelFunc // Aborts in-flight publishes once shutdown gives up
+
+	drop      bool        // Drop batches when the queue is full instead of blocking
+	dropped   func(n int) // Counts trades dropped from a full queue
+	saturated atomic.Bool // Whether the queue is full, so the warning fires once per episode
+}
+
+// startPublishers starts the configured number of publisher workers. Workers
+// outlive the run context so batches queued at shutdown still drain.
+func (g *Generator) startPublishers(ctx context.Context) {
+	workers := g.cfg.Generate.Workers
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	pool := &publisherPool{
+		batches: make(chan []pendingGroup, g.cfg.Generate.QueueSize),
+		cancel:  cancel,
+		drop:    g.cfg.Generate.QueueFull == "drop",
+		dropped: func(n int) { g.stats.DroppedTrades.Add(int64(n)) },
+	}
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for groups := range pool.batches {
+				if err := g.publish(ctx, groups); err != nil {
+					logError("publishing trades failed", "Error publishing trades", err)
+				}
+			}
+		}()
+	}
+
+	g.publishers = pool
+}
+
+// submit queues a batch for the workers. While the queue is full it either
+// blocks, so generation can't outrun the sink, or drops the batch and counts
+// its trades, so generation keeps its rate. Either way a warning is logged
+// each time the queue saturates.
+func (p *publisherPool) submit(ctx context.Context, groups []pendingGroup) error {
+	select {
+	case p.batches <- groups:
+		p.saturated.Store(false)
+		return nil
+	default:
+	}
+
+	if !p.saturated.Swap(true) {
+		p.warnSaturated()
+	}
+
+	if p.drop {
+		p.dropped(countTrades(groups))
+		return nil
+	}
+
+	select {
+	case p.batches <- groups:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("dropped %d queued trade groups: %w", len(groups), ctx.Err())
+	}
+}
+
+// warnSaturated logs that the publishers have fallen behind generation
+func (p *publisherPool) warnSaturated() {
+	if p.drop {
+		slog.Warn("publish queue full, dropping trades", "queue_size", cap(p.batches),
+			logging.Text("⚠️  Warning: publish queue full (%d batches), dropping trades until publishers catch up", cap(p.batches)))
+		return
+	}
+	slog.Warn("publish queue full, generation stalled", "queue_size", cap(p.batches),
+		logging.Text("⚠️  Warning: publish queue full (%d batches), generation is waiting on publishers and running below the requested TPS", cap(p.batches)))
+}
+
+// countTrades returns the number of trades across groups
+func countTrades(groups []pendingGroup) int {
+	n := 0
+	for _, group := range groups {
+		n += len(group.trades)
+	}
+	return n
+}
+
+// stop waits for the workers to drain the queue, abandoning in-flight
+// publishes if ctx ends first
+func (p *publisherPool) stop(ctx context.Context) {
+	close(p.batches)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.Warn("publishers still busy at shutdown, abandoning queued trades",
+			logging.Text("⚠️  Warning: publishers still busy at shutdown, abandoning queued trades"))
+		p.cancel()
+		<-done
+	}
+	p.cancel()
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+// Run with -race: workers publish concurrently with generation and statistics
+func TestPublisherPoolLosesNoTrades(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Workers = 4
+	cfg.Generate.QueueSize = 8
+	cfg.Generate.BatchSize = 16
+	recorder := &recordingSink{}
+	g, err := New(Options{Config: cfg, Sink: recorder, Labels: recorder, Clock: clock.NewFake(testStart), FraudRate: 0.1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	g.startPublishers(ctx)
+	for i := 0; i < 5000; i++ {
+		if err := g.generateAndPublish(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.flushRemaining(ctx)
+
+	if got := int64(len(recorder.trades)); got != g.generated {
+		t.Errorf("published %d of %d generated trades", got, g.generated)
+	}
+	if got := g.stats.TotalTrades.Load(); got != g.generated {
+		t.Errorf("statistics counted %d of %d generated trades", got, g.generated)
+	}
+	seen := make(map[string]bool)
+	for _, trade := range recorder.trades {
+		if seen[trade.ID.String()] {
+			t.Fatalf("trade %s published twice", trade.ID)
+		}
+		seen[trade.ID.String()] = true
+	}
+}
+
+func TestSlowSinkDropsWithoutStalling(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Workers = 1
+	cfg.Generate.QueueSize = 2
+	cfg.Generate.BatchSize = 10
+	cfg.Generate.QueueFull = "drop"
+	out := &shutdownSink{delay: 20 * time.Millisecond}
+	g, err := New(Options{Config: cfg, Sink: out, Labels: out, Clock: clock.NewFake(testStart), FraudRate: 0.1, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocking on this sink would take seconds; dropping keeps generation going
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() {
+		g.startPublishers(ctx)
+		for i := 0; i < 2000; i++ {
+			if err := g.generateAndPublish(ctx); err != nil {
+				done <- err
+				return
+			}
+		}
+		g.flushRemaining(ctx)
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("generation deadlocked behind the slow sink")
+	}
+
+	dropped := g.stats.DroppedTrades.Load()
+	if dropped == 0 {
+		t.Fatal("no trades dropped behind a sink slower than generation")
+	}
+	out.mu.Lock()
+	published := int64(len(out.trades))
+	out.mu.Unlock()
+	if published+dropped != g.generated {
+		t.Errorf("published %d and dropped %d of %d generated trades", published, dropped, g.generated)
+	}
+}
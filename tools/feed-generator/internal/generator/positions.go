@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// positionKey identifies one account's holding in one symbol
+type positionKey struct {
+	user   string
+	symbol string
+}
+
+// positions tracks running holdings so normal traders never sell shares they
+// do not hold. Only the generation loop touches it, so it needs no locking.
+type positions struct {
+	holdings map[positionKey]float64 // Shares held
+	mode     feed.AmountMode
+}
+
+// newPositions returns an empty position book, or nil when positions are not
+// enforced
+func newPositions(cfg *config.Config) *positions {
+	if !cfg.Generate.EnforcePositions {
+		return nil
+	}
+	return &positions{
+		holdings: make(map[positionKey]float64),
+		mode:     feed.AmountMode(cfg.Generate.AmountMode),
+	}
+}
+
+// apply books a trade against its account's holding. A sell with nothing held
+// becomes a buy; a sell larger than the holding is clamped to close it out.
+func (p *positions) apply(trade *feed.Trade) {
+	key := positionKey{user: trade.UserID, symbol: trade.Symbol}
+	held := p.holdings[key]
+	shares := p.mode.Shares(trade.Amount, trade.Price)
+
+	if trade.Type == models.TradeTypeSell {
+		switch {
+		case held <= 0:
+			trade.Type = models.TradeTypeBuy
+		case shares > held:
+			shares = held
+			trade.Amount = p.mode.Amount(held, trade.Price)
+		}
+	}
+
+	if trade.Type == models.TradeTypeBuy {
+		p.holdings[key] = held + shares
+	} else {
+		p.holdings[key] = held - shares
+	}
+}
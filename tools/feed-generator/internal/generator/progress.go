@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressInterval is how often the progress line is redrawn
+const progressInterval = time.Second
+
+// progressPercent returns how far through a run of the given total duration
+// the elapsed time is, from 0 to 100. An infinite run (total 0) has no
+// percentage and reports 0.
+func progressPercent(elapsed, total time.Duration) float64 {
+	if total <= 0 || elapsed <= 0 {
+		return 0
+	}
+	if elapsed >= total {
+		return 100
+	}
+	return float64(elapsed) / float64(total) * 100
+}
+
+// formatProgress renders the progress line: elapsed/total and percentage, or
+// elapsed only for an infinite run
+func formatProgress(elapsed, total time.Duration) string {
+	if total <= 0 {
+		return fmt.Sprintf("⏳ %s elapsed", formatDuration(elapsed))
+	}
+	if elapsed > total {
+		elapsed = total
+	}
+	return fmt.Sprintf("⏳ %s / %s (%.1f%%)",
+		formatDuration(elapsed), formatDuration(total), progressPercent(elapsed, total))
+}
+
+// startProgress redraws the progress line on w in place until the returned
+// stop function is called, measuring against the run deadline (zero for an
+// infinite run). stop ends the line so later output starts on a fresh one.
+func (g *Generator) startProgress(ctx context.Context, w io.Writer, deadline time.Time) (stop func()) {
+	start := g.clock.Now()
+	var total time.Duration
+	if !deadline.IsZero() {
+		total = g.cfg.Generate.Duration
+		start = deadline.Add(-total)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		draw := func() {
+			fmt.Fprintf(w, "\r\033[K%s", formatProgress(g.clock.Now().Sub(start), total))
+		}
+		draw()
+		for {
+			select {
+			case <-ctx.Done():
+				draw()
+				fmt.Fprintln(w)
+				return
+			case <-ticker.C:
+				draw()
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressPercent(t *testing.T) {
+	tests := []struct {
+		elapsed, total time.Duration
+		want           float64
+	}{
+		{0, 10 * time.Minute, 0},
+		{time.Minute, 10 * time.Minute, 10},
+		{150 * time.Second, 10 * time.Minute, 25},
+		{10 * time.Minute, 10 * time.Minute, 100},
+		{11 * time.Minute, 10 * time.Minute, 100}, // Shutdown overran the deadline
+		{time.Minute, 0, 0},                       // Infinite run
+	}
+	for _, tt := range tests {
+		if got := progressPercent(tt.elapsed, tt.total); got != tt.want {
+			t.Errorf("%v of %v: got %.1f%%, want %.1f%%", tt.elapsed, tt.total, got, tt.want)
+		}
+	}
+
+	if got, want := formatProgress(150*time.Second, 10*time.Minute), "⏳ 02:30 / 10:00 (25.0%)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := formatProgress(90*time.Second, 0), "⏳ 01:30 elapsed"; got != want {
+		t.Errorf("infinite run: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// StatsSnapshot is a point-in-time copy of the generation statistics, shared
+// by the text summary and the JSON report
+type StatsSnapshot struct {
+	Duration         time.Duration    `json:"-"`
+	DurationSeconds  float64          `json:"duration_seconds"`
+	TotalTrades      int64            `json:"total_trades"`
+	FraudTrades      int64            `json:"fraud_trades"`
+	FraudRate        float64          `json:"fraud_rate"` // Fraction of trades from fraud patterns
+	Throughput       float64          `json:"throughput_tps"`
+	RequestedTPS     int64            `json:"requested_tps"` // Target rate at the last stats report
+	IntervalTPS      float64          `json:"interval_tps"`  // Rate over the last stats interval
+	TPSDeficit       float64          `json:"tps_deficit"`   // Shortfall of IntervalTPS against RequestedTPS
+	TotalVolume      float64          `json:"total_volume"`
+	UniqueAccounts   int64            `json:"unique_accounts"`
+	UniqueSymbols    int64            `json:"unique_symbols"`
+	SkewedTimestamps int64            `json:"skewed_timestamps"`
+	LabelsWritten    int64            `json:"labels_written"`
+	PublishRetries   int64            `json:"publish_retries"`
+	DroppedTrades    int64            `json:"dropped_trades"`
+	Halts            int64            `json:"halts"`
+	HaltedTrades     int64            `json:"halted_trades"`
+	CancelledOrders  int64            `json:"cancelled_orders"`
+	OrderToTrade     float64          `json:"order_to_trade"` // Records published per execution, cancels included
+	ByProfile        map[string]int64 `json:"by_profile"`
+	BySymbol         map[string]int64 `json:"by_symbol"`
+	SizeHistogram    []SizeBucket     `json:"size_histogram"`
+}
+
+// Snapshot returns the current statistics, safe to call while generating
+func (s *Statistics) Snapshot() StatsSnapshot {
+	elapsed := time.Since(s.StartTime)
+	snap := StatsSnapshot{
+		Duration:         elapsed,
+		DurationSeconds:  elapsed.Seconds(),
+		TotalTrades:      s.TotalTrades.Load(),
+		FraudTrades:      s.FraudPatterns.Load(),
+		TotalVolume:      float64(s.VolumeGenerated.Load()) / 100.0,
+		UniqueAccounts:   s.UniqueAccounts.Load(),
+		UniqueSymbols:    s.UniqueSymbols.Load(),
+		SkewedTimestamps: s.SkewedTimestamps.Load(),
+		LabelsWritten:    s.LabelsWritten.Load(),
+		PublishRetries:   s.PublishRetries.Load(),
+		DroppedTrades:    s.DroppedTrades.Load(),
+		Halts:            s.Halts.Load(),
+		HaltedTrades:     s.HaltedTrades.Load(),
+		CancelledOrders:  s.CancelledOrders.Load(),
+		RequestedTPS:     s.RequestedTPS(),
+		IntervalTPS:      s.IntervalTPS(),
+		TPSDeficit:       s.TPSDeficit(),
+		ByProfile:        s.ProfileCounts(),
+		BySymbol:         s.SymbolCounts(),
+	}
+	if s.sizes != nil {
+		snap.SizeHistogram = s.sizes.buckets()
+	}
+	if snap.TotalTrades > 0 {
+		snap.FraudRate = float64(snap.FraudTrades) / float64(snap.TotalTrades)
+	}
+	if executions := snap.TotalTrades - snap.CancelledOrders; executions > 0 {
+		snap.OrderToTrade = float64(snap.TotalTrades) / float64(executions)
+	}
+	if elapsed > 0 {
+		snap.Throughput = float64(snap.TotalTrades) / elapsed.Seconds()
+	}
+	return snap
+}
+
+// writeStatsFile writes a snapshot as indented JSON
+func writeStatsFile(path string, snap StatsSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode statistics: %w", err)
+	}
+
+	if path == "-" {
+		fmt.Printf("%s\n", data)
+		return nil
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write statistics: %w", err)
+	}
+	return nil
+}
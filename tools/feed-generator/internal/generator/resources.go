@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// resourceUsage is a snapshot of the generator's own resource consumption
+type resourceUsage struct {
+	Goroutines   int
+	HeapAlloc    uint64
+	HeapSys      uint64
+	TotalAlloc   uint64
+	NumGC        uint32
+	GCPauseTotal time.Duration
+	CPUTime      time.Duration
+}
+
+// CPU time metrics: total is available CPU time across GOMAXPROCS, so time
+// actually spent is total minus idle
+const (
+	cpuTotalMetric = "/cpu/classes/total:cpu-seconds"
+	cpuIdleMetric  = "/cpu/classes/idle:cpu-seconds"
+)
+
+// sampleResources reads the current runtime memory, GC and CPU statistics
+func sampleResources() resourceUsage {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	samples := []metrics.Sample{{Name: cpuTotalMetric}, {Name: cpuIdleMetric}}
+	metrics.Read(samples)
+
+	var cpuSeconds float64
+	if samples[0].Value.Kind() == metrics.KindFloat64 && samples[1].Value.Kind() == metrics.KindFloat64 {
+		cpuSeconds = samples[0].Value.Float64() - samples[1].Value.Float64()
+	}
+
+	return resourceUsage{
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapSys:      mem.HeapSys,
+		TotalAlloc:   mem.TotalAlloc,
+		NumGC:        mem.NumGC,
+		GCPauseTotal: time.Duration(mem.PauseTotalNs),
+		CPUTime:      time.Duration(cpuSeconds * float64(time.Second)),
+	}
+}
+
+// formatBytes formats a byte count with a binary unit suffix
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"math"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+// rateSchedule returns the target TPS once elapsed time has passed since the
+// run started
+type rateSchedule func(elapsed time.Duration) int
+
+// newRateSchedule builds the configured ramp or step schedule, or returns nil
+// for a constant rate. The schedule was checked by config validation.
+func newRateSchedule(cfg *config.Config) rateSchedule {
+	gen := cfg.Generate
+
+	if gen.Schedule != "" {
+		steps, err := config.ParseSchedule(gen.Schedule)
+		if err != nil {
+			return nil
+		}
+		return func(elapsed time.Duration) int {
+			// Before the first step the run uses the configured TPS
+			tps := gen.TPS
+			for _, step := range steps {
+				if elapsed < step.At {
+					break
+				}
+				tps = step.TPS
+			}
+			return tps
+		}
+	}
+
+	if gen.RampFrom > 0 {
+		return func(elapsed time.Duration) int {
+			progress := math.Min(1, float64(elapsed)/float64(gen.Duration))
+			return int(math.Round(float64(gen.RampFrom) + progress*float64(gen.RampTo-gen.RampFrom)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+func TestRampSchedule(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.Duration = 10 * time.Minute
+	cfg.Generate.RampFrom = 100
+	cfg.Generate.RampTo = 1100
+
+	schedule := newRateSchedule(cfg)
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{0, 100},
+		{time.Minute, 200},
+		{5 * time.Minute, 600},
+		{10 * time.Minute, 1100},
+		{time.Hour, 1100}, // Holds at the end rate past the duration
+	}
+	for _, tt := range tests {
+		if got := schedule(tt.elapsed); got != tt.want {
+			t.Errorf("ramp at %v: got %d, want %d", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestStepSchedule(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.TPS = 50
+	cfg.Generate.Schedule = "100@10s,500@1m"
+
+	schedule := newRateSchedule(cfg)
+	tests := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{0, 50}, // Configured TPS before the first step
+		{10 * time.Second, 100},
+		{59 * time.Second, 100},
+		{time.Minute, 500},
+		{time.Hour, 500},
+	}
+	for _, tt := range tests {
+		if got := schedule(tt.elapsed); got != tt.want {
+			t.Errorf("schedule at %v: got %d, want %d", tt.elapsed, got, tt.want)
+		}
+	}
+}
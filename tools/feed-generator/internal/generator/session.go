@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+)
+
+// session is the trading session enforced in market-hours mode
+type session struct {
+	location *time.Location
+	open     time.Duration // Offset from midnight in the session timezone
+	close    time.Duration
+	hours    *time.Location // Zone profile active hours are read in
+
+	// Profiles active in the cached day and hour, rebuilt when either changes
+	activeDay  time.Weekday
+	activeHour int
+	active     []profiles.TraderProfile
+}
+
+// newSession returns the configured trading session, or nil when market-hours
+// mode is off. The session was checked by config validation.
+func newSession(cfg *config.Config) *session {
+	if !cfg.Generate.MarketHours {
+		return nil
+	}
+
+	location, open, close, err := cfg.Session.Parse()
+	if err != nil {
+		return nil
+	}
+
+	// Active hours follow the session timezone unless a market timezone is set
+	hours := location
+	if cfg.Generate.Timezone != "" {
+		hours = cfg.Generate.Location
+	}
+
+	return &session{location: location, open: open, close: close, hours: hours, activeHour: -1}
+}
+
+// isOpen reports whether t falls on a weekday inside the session. A close
+// earlier than the open wraps past midnight.
+func (s *session) isOpen(t time.Time) bool {
+	local := t.In(s.location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if s.open < s.close {
+		return offset >= s.open && offset < s.close
+	}
+	return offset >= s.open || offset < s.close
+}
+
+// activeProfiles returns the profiles whose active days and hours include
+// the day and hour of t, falling back to all profiles when none are active
+func (s *session) activeProfiles(all []profiles.TraderProfile, t time.Time) []profiles.TraderProfile {
+	local := t.In(s.hours)
+	day, hour := local.Weekday(), local.Hour()
+	if day == s.activeDay && hour == s.activeHour {
+		return s.active
+	}
+
+	var active []profiles.TraderProfile
+	for i := range all {
+		if all[i].Type != profiles.FraudTrader && all[i].IsActiveOn(day) && all[i].IsActiveAt(hour) {
+			active = append(active, all[i])
+		}
+	}
+	if len(active) == 0 {
+		active = all
+	}
+
+	s.activeDay = day
+	s.activeHour = hour
+	s.active = active
+	return active
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+)
+
+func TestMarketHoursStopNormalTradesAtClose(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	cfg := config.Default()
+	cfg.Generate.MarketHours = true
+	closes := time.Date(2026, 3, 2, 16, 0, 0, 0, newYork)
+
+	// Ten minutes of session at one trade a second, then fraud alone after the close
+	recorder := recordTrades(t, Options{
+		Config:    cfg,
+		Clock:     clock.NewFake(closes.Add(-10 * time.Minute)),
+		TPS:       1,
+		FraudRate: 0.1,
+		FraudType: "VELOCITY",
+		Seed:      1,
+	}, 1500)
+
+	fraud := make(map[string]bool)
+	afterClose := 0
+	for _, label := range recorder.labels {
+		for _, id := range label.TradeIDs {
+			fraud[id.String()] = true
+		}
+		if !label.InjectedAt.Before(closes) {
+			afterClose++
+		}
+	}
+	if afterClose == 0 {
+		t.Error("no fraud patterns injected after the close")
+	}
+	normal := 0
+	for _, trade := range recorder.trades {
+		if fraud[trade.ID.String()] {
+			continue
+		}
+		normal++
+		if !trade.Timestamp.Before(closes) {
+			t.Fatalf("normal trade by %s at %v, after the close", trade.UserID, trade.Timestamp.In(newYork))
+		}
+	}
+	if normal == 0 {
+		t.Error("no normal trades before the close")
+	}
+}
+
+func TestFakeClockCrossesActiveHourBoundary(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.MarketHours = true
+	cfg.Generate.Timezone = "UTC"
+	cfg.Generate.Location = time.UTC
+	morning := profiles.GetDefaultProfiles()[0]
+	morning.UserID, morning.ActiveHours = "MORNING", []int{14}
+	afternoon := profiles.GetDefaultProfiles()[0]
+	afternoon.UserID, afternoon.ActiveHours = "AFTERNOON", []int{15}
+
+	// Ten simulated minutes either side of 15:00 UTC, mid-session in New York,
+	// without waiting on the wall clock
+	boundary := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	recorder := recordTrades(t, Options{
+		Config:   cfg,
+		Profiles: []profiles.TraderProfile{morning, afternoon},
+		Clock:    clock.NewFake(boundary.Add(-10 * time.Minute)),
+		TPS:      1,
+		Seed:     1,
+	}, 1200)
+
+	before, after := 0, 0
+	for _, trade := range recorder.trades {
+		want := "MORNING"
+		if !trade.Timestamp.Before(boundary) {
+			want = "AFTERNOON"
+			after++
+		} else {
+			before++
+		}
+		if trade.UserID != want {
+			t.Fatalf("%s traded at %v, want only %s", trade.UserID, trade.Timestamp.Format("15:04:05"), want)
+		}
+	}
+	if before != 600 || after != 600 {
+		t.Errorf("got %d trades before 15:00 and %d after, want 600 each", before, after)
+	}
+}
+
+func TestSessionClosesFromFridayToSaturday(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	cfg := config.Default()
+	cfg.Generate.MarketHours = true
+	cfg.Session = config.SessionConfig{Open: "18:00", Close: "17:00", Timezone: "America/New_York"} // Overnight, futures style
+	s := newSession(cfg)
+
+	allDay := make([]int, 24)
+	for hour := range allDay {
+		allDay[hour] = hour
+	}
+	weekday := profiles.TraderProfile{UserID: "WEEKDAY", ActiveHours: allDay}
+	weekend := profiles.TraderProfile{UserID: "WEEKEND", ActiveHours: allDay, ActiveDays: []time.Weekday{time.Saturday, time.Sunday}}
+	all := []profiles.TraderProfile{weekday, weekend}
+
+	fake := clock.NewFake(time.Date(2026, 3, 6, 23, 50, 0, 0, newYork)) // Friday
+	if !s.isOpen(fake.Now()) {
+		t.Fatal("overnight session closed late on Friday")
+	}
+	if active := s.activeProfiles(all, fake.Now()); len(active) != 1 || active[0].UserID != "WEEKDAY" {
+		t.Errorf("Friday: got %d active profiles, want only WEEKDAY", len(active))
+	}
+
+	fake.Advance(20 * time.Minute) // Past midnight into Saturday
+	if s.isOpen(fake.Now()) {
+		t.Errorf("session open at %v", fake.Now().In(newYork).Format("Mon 15:04"))
+	}
+	if active := s.activeProfiles(all, fake.Now()); len(active) != 1 || active[0].UserID != "WEEKEND" {
+		t.Errorf("Saturday: got %d active profiles, want only WEEKEND", len(active))
+	}
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/patterns"
+)
+
+// runState is the evolving state of a run, written to the snapshot file so a
+// restarted run continues where the last snapshot left off
+type runState struct {
+	SavedAt    time.Time           `json:"saved_at"`
+	Seq        uint64              `json:"seq"`       // Last assigned sequence number
+	Generated  int64               `json:"generated"` // Trades generated, for the max-trades cap
+	Ticks      int                 `json:"ticks"`
+	Seed       int64               `json:"seed"` // Trade content source, reseeded at the snapshot
+	TimingSeed int64               `json:"timing_seed"`
+	Prices     patterns.PriceState `json:"prices"`
+	Positions  []positionState     `json:"positions,omitempty"`
+}
+
+// positionState is one account's holding in one symbol
+type positionState struct {
+	UserID string  `json:"user_id"`
+	Symbol string  `json:"symbol"`
+	Shares float64 `json:"shares"`
+}
+
+// captureState records the run's state. A math/rand source can't export its
+// state, so both sources are reseeded from their own next draws and the seeds
+// saved: the run carries on exactly as a run resumed from the snapshot would.
+// It must run on the generation loop, which owns the prices and positions.
+func (g *Generator) captureState() runState {
+	state := runState{
+		SavedAt:    time.Now(),
+		Seq:        g.seq.Load(),
+		Generated:  g.generated,
+		Ticks:      g.ticks,
+		Seed:       g.rng.Int63(),
+		TimingSeed: g.timing.Int63(),
+		Prices:     g.patternGenerator.PriceState(),
+	}
+	g.rng.Seed(state.Seed)
+	g.timing.Seed(state.TimingSeed)
+
+	if g.positions != nil {
+		for key, shares := range g.positions.holdings {
+			state.Positions = append(state.Positions, positionState{UserID: key.user, Symbol: key.symbol, Shares: shares})
+		}
+	}
+	return state
+}
+
+// restoreState resumes the run from a snapshot
+func (g *Generator) restoreState(state runState) {
+	g.seq.Store(state.Seq)
+	g.generated = state.Generated
+	g.ticks = state.Ticks
+	g.rng.Seed(state.Seed)
+	g.timing.Seed(state.TimingSeed)
+	g.patternGenerator.RestorePriceState(state.Prices)
+
+	if g.positions != nil {
+		for _, position := range state.Positions {
+			g.positions.holdings[positionKey{user: position.UserID, symbol: position.Symbol}] = position.Shares
+		}
+	}
+}
+
+// saveSnapshot writes the run's state to path. It writes a temporary file and
+// renames it into place, so a crash mid-write leaves the previous snapshot.
+func (g *Generator) saveSnapshot(path string) error {
+	data, err := json.MarshalIndent(g.captureState(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// loadSnapshot resumes the run from the snapshot at path
+func (g *Generator) loadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var state runState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse snapshot %s: %w", path, err)
+	}
+	g.restoreState(state)
+
+	slog.Info("resumed from snapshot", "path", path, "saved_at", state.SavedAt, "seq", state.Seq,
+		logging.Text("♻️  Resumed from %s (saved %s, sequence %d)", path, state.SavedAt.Format(time.RFC3339), state.Seq))
+	return nil
+}
+
+// logSnapshot saves a snapshot, logging rather than failing the run on error
+func (g *Generator) logSnapshot() {
+	if err := g.saveSnapshot(g.cfg.Generate.SnapshotFile); err != nil {
+		logError("saving snapshot failed", "Error saving snapshot", err)
+	}
+}
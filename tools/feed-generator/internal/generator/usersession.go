@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+)
+
+// userSessions clusters normal trades into per-trader sessions. The trader
+// type of each trade is still drawn from the profile ratios, but once a trader
+// of that type is picked, the type's next trades come from the same trader
+// until their session runs out. Only the generation loop touches it, so it
+// needs no locking.
+type userSessions struct {
+	meanTrades float64
+	open       map[profiles.TraderType]*userSession
+}
+
+// userSession is one trader's current burst of activity
+type userSession struct {
+	profile   profiles.TraderProfile
+	remaining int // Trades left after the current one
+}
+
+// newUserSessions returns the session tracker, or nil when every normal trade
+// picks its trader afresh
+func newUserSessions(cfg *config.Config) *userSessions {
+	if cfg.Generate.UserSessionTrades <= 1 {
+		return nil
+	}
+	return &userSessions{
+		meanTrades: cfg.Generate.UserSessionTrades,
+		open:       make(map[profiles.TraderType]*userSession),
+	}
+}
+
+// next returns the trader of the next normal trade given the freshly selected
+// one: the trader of the open session for its type while that trader is
+// still a candidate, otherwise the selected trader, starting a new session
+func (s *userSessions) next(rng *rand.Rand, selected *profiles.TraderProfile, candidates []profiles.TraderProfile) *profiles.TraderProfile {
+	if open := s.open[selected.Type]; open != nil && open.remaining > 0 &&
+		slices.ContainsFunc(candidates, func(p profiles.TraderProfile) bool { return p.UserID == open.profile.UserID }) {
+		open.remaining--
+		profile := open.profile
+		return &profile
+	}
+
+	s.open[selected.Type] = &userSession{profile: *selected, remaining: s.length(rng, selected) - 1}
+	return selected
+}
+
+// length draws a session's trade count from a geometric distribution with the
+// configured mean, capped at the trader's trades per hour so a casual trader
+// never fits a heavy trader's burst into one session
+func (s *userSessions) length(rng *rand.Rand, profile *profiles.TraderProfile) int {
+	n := 1 + int(math.Log(1-rng.Float64())/math.Log(1-1/s.meanTrades))
+	return max(1, min(n, profile.TradesPerHour))
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"math"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+const (
+	// uShapeDepth sets how much busier the open and close are than midday (4x)
+	uShapeDepth = 3.0
+
+	// offSessionWeight is the u-shape's relative volume outside the session
+	offSessionWeight = 0.1
+)
+
+// volumeCurve scales the configured TPS by hour of day. Weights average 1 over
+// the day, so the configured TPS remains the daily average rate.
+type volumeCurve struct {
+	location *time.Location
+	weights  [24]float64
+}
+
+// newVolumeCurve builds the configured intraday volume curve, or returns nil
+// for a flat profile. The session was checked by config validation.
+func newVolumeCurve(cfg *config.Config) *volumeCurve {
+	location, open, close, err := cfg.Session.Parse()
+	if err != nil {
+		location = time.UTC
+	}
+
+	curve := &volumeCurve{location: location}
+	switch cfg.Generate.VolumeProfile {
+	case "u-shape":
+		curve.weights = uShapeWeights(open, close)
+	case "custom":
+		copy(curve.weights[:], cfg.Generate.VolumeWeights)
+	default:
+		return nil
+	}
+
+	total := 0.0
+	for _, weight := range curve.weights {
+		total += weight
+	}
+	for hour := range curve.weights {
+		curve.weights[hour] *= 24 / total
+	}
+
+	return curve
+}
+
+// uShapeWeights weights each session hour by its distance from midday, so
+// the open and close are busiest, with a low trickle outside the session
+func uShapeWeights(open, close time.Duration) [24]float64 {
+	length := close - open
+	if length < 0 {
+		length += 24 * time.Hour
+	}
+
+	var weights [24]float64
+	for hour := range weights {
+		// Position of the hour's midpoint within the session (0 = open, 1 = close)
+		since := time.Duration(hour)*time.Hour + 30*time.Minute - open
+		if since < 0 {
+			since += 24 * time.Hour
+		}
+		if since >= length {
+			weights[hour] = offSessionWeight
+			continue
+		}
+		x := float64(since) / float64(length)
+		weights[hour] = 1 + uShapeDepth*(2*x-1)*(2*x-1)
+	}
+	return weights
+}
+
+// tps returns the target rate at t for an average rate of base, clamped to
+// the accepted TPS range
+func (c *volumeCurve) tps(base int, t time.Time) int {
+	weight := c.weights[t.In(c.location).Hour()]
+	return max(1, min(maxTPS, int(math.Round(float64(base)*weight))))
+}
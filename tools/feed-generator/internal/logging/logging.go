@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// TextKey is the attribute carrying a record's console rendering. Text mode
+// prints it in place of the message; JSON mode drops it, since the message
+// and the other attributes already carry the same information.
+const TextKey = "text"
+
+// Text attaches the human-readable console line for a record
+func Text(format string, args ...any) slog.Attr {
+	return slog.String(TextKey, fmt.Sprintf(format, args...))
+}
+
+// New returns a logger writing to w in the given format (text or json),
+// dropping records below the given level (debug, info, warn or error)
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("log level must be debug, info, warn or error, got %q", level)
+	}
+
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(&consoleHandler{w: w, level: minLevel, mu: &sync.Mutex{}}), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: minLevel,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == TextKey && len(groups) == 0 {
+					return slog.Attr{}
+				}
+				return a
+			},
+		})), nil
+	default:
+		return nil, fmt.Errorf("log format must be text or json, got %q", format)
+	}
+}
+
+// consoleHandler prints each record as a bare line for people: its text
+// attribute when set, otherwise its message. Timestamps, levels and other
+// attributes are left to JSON mode.
+type consoleHandler struct {
+	w     io.Writer
+	level slog.Level
+	mu    *sync.Mutex // Shared by derived handlers so lines never interleave
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	line := r.Message
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == TextKey {
+			line = a.Value.String()
+			return false
+		}
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, line)
+	return err
+}
+
+func (h *consoleHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *consoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
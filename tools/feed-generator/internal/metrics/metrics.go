@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/generator"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// shutdownTimeout bounds how long in-flight scrapes may delay shutdown
+const shutdownTimeout = 5 * time.Second
+
+var (
+	tradesDesc = prometheus.NewDesc("tds_trades_total",
+		"Trades published", nil, nil)
+	fraudDesc = prometheus.NewDesc("tds_fraud_total",
+		"Fraud trades published", nil, nil)
+	volumeDesc = prometheus.NewDesc("tds_volume_cents",
+		"Notional volume published, in cents", nil, nil)
+	requestedTPSDesc = prometheus.NewDesc("tds_tps_requested",
+		"Target trades per second at the last stats report", nil, nil)
+	intervalTPSDesc = prometheus.NewDesc("tds_tps_interval",
+		"Trades per second achieved over the last stats interval", nil, nil)
+	tpsDeficitDesc = prometheus.NewDesc("tds_tps_deficit",
+		"Shortfall of the last interval's rate against the requested rate", nil, nil)
+	byProfileDesc = prometheus.NewDesc("tds_trades_by_profile",
+		"Trades published by trader profile type", []string{"profile"}, nil)
+	bySymbolDesc = prometheus.NewDesc("tds_trades_by_symbol",
+		"Trades published by symbol", []string{"symbol"}, nil)
+)
+
+// Collector exposes generator statistics to Prometheus, reading the live
+// counters at scrape time so nothing is counted twice
+type Collector struct {
+	stats *generator.Statistics
+}
+
+// NewCollector creates a collector over the given statistics
+func NewCollector(stats *generator.Statistics) *Collector {
+	return &Collector{stats: stats}
+}
+
+// Describe sends the metric descriptors
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tradesDesc
+	ch <- fraudDesc
+	ch <- volumeDesc
+	ch <- requestedTPSDesc
+	ch <- intervalTPSDesc
+	ch <- tpsDeficitDesc
+	ch <- byProfileDesc
+	ch <- bySymbolDesc
+}
+
+// Collect reads the current statistics
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(tradesDesc, prometheus.CounterValue,
+		float64(c.stats.TotalTrades.Load()))
+	ch <- prometheus.MustNewConstMetric(fraudDesc, prometheus.CounterValue,
+		float64(c.stats.FraudPatterns.Load()))
+	ch <- prometheus.MustNewConstMetric(volumeDesc, prometheus.CounterValue,
+		float64(c.stats.VolumeGenerated.Load()))
+	ch <- prometheus.MustNewConstMetric(requestedTPSDesc, prometheus.GaugeValue,
+		float64(c.stats.RequestedTPS()))
+	ch <- prometheus.MustNewConstMetric(intervalTPSDesc, prometheus.GaugeValue,
+		c.stats.IntervalTPS())
+	ch <- prometheus.MustNewConstMetric(tpsDeficitDesc, prometheus.GaugeValue,
+		c.stats.TPSDeficit())
+
+	for profile, count := range c.stats.ProfileCounts() {
+		ch <- prometheus.MustNewConstMetric(byProfileDesc, prometheus.CounterValue,
+			float64(count), profile)
+	}
+	for symbol, count := range c.stats.SymbolCounts() {
+		ch <- prometheus.MustNewConstMetric(bySymbolDesc, prometheus.CounterValue,
+			float64(count), symbol)
+	}
+}
+
+// Start serves /metrics on addr until ctx is cancelled. The returned channel
+// is closed once the server has shut down.
+func Start(ctx context.Context, addr string, stats *generator.Statistics) (<-chan struct{}, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(NewCollector(stats)); err != nil {
+		return nil, fmt.Errorf("failed to register metrics: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("serving metrics failed", "error", err,
+				logging.Text("Error serving metrics: %v", err))
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("shutting down metrics server failed", "error", err,
+				logging.Text("Error shutting down metrics server: %v", err))
+		}
+	}()
+
+	return done, nil
+}
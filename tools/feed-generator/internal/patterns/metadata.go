@@ -0,0 +1,79 @@
+package patterns
+
+import (
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+)
+
+// Asset classes of the built-in symbols
+const (
+	AssetEquity     = "EQUITY"
+	AssetETF        = "ETF"
+	AssetPennyStock = "PENNY_STOCK"
+)
+
+// SymbolMetadata returns a symbol's sector and asset class, falling back to
+// the configured defaults for symbols missing from the table
+func (pg *PatternGenerator) SymbolMetadata(symbol string) config.SymbolInfo {
+	metadata := pg.symbolInfo[symbol]
+	if metadata.Sector == "" {
+		metadata.Sector = pg.cfg.Generate.DefaultSector
+	}
+	if metadata.AssetClass == "" {
+		metadata.AssetClass = pg.cfg.Generate.DefaultAssetClass
+	}
+	return metadata
+}
+
+// setSymbolMetadata merges configured metadata over the built-in table. A
+// configured entry that leaves a field empty keeps the built-in value.
+func (pg *PatternGenerator) setSymbolMetadata(overrides map[string]config.SymbolInfo) {
+	for symbol, override := range overrides {
+		metadata := pg.symbolInfo[symbol]
+		if override.Sector != "" {
+			metadata.Sector = override.Sector
+		}
+		if override.AssetClass != "" {
+			metadata.AssetClass = override.AssetClass
+		}
+		pg.symbolInfo[symbol] = metadata
+	}
+}
+
+// getSymbolMetadata returns the sector and asset class of the built-in symbols
+func getSymbolMetadata() map[string]config.SymbolInfo {
+	equity := func(sector string) config.SymbolInfo {
+		return config.SymbolInfo{Sector: sector, AssetClass: AssetEquity}
+	}
+	etf := config.SymbolInfo{Sector: "BROAD_MARKET", AssetClass: AssetETF}
+	penny := config.SymbolInfo{Sector: "MICRO_CAP", AssetClass: AssetPennyStock}
+
+	return map[string]config.SymbolInfo{
+		// Blue chip stocks
+		"AAPL":  equity("TECHNOLOGY"),
+		"MSFT":  equity("TECHNOLOGY"),
+		"GOOGL": equity("COMMUNICATION_SERVICES"),
+		"AMZN":  equity("CONSUMER_DISCRETIONARY"),
+		"META":  equity("COMMUNICATION_SERVICES"),
+		"NVDA":  equity("TECHNOLOGY"),
+		"TSLA":  equity("CONSUMER_DISCRETIONARY"),
+
+		// Popular stocks
+		"AMD":  equity("TECHNOLOGY"),
+		"NFLX": equity("COMMUNICATION_SERVICES"),
+		"DIS":  equity("COMMUNICATION_SERVICES"),
+
+		// ETFs
+		"SPY": etf,
+		"QQQ": etf,
+		"VTI": etf,
+		"IWM": etf,
+		"DIA": etf,
+
+		// Penny stocks
+		"PENNY_A": penny,
+		"PENNY_B": penny,
+		"PENNY_C": penny,
+		"MICRO_X": penny,
+		"MICRO_Y": penny,
+	}
+}
@@ -1,129 +1,983 @@
 package patterns
 
 import (
+	"log/slog"
+	"maps"
+	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
 	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
 	"github.com/google/uuid"
 )
 
+// defaultPriceVolatility is the ±1% per-trade price jitter for symbols without
+// a price_jitter entry
+const defaultPriceVolatility = 0.01
+
+// DefaultSymbolPrice is the base price of a symbol missing from the price table
+const DefaultSymbolPrice = 100.0
+
+// syntheticPriceSeed seeds synthetic symbol base prices
+const syntheticPriceSeed = 1
+
+// Injector generates the trades for one occurrence of a fraud pattern
+type Injector func(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade
+
 // PatternGenerator handles fraud pattern injection
 type PatternGenerator struct {
+	cfg          *config.Config
+	rng          *rand.Rand // Trade content: symbols, sizes, prices, sides, IDs
+	timing       *rand.Rand // Timestamp offsets only, so timing can vary independently of content
 	symbolPrices map[string]float64
+	basePrices   map[string]float64       // Prices each symbol started from, the default mean of the mean_reverting model
+	priceClock   time.Duration            // Simulated time advanced by StepPrices
+	priceUpdated map[string]time.Duration // Price clock reading when each symbol's price last moved
+	injectors    map[profiles.FraudType]Injector
+	victims      []profiles.TraderProfile // Population front-running patterns trade ahead of
+	comboSymbol  string                   // Symbol every component of a combo pattern trades, while one is injected
+	symbolInfo   map[string]config.SymbolInfo
+	session      feed.Session // Regular session, outside of which trades are extended hours
 }
 
-// NewPatternGenerator creates a new pattern generator
-func NewPatternGenerator() *PatternGenerator {
-	return &PatternGenerator{
+// NewPatternGenerator creates a new pattern generator. Trade content is drawn
+// from rng and timestamp offsets within patterns from timing.
+func NewPatternGenerator(cfg *config.Config, rng, timing *rand.Rand) *PatternGenerator {
+	pg := &PatternGenerator{
+		cfg:          cfg,
+		rng:          rng,
+		timing:       timing,
 		symbolPrices: getSymbolPrices(),
+		symbolInfo:   getSymbolMetadata(),
+		priceUpdated: make(map[string]time.Duration),
+		injectors:    make(map[profiles.FraudType]Injector),
+	}
+
+	if n := cfg.Generate.SyntheticSymbols; n > 0 {
+		pg.addSyntheticPrices(profiles.SyntheticSymbols(n))
+	}
+	pg.SetPrices(cfg.Prices)
+	pg.basePrices = maps.Clone(pg.symbolPrices)
+	pg.setSymbolMetadata(cfg.SymbolMetadata)
+	pg.session = marketSession(cfg)
+
+	pg.Register(profiles.WashTrade, pg.InjectWashTrade)
+	pg.Register(profiles.VelocitySpike, pg.InjectVelocitySpike)
+	pg.Register(profiles.VelocityMulti, func(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+		return pg.InjectCrossSymbolVelocity(profile, pg.relatedSymbols(profile), baseTime)
+	})
+	pg.Register(profiles.Anomaly, func(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+		return []*feed.Trade{pg.InjectAnomaly(profile, baseTime)}
+	})
+	pg.Register(profiles.Imbalance, pg.InjectImbalance)
+	pg.Register(profiles.FragmentedWash, pg.InjectFragmentedWash)
+	pg.Register(profiles.SpoofPattern, pg.InjectSpoofPattern)
+	pg.Register(profiles.PumpDump, pg.InjectPumpDump)
+	pg.Register(profiles.Collusion, pg.InjectCollusiveWash)
+	pg.Register(profiles.QuoteStuffing, pg.InjectQuoteStuffing)
+	pg.Register(profiles.Momentum, pg.InjectMomentumIgnition)
+	pg.Register(profiles.FrontRunning, func(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+		victim := profiles.SelectVictimProfile(pg.rng, pg.victims, profile)
+		if victim == nil {
+			return nil
+		}
+		return pg.InjectFrontRunning(victim, profile, baseTime)
+	})
+	pg.Register(profiles.Combo, pg.InjectCombo)
+	pg.Register(profiles.TradingRing, pg.InjectTradingRing)
+	pg.Register(profiles.FatFinger, pg.InjectFatFinger)
+
+	return pg
+}
+
+// SetVictims sets the trader population front-running patterns pick their
+// victims from
+func (pg *PatternGenerator) SetVictims(traderProfiles []profiles.TraderProfile) {
+	pg.victims = traderProfiles
+}
+
+// Register adds (or replaces) the injector for a fraud type
+func (pg *PatternGenerator) Register(fraudType profiles.FraudType, injector Injector) {
+	pg.injectors[fraudType] = injector
+}
+
+// HasInjector reports whether an injector is registered for a fraud type
+func (pg *PatternGenerator) HasInjector(fraudType profiles.FraudType) bool {
+	_, exists := pg.injectors[fraudType]
+	return exists
+}
+
+// FraudTypes returns the registered fraud types in sorted order
+func (pg *PatternGenerator) FraudTypes() []profiles.FraudType {
+	fraudTypes := make([]profiles.FraudType, 0, len(pg.injectors))
+	for fraudType := range pg.injectors {
+		fraudTypes = append(fraudTypes, fraudType)
 	}
+	sort.Slice(fraudTypes, func(i, j int) bool { return fraudTypes[i] < fraudTypes[j] })
+	return fraudTypes
+}
+
+// Inject generates a fraud pattern for the given type.
+// It returns false if no injector is registered for the type.
+func (pg *PatternGenerator) Inject(fraudType profiles.FraudType, profile *profiles.TraderProfile, baseTime time.Time) ([]*feed.Trade, bool) {
+	injector, exists := pg.injectors[fraudType]
+	if !exists {
+		return nil, false
+	}
+	return injector(profile, baseTime), true
+}
+
+// InjectCombo overlays the configured combo patterns on one account: each
+// component runs from the same base time on a single symbol, so for example
+// wash pairs land inside a velocity spike. The merged trades are returned in
+// timestamp order.
+func (pg *PatternGenerator) InjectCombo(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	pg.comboSymbol = pg.fraudSymbol(profiles.Combo, profile)
+	defer func() { pg.comboSymbol = "" }()
+
+	var trades []*feed.Trade
+	for _, name := range pg.cfg.Generate.ComboPatterns {
+		fraudType := profiles.FraudType(name)
+		if fraudType == profiles.Combo {
+			continue
+		}
+		if pattern, ok := pg.Inject(fraudType, profile, baseTime); ok {
+			trades = append(trades, pattern...)
+		}
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Timestamp.Before(trades[j].Timestamp) })
+	return trades
 }
 
 // InjectWashTrade creates a wash trade pattern (buy followed by sell of same symbol)
-func (pg *PatternGenerator) InjectWashTrade(profile *profiles.TraderProfile, baseTime time.Time) []*models.Trade {
-	symbol := profile.GetRandomSymbol()
-	amount := pg.GenerateAmount(profile)
+func (pg *PatternGenerator) InjectWashTrade(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	symbol := pg.fraudSymbol(profiles.WashTrade, profile)
+	amount := pg.fraudAmount(profile)
 	price := pg.GetPrice(symbol)
 
-	trades := []*models.Trade{
-		{
-			ID:        uuid.New(),
+	trades := []*feed.Trade{
+		pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
 			UserID:    profile.UserID,
 			Symbol:    symbol,
 			Amount:    amount,
 			Price:     price,
 			Type:      models.TradeTypeBuy,
 			Timestamp: baseTime,
-		},
-		{
-			ID:        uuid.New(),
+		}),
+		pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
 			UserID:    profile.UserID,
 			Symbol:    symbol,
 			Amount:    amount,
-			Price:     price * (1 + (rand.Float64()-0.5)*0.001), // Tiny price difference
+			Price:     price * (1 + (pg.rng.Float64()-0.5)*0.001), // Tiny price difference
 			Type:      models.TradeTypeSell,
-			Timestamp: baseTime.Add(time.Duration(1+rand.Intn(4)) * time.Second), // 1-4 seconds later
-		},
+			Timestamp: baseTime.Add(time.Duration(1+pg.timing.Intn(4)) * time.Second), // 1-4 seconds later
+		}),
+	}
+
+	return trades
+}
+
+// InjectCollusiveWash spreads a wash trade across colluding accounts: 2-3
+// accounts from the profile's ring each buy from the next in a cycle, so every
+// leg pairs one account's buy with another's matching sell and positions net
+// to zero across the ring
+func (pg *PatternGenerator) InjectCollusiveWash(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	ring := append([]string{profile.UserID}, profile.LinkedUserIDs...)
+	pg.rng.Shuffle(len(ring), func(i, j int) { ring[i], ring[j] = ring[j], ring[i] })
+	accounts := ring[:min(len(ring), 2+pg.rng.Intn(2))] // 2-3 accounts
+
+	symbol := pg.fraudSymbol(profiles.Collusion, profile)
+	amount := pg.fraudAmount(profile)
+	trades := make([]*feed.Trade, 0, len(accounts)*2)
+
+	legTime := baseTime
+	for i, buyer := range accounts {
+		seller := accounts[(i+1)%len(accounts)]
+		price := pg.GetPrice(symbol)
+
+		trades = append(trades,
+			pg.NewTrade(&models.Trade{
+				ID:        pg.NewID(),
+				UserID:    buyer,
+				Symbol:    symbol,
+				Amount:    amount,
+				Price:     price,
+				Type:      models.TradeTypeBuy,
+				Timestamp: legTime,
+			}),
+			pg.NewTrade(&models.Trade{
+				ID:        pg.NewID(),
+				UserID:    seller,
+				Symbol:    symbol,
+				Amount:    amount,
+				Price:     price * (1 + (pg.rng.Float64()-0.5)*0.001), // Tiny price difference
+				Type:      models.TradeTypeSell,
+				Timestamp: legTime.Add(time.Duration(1+pg.timing.Intn(4)) * time.Second), // 1-4 seconds later
+			}),
+		)
+		legTime = legTime.Add(time.Duration(5+pg.timing.Intn(26)) * time.Second) // Next leg 5-30 seconds on
 	}
 
 	return trades
 }
 
+// InjectTradingRing cycles volume around a ring of 3-6 linked accounts in one
+// penny stock: over 2-4 rounds each account buys from the next, so the loop
+// closes with every account's position back where it started. Gross volume is
+// several times any single leg while nobody's net position moves.
+func (pg *PatternGenerator) InjectTradingRing(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	accounts := append([]string{profile.UserID}, profile.LinkedUserIDs...)
+	pg.rng.Shuffle(len(accounts), func(i, j int) { accounts[i], accounts[j] = accounts[j], accounts[i] })
+	size := pg.cfg.Generate.RingSize
+	if size == 0 {
+		size = 3 + pg.rng.Intn(4) // 3-6 accounts
+	}
+	ring := accounts[:min(len(accounts), size)]
+
+	symbol := pg.fraudSymbol(profiles.TradingRing, profile)
+	rounds := 2 + pg.rng.Intn(3) // 2-4 trips around the ring
+	trades := make([]*feed.Trade, 0, rounds*len(ring)*2)
+
+	legTime := baseTime
+	for round := 0; round < rounds; round++ {
+		// One size per round, so each account's buy and sell cancel out
+		amount := pg.fraudAmount(profile)
+		for i, buyer := range ring {
+			seller := ring[(i+1)%len(ring)]
+			price := pg.GetPrice(symbol)
+
+			trades = append(trades,
+				pg.NewTrade(&models.Trade{
+					ID:        pg.NewID(),
+					UserID:    buyer,
+					Symbol:    symbol,
+					Amount:    amount,
+					Price:     price,
+					Type:      models.TradeTypeBuy,
+					Timestamp: legTime,
+				}),
+				pg.NewTrade(&models.Trade{
+					ID:        pg.NewID(),
+					UserID:    seller,
+					Symbol:    symbol,
+					Amount:    amount,
+					Price:     price,
+					Type:      models.TradeTypeSell,
+					Timestamp: legTime.Add(time.Duration(pg.timing.Intn(500)) * time.Millisecond), // Matched within half a second
+				}),
+			)
+			legTime = legTime.Add(time.Duration(2+pg.timing.Intn(9)) * time.Second) // Next leg 2-10 seconds on
+		}
+	}
+
+	return trades
+}
+
+// InjectFragmentedWash splits a wash trade into many small matched buy/sell pairs
+// in one symbol. Each pair stays below typical size thresholds; only the aggregate
+// matched volume is suspicious.
+func (pg *PatternGenerator) InjectFragmentedWash(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numPairs := pg.cfg.Generate.FragmentedWashPairs
+	trades := make([]*feed.Trade, 0, numPairs*2)
+
+	symbol := pg.fraudSymbol(profiles.FragmentedWash, profile)
+	basePrice := pg.GetPrice(symbol)
+	window := 10 * time.Minute
+	spacing := window / time.Duration(numPairs)
+
+	for i := 0; i < numPairs; i++ {
+		// ±20% size jitter so the pairs don't share one conspicuous size
+		shares := pg.cfg.Generate.FragmentedWashSize * pg.cfg.Generate.FraudSizeMultiplier * (0.8 + pg.rng.Float64()*0.4)
+		price := basePrice * (1 + (pg.rng.Float64()-0.5)*0.002)
+		amount := pg.AmountMode().Amount(shares, price)
+		buyTime := baseTime.Add(time.Duration(i) * spacing)
+
+		trades = append(trades,
+			pg.NewTrade(&models.Trade{
+				ID:        pg.NewID(),
+				UserID:    profile.UserID,
+				Symbol:    symbol,
+				Amount:    amount,
+				Price:     price,
+				Type:      models.TradeTypeBuy,
+				Timestamp: buyTime,
+			}),
+			pg.NewTrade(&models.Trade{
+				ID:        pg.NewID(),
+				UserID:    profile.UserID,
+				Symbol:    symbol,
+				Amount:    amount,
+				Price:     price * (1 + (pg.rng.Float64()-0.5)*0.001), // Tiny price difference
+				Type:      models.TradeTypeSell,
+				Timestamp: buyTime.Add(time.Duration(1+pg.timing.Intn(4)) * time.Second), // 1-4 seconds later
+			}),
+		)
+	}
+
+	return trades
+}
+
+// InjectSpoofPattern creates a spoofing/layering pattern: a burst of large
+// same-side orders at escalating prices, all cancelled within a few hundred
+// milliseconds, followed by a small opposite-side trade at the moved price.
+// The layered orders are published with Cancelled set, since the feed carries
+// no separate order book.
+func (pg *PatternGenerator) InjectSpoofPattern(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numLayers := 5 + pg.rng.Intn(4) // 5-8 layered orders
+	trades := make([]*feed.Trade, 0, numLayers+1)
+
+	symbol := pg.fraudSymbol(profiles.SpoofPattern, profile)
+	price := pg.GetPrice(symbol)
+
+	// Buy-side layers push the price up so the real trade sells into it, and vice versa
+	spoofSide := pg.RandomTradeType(profile)
+	realSide := models.TradeTypeSell
+	step := 1 + 0.0005 // Each layer improves on the last by 5 bps
+	if spoofSide == models.TradeTypeSell {
+		realSide = models.TradeTypeBuy
+		step = 1 / step
+	}
+
+	timestamp := baseTime
+	for i := 0; i < numLayers; i++ {
+		order := pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    pg.fraudAmount(profile) * 10, // Large enough to move the book
+			Price:     price,
+			Type:      spoofSide,
+			Timestamp: timestamp,
+		})
+		order.Liquidity = feed.Passive
+		order.Cancelled = true
+		trades = append(trades, order)
+
+		price *= step
+		timestamp = timestamp.Add(time.Duration(20+pg.timing.Intn(41)) * time.Millisecond) // 20-60ms apart
+	}
+
+	// The trade that benefits from the moved price, shortly after the layers
+	fill := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    pg.fraudAmount(profile),
+		Price:     price,
+		Type:      realSide,
+		Timestamp: timestamp.Add(time.Duration(50+pg.timing.Intn(151)) * time.Millisecond), // 50-200ms later
+	})
+	fill.Liquidity = feed.Aggressive
+
+	return append(trades, fill)
+}
+
+// InjectQuoteStuffing floods one symbol with a burst of small orders at
+// millisecond spacing, all inside one second, nearly all of them cancelled.
+// Cancelled orders are published with Cancelled set, as with spoofing.
+func (pg *PatternGenerator) InjectQuoteStuffing(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numOrders := pg.cfg.Generate.QuoteStuffSize
+	trades := make([]*feed.Trade, numOrders)
+
+	symbol := pg.fraudSymbol(profiles.QuoteStuffing, profile)
+	price := pg.GetPrice(symbol)
+	spacing := time.Second / time.Duration(numOrders)
+
+	for i := range trades {
+		order := pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    pg.fraudAmount(profile),
+			Price:     price * (1 + (pg.rng.Float64()-0.5)*0.001), // Quotes flicker within 5 bps
+			Type:      pg.RandomTradeType(profile),
+			Timestamp: baseTime.Add(time.Duration(i) * spacing),
+		})
+		if pg.rng.Float64() < pg.cfg.Generate.QuoteStuffCancelRatio {
+			order.Liquidity = feed.Passive
+			order.Cancelled = true
+		}
+		trades[i] = order
+	}
+
+	return trades
+}
+
+// InjectMomentumIgnition fires a short cluster of escalating aggressive orders
+// in one direction to set off momentum algorithms, pauses while they chase the
+// move, then unwinds the whole position in one large aggressive opposite-side
+// trade. The cluster moves the symbol's price, which gives back half the move
+// once the manipulator is out.
+func (pg *PatternGenerator) InjectMomentumIgnition(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numIgnition := 4 + pg.rng.Intn(4) // 4-7 orders
+	trades := make([]*feed.Trade, 0, numIgnition+1)
+
+	symbol := pg.fraudSymbol(profiles.Momentum, profile)
+	startPrice := pg.currentPrice(symbol)
+
+	side := pg.RandomTradeType(profile)
+	unwindSide := models.TradeTypeSell
+	direction := 1.0
+	if side == models.TradeTypeSell {
+		unwindSide = models.TradeTypeBuy
+		direction = -1
+	}
+
+	// Ignition: orders 100-500ms apart, each larger than the last and 10-25 bps further on
+	price := startPrice
+	amount := pg.fraudAmount(profile)
+	position := 0.0
+	timestamp := baseTime
+	for i := 0; i < numIgnition; i++ {
+		price *= 1 + direction*(0.001+pg.rng.Float64()*0.0015)
+		pg.symbolPrices[symbol] = price
+
+		order := pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    amount,
+			Price:     price,
+			Type:      side,
+			Timestamp: timestamp,
+		})
+		order.Liquidity = feed.Aggressive
+		trades = append(trades, order)
+
+		position += amount
+		amount *= 1.3 + pg.rng.Float64()*0.4 // 30-70% larger each time
+		timestamp = timestamp.Add(time.Duration(100+pg.timing.Intn(401)) * time.Millisecond)
+	}
+
+	// Unwind: the whole position at the moved price, 2-5 seconds later
+	unwind := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    position,
+		Price:     price,
+		Type:      unwindSide,
+		Timestamp: timestamp.Add(time.Duration(2+pg.timing.Intn(4)) * time.Second),
+	})
+	unwind.Liquidity = feed.Aggressive // Hits the chasing algorithms' quotes
+	pg.symbolPrices[symbol] = startPrice + (price-startPrice)/2
+
+	return append(trades, unwind)
+}
+
+// InjectFrontRunning trades ahead of a large order from a normal trader: the
+// fraud account enters on the same side just before the victim's order, then
+// exits into the price the victim's order moved. It returns the entry, the
+// victim's trade and the exit, in time order.
+func (pg *PatternGenerator) InjectFrontRunning(victim, fraud *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	symbol := pg.fraudSymbol(profiles.FrontRunning, fraud)
+	price := pg.GetPrice(symbol)
+
+	side := pg.RandomTradeType(victim)
+	exitSide := models.TradeTypeSell
+	direction := 1.0
+	if side == models.TradeTypeSell {
+		exitSide = models.TradeTypeBuy
+		direction = -1
+	}
+
+	amount := pg.fraudAmount(fraud)
+	entry := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    fraud.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     price,
+		Type:      side,
+		Timestamp: baseTime,
+	})
+	entry.Liquidity = feed.Aggressive
+
+	// The victim's order is 5-10x their usual size and moves the price 20 bps
+	victimTime := baseTime.Add(time.Duration(50+pg.timing.Intn(451)) * time.Millisecond) // 50-500ms later
+	order := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    victim.UserID,
+		Symbol:    symbol,
+		Amount:    pg.GenerateAmount(victim) * (5 + pg.rng.Float64()*5),
+		Price:     price * (1 + direction*0.001),
+		Type:      side,
+		Timestamp: victimTime,
+	})
+	order.Liquidity = feed.Aggressive
+
+	exit := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    fraud.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     price * (1 + direction*0.002),
+		Type:      exitSide,
+		Timestamp: victimTime.Add(time.Duration(1+pg.timing.Intn(5)) * time.Second), // 1-5 seconds after the victim
+	})
+	exit.Liquidity = feed.Passive
+
+	return []*feed.Trade{entry, order, exit}
+}
+
+// InjectInsiderAccumulation creates the quiet position build-up of a trader
+// who knows about news moving symbol at eventTime: a handful of modest buys
+// at market prices, spread over the insider lead before the event. The price
+// jump itself is left to the news event (see Jump).
+func (pg *PatternGenerator) InjectInsiderAccumulation(profile *profiles.TraderProfile, symbol string, eventTime time.Time) []*feed.Trade {
+	numBuys := 5 + pg.rng.Intn(4) // 5-8 buys
+	trades := make([]*feed.Trade, numBuys)
+
+	lead := pg.cfg.Generate.InsiderLead
+	spacing := lead / time.Duration(numBuys)
+	for i := range trades {
+		// Jitter within each slot keeps the buys from looking scheduled
+		offset := time.Duration(i) * spacing
+		if spacing > 0 {
+			offset += time.Duration(pg.timing.Int63n(int64(spacing)))
+		}
+		trades[i] = pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    pg.fraudAmount(profile),
+			Price:     pg.GetPrice(symbol),
+			Type:      models.TradeTypeBuy,
+			Timestamp: eventTime.Add(offset - lead),
+		})
+	}
+
+	return trades
+}
+
+// InjectPumpDump creates a three-phase pump-and-dump in a penny stock over the
+// configured window: steady accumulation buys, accelerating pump buys, and a
+// cluster of large sells as the price collapses. Prices rise monotonically
+// through accumulation and pump and fall monotonically through the dump, and
+// the symbol's price tracks each phase before settling back where it started.
+func (pg *PatternGenerator) InjectPumpDump(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	symbol := pg.pumpDumpSymbol()
+	startPrice := pg.currentPrice(symbol)
+
+	window := pg.cfg.Generate.PumpDumpWindow
+	accumulationEnd := window * 4 / 10
+	pumpEnd := window * 8 / 10
+
+	numAccumulation := 8 + pg.rng.Intn(5) // 8-12 trades
+	numPump := 10 + pg.rng.Intn(6)        // 10-15 trades
+	numDump := 5 + pg.rng.Intn(4)         // 5-8 trades
+	trades := make([]*feed.Trade, 0, numAccumulation+numPump+numDump)
+
+	price := startPrice
+	trade := func(amount float64, tradeType models.TradeType, timestamp time.Time) {
+		pg.symbolPrices[symbol] = price
+		trades = append(trades, pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    amount,
+			Price:     price,
+			Type:      tradeType,
+			Timestamp: timestamp,
+		}))
+	}
+
+	// Accumulation: evenly spaced buys, each nudging the price up 0.5-1%
+	spacing := accumulationEnd / time.Duration(numAccumulation)
+	for i := 0; i < numAccumulation; i++ {
+		price *= 1.005 + pg.rng.Float64()*0.005
+		trade(pg.fraudAmount(profile), models.TradeTypeBuy, baseTime.Add(time.Duration(i)*spacing))
+	}
+
+	// Pump: buys bunching up toward the peak with ever larger price steps
+	pumpSpan := pumpEnd - accumulationEnd
+	for i := 0; i < numPump; i++ {
+		progress := float64(i) / float64(numPump)
+		offset := time.Duration(float64(pumpSpan) * (1 - (1-progress)*(1-progress)))
+		price *= 1.02 + 0.01*float64(i)
+		trade(pg.fraudAmount(profile)*2, models.TradeTypeBuy, baseTime.Add(accumulationEnd+offset))
+	}
+
+	// Dump: large sells in quick succession, falling back to the starting price
+	dumpSpan := window - pumpEnd
+	spacing = dumpSpan / time.Duration(numDump)
+	drop := math.Pow(startPrice/price, 1/float64(numDump))
+	for i := 0; i < numDump; i++ {
+		price *= drop
+		if i == numDump-1 {
+			price = startPrice // Avoid drifting the symbol's price across repeated patterns
+		}
+		trade(pg.fraudAmount(profile)*5, models.TradeTypeSell, baseTime.Add(pumpEnd+time.Duration(i)*spacing))
+	}
+
+	return trades
+}
+
+// pumpDumpSymbol picks the penny stock for a pump-and-dump, honouring a
+// combo's symbol, a configured fraud symbol universe or traded universe
+func (pg *PatternGenerator) pumpDumpSymbol() string {
+	if pg.comboSymbol != "" {
+		return pg.comboSymbol
+	}
+	if symbols := pg.cfg.FraudSymbols[string(profiles.PumpDump)]; len(symbols) > 0 {
+		return symbols[pg.rng.Intn(len(symbols))]
+	}
+	if symbols := pg.cfg.Generate.Symbols; len(symbols) > 0 {
+		return symbols[pg.rng.Intn(len(symbols))]
+	}
+	return profiles.PennyStocks[pg.rng.Intn(len(profiles.PennyStocks))]
+}
+
 // InjectVelocitySpike creates a sudden burst of trades
-func (pg *PatternGenerator) InjectVelocitySpike(profile *profiles.TraderProfile, baseTime time.Time) []*models.Trade {
-	numTrades := 10 + rand.Intn(11) // 10-20 trades
-	trades := make([]*models.Trade, numTrades)
+func (pg *PatternGenerator) InjectVelocitySpike(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numTrades := 10 + pg.rng.Intn(11) // 10-20 trades
+	trades := make([]*feed.Trade, numTrades)
 
-	symbol := profile.GetRandomSymbol()
+	symbol := pg.fraudSymbol(profiles.VelocitySpike, profile)
 	basePrice := pg.GetPrice(symbol)
 
 	for i := 0; i < numTrades; i++ {
-		amount := pg.GenerateAmount(profile)
+		amount := pg.fraudAmount(profile)
 		// Add small variation to price
-		price := basePrice * (1 + (rand.Float64()-0.5)*0.02)
+		price := basePrice * (1 + (pg.rng.Float64()-0.5)*0.02)
 
-		trades[i] = &models.Trade{
-			ID:        uuid.New(),
+		trades[i] = pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
 			UserID:    profile.UserID,
 			Symbol:    symbol,
 			Amount:    amount,
 			Price:     price,
-			Type:      pg.RandomTradeType(),
+			Type:      pg.RandomTradeType(profile),
 			Timestamp: baseTime.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	return trades
+}
+
+// InjectCrossSymbolVelocity creates coordinated bursts across related symbols,
+// such as an index ETF and its top components, all pushed the same way within
+// one short window. The interleaved trades are returned in timestamp order.
+func (pg *PatternGenerator) InjectCrossSymbolVelocity(profile *profiles.TraderProfile, symbols []string, baseTime time.Time) []*feed.Trade {
+	const window = 5 * time.Second
+
+	side := pg.RandomTradeType(profile)
+	var trades []*feed.Trade
+
+	for _, symbol := range symbols {
+		basePrice := pg.GetPrice(symbol)
+		numTrades := 5 + pg.rng.Intn(6) // 5-10 trades per symbol
+
+		for i := 0; i < numTrades; i++ {
+			trades = append(trades, pg.NewTrade(&models.Trade{
+				ID:        pg.NewID(),
+				UserID:    profile.UserID,
+				Symbol:    symbol,
+				Amount:    pg.fraudAmount(profile),
+				Price:     basePrice * (1 + (pg.rng.Float64()-0.5)*0.02),
+				Type:      side,
+				Timestamp: baseTime.Add(time.Duration(pg.timing.Int63n(int64(window)/int64(time.Millisecond))) * time.Millisecond),
+			}))
 		}
 	}
 
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Timestamp.Before(trades[j].Timestamp) })
 	return trades
 }
 
-// InjectAnomaly creates an anomalous trade that deviates from normal pattern
-func (pg *PatternGenerator) InjectAnomaly(profile *profiles.TraderProfile, baseTime time.Time) *models.Trade {
-	anomalyType := rand.Intn(4)
+// relatedSymbols picks 2-4 symbols for a cross-symbol velocity spike from the
+// configured fraud symbols, the profile's related symbols, or its typical
+// symbols, in that order of preference
+func (pg *PatternGenerator) relatedSymbols(profile *profiles.TraderProfile) []string {
+	if pg.comboSymbol != "" {
+		return []string{pg.comboSymbol}
+	}
+	candidates := pg.cfg.FraudSymbols[string(profiles.VelocityMulti)]
+	if len(candidates) == 0 {
+		candidates = profile.RelatedSymbols
+	}
+	if len(candidates) == 0 {
+		candidates = profile.TypicalSymbols
+	}
 
+	symbols := append([]string(nil), candidates...)
+	pg.rng.Shuffle(len(symbols), func(i, j int) { symbols[i], symbols[j] = symbols[j], symbols[i] })
+	return symbols[:min(len(symbols), 2+pg.rng.Intn(3))]
+}
+
+// InjectAnomaly creates an anomalous trade that deviates from normal pattern
+func (pg *PatternGenerator) InjectAnomaly(profile *profiles.TraderProfile, baseTime time.Time) *feed.Trade {
 	trade := &models.Trade{
-		ID:        uuid.New(),
+		ID:        pg.NewID(),
 		UserID:    profile.UserID,
-		Symbol:    profile.GetRandomSymbol(),
-		Amount:    pg.GenerateAmount(profile),
+		Symbol:    pg.fraudSymbol(profiles.Anomaly, profile),
+		Amount:    pg.fraudAmount(profile),
 		Price:     0,
-		Type:      pg.RandomTradeType(),
+		Type:      pg.RandomTradeType(profile),
 		Timestamp: baseTime,
 	}
 
-	switch anomalyType {
-	case 0:
-		// Massive size (10x normal)
-		trade.Amount = profile.AvgTradeSize * 10
-		trade.Price = pg.GetPrice(trade.Symbol)
-	case 1:
-		// Unusual time (middle of night)
-		nightHour := 2 + rand.Intn(4) // 2-5 AM
-		trade.Timestamp = time.Date(
-			baseTime.Year(), baseTime.Month(), baseTime.Day(),
-			nightHour, rand.Intn(60), rand.Intn(60), 0, baseTime.Location(),
-		)
-		trade.Price = pg.GetPrice(trade.Symbol)
-	case 2:
-		// Penny stock (unusual symbol for this trader)
-		trade.Symbol = profiles.PennyStocks[rand.Intn(len(profiles.PennyStocks))]
-		trade.Price = rand.Float64()*5 + 0.5 // $0.50-$5.50
-	case 3:
-		// Unusual price (way above/below market)
-		trade.Price = pg.GetPrice(trade.Symbol) * (1 + (rand.Float64()-0.5)*0.5) // ±25% deviation
+	switch pg.anomalyType() {
+	case "size":
+		pg.sizeAnomaly(trade, profile)
+	case "off_hours":
+		pg.offHoursAnomaly(trade, baseTime)
+	case "penny_stock":
+		// Repricing a real symbol as a penny stock would break its price
+		// realism, so a restricted universe gets a price anomaly instead
+		if len(pg.cfg.Generate.Symbols) > 0 {
+			pg.priceAnomaly(trade)
+		} else {
+			pg.pennyStockAnomaly(trade)
+		}
+	case "price":
+		pg.priceAnomaly(trade)
+	}
+
+	// Conditions depend on the final size and timestamp (off-hours trades are extended-hours prints)
+	return pg.NewTrade(trade)
+}
+
+// anomalyType returns the configured anomaly type, or draws one by weight
+func (pg *PatternGenerator) anomalyType() string {
+	if anomalyType := pg.cfg.Generate.AnomalyType; anomalyType != "" {
+		return anomalyType
+	}
+
+	total := 0.0
+	for _, anomalyType := range config.AnomalyTypes {
+		total += pg.cfg.AnomalyWeights[anomalyType]
+	}
+
+	r := pg.rng.Float64() * total
+	var last string
+	for _, anomalyType := range config.AnomalyTypes {
+		weight := pg.cfg.AnomalyWeights[anomalyType]
+		if weight == 0 {
+			continue
+		}
+		if r < weight {
+			return anomalyType
+		}
+		r -= weight
+		last = anomalyType
+	}
+	return last // Rounding left r at the total
+}
+
+// sizeAnomaly makes the trade 10x the trader's normal size
+func (pg *PatternGenerator) sizeAnomaly(trade *models.Trade, profile *profiles.TraderProfile) {
+	trade.Amount = profile.AvgTradeSize * 10 * pg.cfg.Generate.FraudSizeMultiplier
+	trade.Price = pg.GetPrice(trade.Symbol)
+}
+
+// offHoursAnomaly moves the trade into the middle of the night (2-5 AM) in
+// the session timezone, the zone extended hours are decided in, so the trade
+// prints outside the session wherever the host runs
+func (pg *PatternGenerator) offHoursAnomaly(trade *models.Trade, baseTime time.Time) {
+	local := baseTime
+	if pg.session.Location != nil {
+		local = baseTime.In(pg.session.Location)
+	}
+	nightHour := 2 + pg.timing.Intn(4)
+	trade.Timestamp = time.Date(
+		local.Year(), local.Month(), local.Day(),
+		nightHour, pg.timing.Intn(60), pg.timing.Intn(60), 0, local.Location(),
+	)
+	trade.Price = pg.GetPrice(trade.Symbol)
+}
+
+// pennyStockAnomaly switches the trade to a penny stock, unusual for the
+// trader. Inside a combo it prices the combo's symbol as a penny stock instead.
+func (pg *PatternGenerator) pennyStockAnomaly(trade *models.Trade) {
+	trade.Symbol = profiles.PennyStocks[pg.rng.Intn(len(profiles.PennyStocks))]
+	if pg.comboSymbol != "" {
+		trade.Symbol = pg.comboSymbol
+	}
+	trade.Price = pg.rng.Float64()*5 + 0.5 // $0.50-$5.50
+}
+
+// priceAnomaly prices the trade N volatilities above or below market, so
+// severity is comparable across symbols
+func (pg *PatternGenerator) priceAnomaly(trade *models.Trade) {
+	deviation := 1 + pg.cfg.Generate.AnomalyPriceSigmas*pg.symbolVolatility(trade.Symbol)
+	trade.Price = pg.GetPrice(trade.Symbol) * deviation
+	if pg.rng.Float64() < 0.5 {
+		trade.Price = pg.GetPrice(trade.Symbol) / deviation
+	}
+}
+
+// InjectFatFinger creates an isolated keying error: one trade with a
+// misplaced decimal, either 10x or 100x the intended size or priced 10x above
+// or below market. With probability fat_finger_correction it is followed 5-30
+// seconds later by an opposite-side trade of the same size at market price,
+// unwinding the error.
+func (pg *PatternGenerator) InjectFatFinger(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	symbol := pg.fraudSymbol(profiles.FatFinger, profile)
+	amount := pg.fraudAmount(profile)
+	price := pg.GetPrice(symbol)
+
+	if pg.rng.Intn(2) == 0 {
+		amount *= []float64{10, 100}[pg.rng.Intn(2)]
+	} else if pg.rng.Intn(2) == 0 {
+		price *= 10
+	} else {
+		price /= 10
+	}
+
+	errorTrade := &models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     price,
+		Type:      pg.RandomTradeType(profile),
+		Timestamp: baseTime,
+	}
+	trades := []*feed.Trade{pg.NewTrade(errorTrade)}
+	if pg.rng.Float64() >= pg.cfg.Generate.FatFingerCorrection {
+		return trades
+	}
+
+	reverse := models.TradeTypeSell
+	if errorTrade.Type == models.TradeTypeSell {
+		reverse = models.TradeTypeBuy
+	}
+	return append(trades, pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     pg.GetPrice(symbol),
+		Type:      reverse,
+		Timestamp: baseTime.Add(time.Duration(5+pg.timing.Intn(26)) * time.Second), // 5-30 seconds later
+	}))
+}
+
+// InjectImbalance creates a run of trades skewed heavily to one side.
+// Sizes and pacing stay normal so the directional imbalance is the only signature.
+func (pg *PatternGenerator) InjectImbalance(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	numTrades := 20 + pg.rng.Intn(21) // 20-40 trades
+	trades := make([]*feed.Trade, numTrades)
+
+	symbol := pg.fraudSymbol(profiles.Imbalance, profile)
+	window := 10 * time.Minute
+	spacing := window / time.Duration(numTrades)
+
+	for i := 0; i < numTrades; i++ {
+		tradeType := models.TradeTypeSell
+		if pg.rng.Float64() < pg.cfg.Generate.ImbalanceRatio {
+			tradeType = models.TradeTypeBuy
+		}
+
+		trades[i] = pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    symbol,
+			Amount:    pg.fraudAmount(profile),
+			Price:     pg.GetPrice(symbol),
+			Type:      tradeType,
+			Timestamp: baseTime.Add(time.Duration(i) * spacing),
+		})
 	}
 
-	return trade
+	return trades
 }
 
-// GenerateAmount generates a trade amount using normal distribution
+// MarketMakerQuotes fills both sides of a market maker's two-sided quote: a
+// passive buy at the bid and a passive sell of the same size at the ask, half
+// the profile's spread either side of the symbol's mid, a few milliseconds
+// apart in random order. Legitimate two-sided flow like this must not be
+// mistaken for wash trading.
+func (pg *PatternGenerator) MarketMakerQuotes(profile *profiles.TraderProfile, baseTime time.Time) []*feed.Trade {
+	symbol := profile.GetRandomSymbol(pg.rng)
+	mid := pg.currentPrice(symbol)
+	halfSpread := mid * profile.GetQuoteSpread() / 2
+	amount := pg.RoundToLot(pg.GenerateAmount(profile), mid)
+
+	bid := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     mid - halfSpread,
+		Type:      models.TradeTypeBuy,
+		Timestamp: baseTime,
+	})
+	ask := pg.NewTrade(&models.Trade{
+		ID:        pg.NewID(),
+		UserID:    profile.UserID,
+		Symbol:    symbol,
+		Amount:    amount,
+		Price:     mid + halfSpread,
+		Type:      models.TradeTypeSell,
+		Timestamp: baseTime,
+	})
+	bid.Liquidity = feed.Passive
+	ask.Liquidity = feed.Passive
+
+	first, second := bid, ask
+	if pg.rng.Intn(2) == 0 {
+		first, second = ask, bid
+	}
+	second.Timestamp = baseTime.Add(time.Duration(1+pg.timing.Intn(50)) * time.Millisecond) // 1-50ms later
+
+	return []*feed.Trade{first, second}
+}
+
+// CancelledOrders returns the orders a trader placed and cancelled around one
+// execution, drawn so that orders per execution average the profile's
+// order-to-trade ratio. Each rests passively a few bps behind the execution's
+// price, on a random side, in the 500ms before it.
+func (pg *PatternGenerator) CancelledOrders(profile *profiles.TraderProfile, execution *feed.Trade) []*feed.Trade {
+	expected := profile.GetOrderToTrade() - 1
+	n := int(expected)
+	if pg.rng.Float64() < expected-float64(n) {
+		n++
+	}
+
+	orders := make([]*feed.Trade, n)
+	for i := range orders {
+		side := pg.RandomTradeType(profile)
+		offset := 1 - float64(1+pg.rng.Intn(10))*0.0001 // 1-10 bps behind, below a bid
+		if side == models.TradeTypeSell {
+			offset = 2 - offset
+		}
+		order := pg.NewTrade(&models.Trade{
+			ID:        pg.NewID(),
+			UserID:    profile.UserID,
+			Symbol:    execution.Symbol,
+			Amount:    pg.RoundToLot(pg.GenerateAmount(profile), execution.Price),
+			Price:     execution.Price * offset,
+			Type:      side,
+			Timestamp: execution.Timestamp.Add(-time.Duration(pg.timing.Intn(500)) * time.Millisecond),
+		})
+		order.Liquidity = feed.Passive
+		order.Cancelled = true
+		orders[i] = order
+	}
+	return orders
+}
+
+// GenerateAmount generates a trade amount from the profile's size distribution
 func (pg *PatternGenerator) GenerateAmount(profile *profiles.TraderProfile) float64 {
 	mean := profile.AvgTradeSize
+	if profile.SizeDistribution == profiles.LognormalSizes {
+		return pg.lognormalAmount(mean, profile.Volatility)
+	}
+
 	stdDev := mean * profile.Volatility
 
 	// Use normal distribution
-	z := rand.NormFloat64()
+	z := pg.rng.NormFloat64()
 
 	amount := mean + z*stdDev
 
@@ -141,26 +995,293 @@ func (pg *PatternGenerator) GenerateAmount(profile *profiles.TraderProfile) floa
 	return amount
 }
 
-// GetPrice gets the price for a symbol with small random variation
+// lognormalAmount draws a right-skewed amount with the given mean and
+// coefficient of variation. The draw is clamped to 3 sigma in log space, so
+// sizes stay positive and the long tail is kept without unbounded outliers.
+func (pg *PatternGenerator) lognormalAmount(mean, volatility float64) float64 {
+	sigma := math.Sqrt(math.Log(1 + volatility*volatility))
+	mu := math.Log(mean) - sigma*sigma/2
+
+	z := math.Max(-3, math.Min(3, pg.rng.NormFloat64()))
+	return math.Exp(mu + z*sigma)
+}
+
+// RoundToLot rounds a normal trade amount at the given price to whole round
+// lots of shares, leaving an odd-lot size with the configured probability.
+// Rounding is off when no round lot size is configured.
+func (pg *PatternGenerator) RoundToLot(amount, price float64) float64 {
+	lot := pg.cfg.Generate.RoundLotSize
+	if lot <= 0 {
+		return amount
+	}
+
+	mode := pg.AmountMode()
+	if lot > 1 && pg.rng.Float64() < pg.cfg.Generate.OddLotProbability {
+		return mode.Amount(float64(1+pg.rng.Intn(lot-1)), price) // 1 to lot-1 shares
+	}
+
+	lots := math.Max(1, math.Round(mode.Shares(amount, price)/float64(lot)))
+	return mode.Amount(lots*float64(lot), price)
+}
+
+// AmountMode returns the configured unit of trade amounts
+func (pg *PatternGenerator) AmountMode() feed.AmountMode {
+	return feed.AmountMode(pg.cfg.Generate.AmountMode)
+}
+
+// NewTrade wraps a generated trade and classifies its conditions against the
+// round lot size and trading session
+func (pg *PatternGenerator) NewTrade(trade *models.Trade) *feed.Trade {
+	roundLotSize := float64(feed.DefaultRoundLotSize)
+	if pg.cfg.Generate.RoundLotSize > 0 {
+		roundLotSize = float64(pg.cfg.Generate.RoundLotSize)
+	}
+	return feed.NewTrade(trade, roundLotSize, pg.AmountMode(), pg.session)
+}
+
+// marketSession returns the configured session that extended-hours trades
+// fall outside of, read in the session timezone, never the process-local zone
+func marketSession(cfg *config.Config) feed.Session {
+	location, open, close, err := cfg.Session.Parse()
+	if err != nil {
+		return feed.DefaultSession(cfg.Generate.Location)
+	}
+	return feed.Session{Location: location, Open: open, Close: close}
+}
+
+// NewID returns a trade ID. Seeded runs draw IDs from the content source so
+// they repeat along with the trades; unseeded runs use random UUIDs.
+func (pg *PatternGenerator) NewID() uuid.UUID {
+	if pg.cfg.Generate.Seed == 0 {
+		return uuid.New()
+	}
+	id, err := uuid.NewRandomFromReader(pg.rng)
+	if err != nil {
+		return uuid.New()
+	}
+	return id
+}
+
+// fraudSymbol picks the symbol for a fraud pattern, drawing from the fraud
+// type's configured symbol universe when one is set. Components of a combo
+// pattern all trade the combo's symbol.
+func (pg *PatternGenerator) fraudSymbol(fraudType profiles.FraudType, profile *profiles.TraderProfile) string {
+	if pg.comboSymbol != "" {
+		return pg.comboSymbol
+	}
+	if symbols := pg.cfg.FraudSymbols[string(fraudType)]; len(symbols) > 0 {
+		return symbols[pg.rng.Intn(len(symbols))]
+	}
+	return profile.GetRandomSymbol(pg.rng)
+}
+
+// fraudAmount generates a fraud pattern amount scaled by the fraud size multiplier
+func (pg *PatternGenerator) fraudAmount(profile *profiles.TraderProfile) float64 {
+	return pg.GenerateAmount(profile) * pg.cfg.Generate.FraudSizeMultiplier
+}
+
+// GetPrice gets the current price for a symbol with small random variation
 func (pg *PatternGenerator) GetPrice(symbol string) float64 {
-	basePrice, exists := pg.symbolPrices[symbol]
+	basePrice := pg.currentPrice(symbol)
+
+	// Add ±volatility variation
+	variation := (pg.rng.Float64() - 0.5) * 2 * pg.symbolVolatility(symbol)
+	return basePrice * (1 + variation)
+}
+
+// GetTradePrice gets a price on the given side of the symbol's quote: buys
+// lift the ask half a spread above the jittered mid, sells hit the bid half a
+// spread below it
+func (pg *PatternGenerator) GetTradePrice(symbol string, side models.TradeType) float64 {
+	price := pg.GetPrice(symbol)
+	halfSpread := pg.symbolSpread(symbol) / 2
+	if side == models.TradeTypeBuy {
+		return price * (1 + halfSpread)
+	}
+	return price * (1 - halfSpread)
+}
+
+// StepPrices advances the simulated price clock by dt. Every symbol's price
+// follows the configured price model, a geometric Brownian motion by default;
+// a symbol catches up with the elapsed time in one exact step when it is next
+// priced, so the cost of a step does not grow with the size of the symbol
+// universe.
+func (pg *PatternGenerator) StepPrices(dt time.Duration) {
+	pg.priceClock += dt
+}
+
+// currentPrice returns a symbol's random-walk price, first moving it forward
+// over the price clock time elapsed since it was last priced
+func (pg *PatternGenerator) currentPrice(symbol string) float64 {
+	price, exists := pg.symbolPrices[symbol]
 	if !exists {
-		basePrice = 100.0 // Default price
+		price = DefaultSymbolPrice
 	}
 
-	// Add ±1% variation
-	variation := (rand.Float64() - 0.5) * 0.02
-	return basePrice * (1 + variation)
+	elapsed := (pg.priceClock - pg.priceUpdated[symbol]).Hours()
+	if elapsed <= 0 {
+		return price
+	}
+
+	dynamics := pg.priceDynamics(symbol)
+	switch pg.cfg.Generate.PriceModel {
+	case "jitter":
+		// Base prices hold still; only the per-trade jitter and jumps move them
+	case "mean_reverting":
+		price = pg.revertPrice(symbol, price, dynamics, elapsed)
+		pg.symbolPrices[symbol] = price
+	default:
+		if dynamics.Drift != 0 || dynamics.Volatility != 0 {
+			sigma := dynamics.Volatility
+			price *= math.Exp((dynamics.Drift-sigma*sigma/2)*elapsed + sigma*math.Sqrt(elapsed)*pg.rng.NormFloat64())
+			pg.symbolPrices[symbol] = price
+		}
+	}
+	pg.priceUpdated[symbol] = pg.priceClock
+
+	// Jumps arrive as a Poisson process; at most one lands per catch-up
+	if rate := pg.cfg.Generate.PriceJumpRate; rate > 0 && pg.rng.Float64() < 1-math.Exp(-rate*elapsed) {
+		size := pg.cfg.Generate.PriceJumpMin + pg.rng.Float64()*(pg.cfg.Generate.PriceJumpMax-pg.cfg.Generate.PriceJumpMin)
+		if pg.rng.Intn(2) == 0 {
+			size = -size
+		}
+		price = pg.Jump(symbol, size)
+	}
+
+	return price
 }
 
-// RandomTradeType returns a random trade type (50/50 buy/sell)
-func (pg *PatternGenerator) RandomTradeType() models.TradeType {
-	if rand.Float64() < 0.5 {
+// revertPrice moves a price over elapsed hours of an Ornstein-Uhlenbeck
+// process on the log price, which pulls it back toward the symbol's long-run
+// mean at the reversion speed, so a price knocked away by a jump or a pattern
+// drifts home. The step is exact for any elapsed time; with no reversion it is
+// a driftless random walk.
+func (pg *PatternGenerator) revertPrice(symbol string, price float64, dynamics config.PriceDynamics, elapsed float64) float64 {
+	mean := dynamics.Mean
+	if mean == 0 {
+		mean = DefaultSymbolPrice
+		if base, exists := pg.basePrices[symbol]; exists {
+			mean = base
+		}
+	}
+
+	theta, sigma := dynamics.Reversion, dynamics.Volatility
+	decay := math.Exp(-theta * elapsed)
+	variance := sigma * sigma * elapsed
+	if theta > 0 {
+		variance = sigma * sigma * (1 - decay*decay) / (2 * theta)
+	}
+
+	logMean := math.Log(mean)
+	return math.Exp(logMean + (math.Log(price)-logMean)*decay + math.Sqrt(variance)*pg.rng.NormFloat64())
+}
+
+// Jump shifts a symbol's price level by change (0.1 = up 10%), as a news
+// event or overnight gap would, and logs a marker record. Later trades and
+// the random walk continue from the new level. It returns the new price.
+func (pg *PatternGenerator) Jump(symbol string, change float64) float64 {
+	before := pg.currentPrice(symbol)
+	after := before * (1 + change)
+	pg.symbolPrices[symbol] = after
+
+	slog.Info("price jump", "symbol", symbol, "from", before, "to", after, "change", change,
+		logging.Text("💥 Price jump: %s %.2f -> %.2f (%+.1f%%)", symbol, before, after, change*100))
+	return after
+}
+
+// priceDynamics returns the random walk parameters for a symbol
+func (pg *PatternGenerator) priceDynamics(symbol string) config.PriceDynamics {
+	if dynamics, exists := pg.cfg.PriceDynamics[symbol]; exists {
+		return dynamics
+	}
+	return config.PriceDynamics{
+		Drift:      pg.cfg.Generate.PriceDrift,
+		Volatility: pg.cfg.Generate.PriceVolatility,
+		Reversion:  pg.cfg.Generate.PriceReversion,
+	}
+}
+
+// symbolVolatility returns the fractional per-trade price variation for a symbol
+func (pg *PatternGenerator) symbolVolatility(symbol string) float64 {
+	if jitter, exists := pg.cfg.PriceJitter[symbol]; exists {
+		return jitter
+	}
+	return defaultPriceVolatility
+}
+
+// symbolSpread returns the bid/ask spread for a symbol as a fraction of price
+func (pg *PatternGenerator) symbolSpread(symbol string) float64 {
+	if spread, exists := pg.cfg.Spreads[symbol]; exists {
+		return spread
+	}
+	return pg.cfg.Generate.Spread
+}
+
+// RandomLiquidity returns aggressive or passive according to the profile's aggressive ratio
+func (pg *PatternGenerator) RandomLiquidity(profile *profiles.TraderProfile) feed.Liquidity {
+	if pg.rng.Float64() < profile.GetAggressiveRatio() {
+		return feed.Aggressive
+	}
+	return feed.Passive
+}
+
+// RandomTradeType returns buy or sell according to the profile's buy ratio
+func (pg *PatternGenerator) RandomTradeType(profile *profiles.TraderProfile) models.TradeType {
+	if pg.rng.Float64() < profile.GetBuyRatio() {
 		return models.TradeTypeBuy
 	}
 	return models.TradeTypeSell
 }
 
+// SetPrices sets base prices for symbols, overriding the built-in table
+func (pg *PatternGenerator) SetPrices(prices map[string]float64) {
+	for symbol, price := range prices {
+		pg.symbolPrices[symbol] = price
+	}
+}
+
+// PriceState is the evolving state of the price model, saved in run snapshots
+type PriceState struct {
+	Prices  map[string]float64       `json:"prices"`
+	Clock   time.Duration            `json:"clock"`
+	Updated map[string]time.Duration `json:"updated"`
+}
+
+// PriceState returns a copy of the current prices and price clock
+func (pg *PatternGenerator) PriceState() PriceState {
+	return PriceState{
+		Prices:  maps.Clone(pg.symbolPrices),
+		Clock:   pg.priceClock,
+		Updated: maps.Clone(pg.priceUpdated),
+	}
+}
+
+// RestorePriceState resumes the price model from a saved state. Symbols the
+// state doesn't mention keep their configured prices.
+func (pg *PatternGenerator) RestorePriceState(state PriceState) {
+	maps.Copy(pg.symbolPrices, state.Prices)
+	pg.priceClock = state.Clock
+	pg.priceUpdated = make(map[string]time.Duration, len(state.Updated))
+	maps.Copy(pg.priceUpdated, state.Updated)
+}
+
+// HasPrice reports whether a symbol has a base price. Unpriced symbols trade
+// around the default of 100.
+func (pg *PatternGenerator) HasPrice(symbol string) bool {
+	_, exists := pg.symbolPrices[symbol]
+	return exists
+}
+
+// addSyntheticPrices assigns base prices to synthetic symbols, drawn log-uniformly
+// between $1 and $1000 from a fixed seed so a universe prices the same on every run
+func (pg *PatternGenerator) addSyntheticPrices(symbols []string) {
+	r := rand.New(rand.NewSource(syntheticPriceSeed))
+	for _, symbol := range symbols {
+		price := math.Pow(10, r.Float64()*3)
+		pg.symbolPrices[symbol] = math.Round(price*100) / 100
+	}
+}
+
 // getSymbolPrices returns a map of realistic symbol prices
 func getSymbolPrices() map[string]float64 {
 	return map[string]float64{
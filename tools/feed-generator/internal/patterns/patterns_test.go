@@ -0,0 +1,712 @@
+package patterns
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/profiles"
+)
+
+// newTestGenerator returns a seeded pattern generator over the built-in profiles
+func newTestGenerator(cfg *config.Config) (*PatternGenerator, []profiles.TraderProfile) {
+	traderProfiles := profiles.GetDefaultProfiles()
+	pg := NewPatternGenerator(cfg, rand.New(rand.NewSource(1)), rand.New(rand.NewSource(2)))
+	pg.SetVictims(traderProfiles)
+	return pg, traderProfiles
+}
+
+// fraudProfile returns the first built-in profile for a fraud type
+func fraudProfile(t *testing.T, traderProfiles []profiles.TraderProfile, fraudType profiles.FraudType) *profiles.TraderProfile {
+	t.Helper()
+	matches := profiles.FilterFraudProfiles(traderProfiles, fraudType)
+	if len(matches) == 0 {
+		t.Fatalf("no built-in profile for %s", fraudType)
+	}
+	return &matches[0]
+}
+
+func TestComboTradesOneSymbol(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.ComboPatterns = []string{"VELOCITY", "WASH", "PUMP_DUMP", "VELOCITY_MULTI", "FRONT_RUN", "ANOMALY"}
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.Combo)
+
+	for i := 0; i < 50; i++ {
+		trades, ok := pg.Inject(profiles.Combo, profile, time.Now())
+		if !ok || len(trades) == 0 {
+			t.Fatal("combo produced no trades")
+		}
+		for _, trade := range trades {
+			if trade.Symbol != trades[0].Symbol {
+				t.Fatalf("combo %d mixed symbols %s and %s", i, trades[0].Symbol, trade.Symbol)
+			}
+		}
+	}
+}
+
+func TestFraudSizeMultiplierScalesEveryInjector(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.FatFingerCorrection = 0
+	base, traderProfiles := newTestGenerator(cfg)
+
+	scaledCfg := config.Default()
+	scaledCfg.Generate.FatFingerCorrection = 0
+	scaledCfg.Generate.FraudSizeMultiplier = 3
+	scaled, _ := newTestGenerator(scaledCfg)
+
+	for _, fraudType := range base.FraudTypes() {
+		if fraudType == profiles.Combo || fraudType == profiles.FrontRunning {
+			continue // Built from other injectors, or sized by its victim
+		}
+		profile := fraudProfile(t, traderProfiles, fraudType)
+		baseTime := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+		want, _ := base.Inject(fraudType, profile, baseTime)
+		got, _ := scaled.Inject(fraudType, profile, baseTime)
+		if len(want) == 0 || len(got) != len(want) {
+			t.Errorf("%s: got %d trades, want %d", fraudType, len(got), len(want))
+			continue
+		}
+		if ratio := got[0].Amount / want[0].Amount; ratio < 2.99 || ratio > 3.01 {
+			t.Errorf("%s: multiplier 3 scaled the first trade by %.2f", fraudType, ratio)
+		}
+	}
+}
+
+func TestGBMVarianceScalesWithTime(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.PriceModel = "gbm"
+	cfg.Generate.PriceVolatility = 0.05
+	pg, _ := newTestGenerator(cfg)
+
+	// Independent walks: unknown symbols all start at the default price
+	symbols := make([]string, 2000)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("WALK_%d", i)
+	}
+
+	logReturnVariance := func() float64 {
+		var sum, sumSquares float64
+		for _, symbol := range symbols {
+			r := math.Log(pg.currentPrice(symbol) / DefaultSymbolPrice)
+			sum += r
+			sumSquares += r * r
+		}
+		n := float64(len(symbols))
+		return sumSquares/n - (sum/n)*(sum/n)
+	}
+
+	var variances []float64
+	for hour := 1; hour <= 4; hour++ {
+		for minute := 0; minute < 60; minute++ {
+			pg.StepPrices(time.Minute)
+			for _, symbol := range symbols {
+				pg.currentPrice(symbol)
+			}
+		}
+		variances = append(variances, logReturnVariance())
+	}
+
+	sigma2 := cfg.Generate.PriceVolatility * cfg.Generate.PriceVolatility
+	for i, variance := range variances {
+		want := sigma2 * float64(i+1)
+		if variance < want*0.85 || variance > want*1.15 {
+			t.Errorf("log return variance after %dh: got %.5f, want about %.5f", i+1, variance, want)
+		}
+	}
+}
+
+func TestCollusiveWashLegsShareSymbolAndAmount(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+	profile := fraudProfile(t, traderProfiles, profiles.Collusion)
+
+	for i := 0; i < 50; i++ {
+		trades := pg.InjectCollusiveWash(profile, time.Now())
+		if len(trades) < 4 || len(trades)%2 != 0 {
+			t.Fatalf("want at least two buy/sell legs, got %d trades", len(trades))
+		}
+		for leg := 0; leg < len(trades); leg += 2 {
+			buy, sell := trades[leg], trades[leg+1]
+			if buy.Symbol != trades[0].Symbol || sell.Symbol != buy.Symbol {
+				t.Errorf("leg %d: symbols %s/%s differ from %s", leg/2, buy.Symbol, sell.Symbol, trades[0].Symbol)
+			}
+			if buy.Amount != sell.Amount {
+				t.Errorf("leg %d: amounts %.2f and %.2f differ", leg/2, buy.Amount, sell.Amount)
+			}
+			if buy.UserID == sell.UserID {
+				t.Errorf("leg %d: %s trades with itself", leg/2, buy.UserID)
+			}
+		}
+	}
+}
+
+func TestRandomTradeTypeFollowsBuyRatio(t *testing.T) {
+	pg, _ := newTestGenerator(config.Default())
+
+	for _, ratio := range []float64{0.2, 0.5, 0.8} {
+		profile := &profiles.TraderProfile{BuyRatio: ratio}
+		buys := 0
+		const samples = 20000
+		for i := 0; i < samples; i++ {
+			if pg.RandomTradeType(profile) == models.TradeTypeBuy {
+				buys++
+			}
+		}
+		if got := float64(buys) / samples; math.Abs(got-ratio) > 0.02 {
+			t.Errorf("buy ratio %.1f: got buy fraction %.3f", ratio, got)
+		}
+	}
+}
+
+func TestSizeOnlyAnomalyWeights(t *testing.T) {
+	cfg := config.Default()
+	for _, anomalyType := range config.AnomalyTypes {
+		cfg.AnomalyWeights[anomalyType] = 0
+	}
+	cfg.AnomalyWeights["size"] = 1
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.Anomaly)
+
+	for i := 0; i < 100; i++ {
+		trade := pg.InjectAnomaly(profile, time.Now())
+		if want := profile.AvgTradeSize * 10; trade.Amount != want {
+			t.Fatalf("size-only anomaly %d: got amount %.2f, want %.2f", i, trade.Amount, want)
+		}
+	}
+}
+
+func TestSpreadSeparatesBuyAndSellPrices(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.PriceModel = "jitter"
+	cfg.Generate.Spread = 0.01
+	pg, _ := newTestGenerator(cfg)
+
+	var buys, sells float64
+	const samples = 5000
+	for i := 0; i < samples; i++ {
+		buys += pg.GetTradePrice("AAPL", models.TradeTypeBuy)
+		sells += pg.GetTradePrice("AAPL", models.TradeTypeSell)
+	}
+	mid := (buys + sells) / 2 / samples
+	if got := (buys - sells) / samples / mid; math.Abs(got-cfg.Generate.Spread) > 0.002 {
+		t.Errorf("average buy-sell difference: got %.4f of the mid, want about %.4f", got, cfg.Generate.Spread)
+	}
+}
+
+func TestOffHoursAnomalyIsExtendedHours(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	cfg := config.Default()
+	cfg.Generate.AnomalyType = "off_hours"
+	cfg.Generate.Location = tokyo // A host in Tokyo, where 2-5 AM is midday in New York
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.Anomaly)
+
+	for i := 0; i < 100; i++ {
+		trade := pg.InjectAnomaly(profile, time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC))
+		if !trade.HasCondition(feed.ExtendedHours) {
+			t.Fatalf("off-hours anomaly at %v has conditions %v, want EXTENDED_HOURS", trade.Timestamp, trade.Conditions)
+		}
+	}
+}
+
+func TestPennyStockAnomalyKeepsRestrictedSymbolsRealistic(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.AnomalyType = "penny_stock"
+	cfg.Generate.Symbols = []string{"AAPL", "TSLA"}
+	pg, traderProfiles := newTestGenerator(cfg)
+	profiles.RestrictSymbols(traderProfiles, cfg.Generate.Symbols)
+	profile := fraudProfile(t, traderProfiles, profiles.Anomaly)
+
+	for i := 0; i < 100; i++ {
+		trade := pg.InjectAnomaly(profile, time.Now())
+		base := getSymbolPrices()[trade.Symbol]
+		if trade.Symbol != "AAPL" && trade.Symbol != "TSLA" {
+			t.Fatalf("anomaly left the symbol list for %s", trade.Symbol)
+		}
+		if trade.Price < base/2 || trade.Price > base*2 {
+			t.Fatalf("%s anomaly priced at %.2f, nowhere near its %.2f base", trade.Symbol, trade.Price, base)
+		}
+	}
+}
+
+func TestSpoofAndMomentumExecutionsAreAggressive(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+
+	for _, fraudType := range []profiles.FraudType{profiles.SpoofPattern, profiles.Momentum} {
+		profile := fraudProfile(t, traderProfiles, fraudType)
+		for i := 0; i < 20; i++ {
+			trades, _ := pg.Inject(fraudType, profile, time.Now())
+			for _, trade := range trades {
+				want := feed.Aggressive
+				if trade.Cancelled {
+					want = feed.Passive // Resting spoof layers never traded
+				}
+				if trade.Liquidity != want {
+					t.Fatalf("%s: cancelled=%v trade tagged %s, want %s", fraudType, trade.Cancelled, trade.Liquidity, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSizeDistributionMeanAndMedian(t *testing.T) {
+	const n = 20000
+	tests := []struct {
+		distribution profiles.SizeDistribution
+		volatility   float64
+		wantMedian   float64 // As a fraction of AvgTradeSize
+	}{
+		{profiles.NormalSizes, 0.3, 1},
+		{profiles.LognormalSizes, 0.8, 1 / math.Sqrt(1+0.8*0.8)}, // exp(mu) sits below the mean
+	}
+
+	for _, tt := range tests {
+		pg, _ := newTestGenerator(config.Default())
+		profile := &profiles.TraderProfile{AvgTradeSize: 1000, Volatility: tt.volatility, SizeDistribution: tt.distribution}
+
+		amounts := make([]float64, n)
+		sum := 0.0
+		for i := range amounts {
+			amounts[i] = pg.GenerateAmount(profile)
+			sum += amounts[i]
+		}
+		sort.Float64s(amounts)
+		mean, median := sum/n, amounts[n/2]
+
+		if mean < 970 || mean > 1030 {
+			t.Errorf("%s: got mean %.1f, want about 1000", tt.distribution, mean)
+		}
+		if want := 1000 * tt.wantMedian; median < want*0.97 || median > want*1.03 {
+			t.Errorf("%s: got median %.1f, want about %.1f", tt.distribution, median, want)
+		}
+	}
+}
+
+func TestConfiguredPricesOverrideBuiltInTable(t *testing.T) {
+	cfg := config.Default()
+	cfg.Prices = map[string]float64{"AAPL": 250, "NEWCO": 12.5}
+	pg, _ := newTestGenerator(cfg)
+
+	for symbol, want := range map[string]float64{"AAPL": 250, "NEWCO": 12.5, "MSFT": 378.25} {
+		if got := pg.currentPrice(symbol); got != want {
+			t.Errorf("%s: got base price %.2f, want %.2f", symbol, got, want)
+		}
+	}
+
+	// A later SetPrices wins over both the table and the config
+	pg.SetPrices(map[string]float64{"AAPL": 300, "MSFT": 400})
+	for symbol, want := range map[string]float64{"AAPL": 300, "MSFT": 400, "NEWCO": 12.5} {
+		if got := pg.currentPrice(symbol); got != want {
+			t.Errorf("%s after SetPrices: got %.2f, want %.2f", symbol, got, want)
+		}
+	}
+	if pg.HasPrice("UNLISTED") {
+		t.Error("a symbol with no configured price reports one")
+	}
+}
+
+func TestCrossSymbolVelocityCoversEverySymbolInWindow(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+	profile := fraudProfile(t, traderProfiles, profiles.VelocityMulti)
+	symbols := []string{"SPY", "AAPL", "MSFT", "NVDA"}
+	baseTime := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	trades := pg.InjectCrossSymbolVelocity(profile, symbols, baseTime)
+	seen := make(map[string]int)
+	for i, trade := range trades {
+		seen[trade.Symbol]++
+		if trade.Timestamp.Before(baseTime) || !trade.Timestamp.Before(baseTime.Add(5*time.Second)) {
+			t.Errorf("%s trade at %v, outside the 5s window from %v", trade.Symbol, trade.Timestamp, baseTime)
+		}
+		if i > 0 && trade.Timestamp.Before(trades[i-1].Timestamp) {
+			t.Errorf("trade %d at %v precedes the one before it", i, trade.Timestamp)
+		}
+	}
+	for _, symbol := range symbols {
+		if seen[symbol] < 5 {
+			t.Errorf("%s: got %d trades, want at least 5", symbol, seen[symbol])
+		}
+	}
+	if len(seen) != len(symbols) {
+		t.Errorf("burst traded %d symbols, want only the %d requested", len(seen), len(symbols))
+	}
+}
+
+func TestQuoteStuffingBurstFitsOneSecond(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.QuoteStuffSize = 200
+	cfg.Generate.QuoteStuffCancelRatio = 0.8
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.QuoteStuffing)
+	baseTime := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	orders, cancelled := 0, 0
+	for burst := 0; burst < 20; burst++ {
+		trades := pg.InjectQuoteStuffing(profile, baseTime)
+		if len(trades) != cfg.Generate.QuoteStuffSize {
+			t.Fatalf("got %d orders, want %d", len(trades), cfg.Generate.QuoteStuffSize)
+		}
+		for _, trade := range trades {
+			if trade.Timestamp.Before(baseTime) || !trade.Timestamp.Before(baseTime.Add(time.Second)) {
+				t.Fatalf("order at %v, outside the second from %v", trade.Timestamp, baseTime)
+			}
+			orders++
+			if trade.Cancelled {
+				cancelled++
+			}
+		}
+	}
+
+	if ratio := float64(cancelled) / float64(orders); math.Abs(ratio-0.8) > 0.02 {
+		t.Errorf("got cancel ratio %.3f, want about 0.8", ratio)
+	}
+}
+
+func TestMomentumIgnitionEscalatesThenReverses(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+	profile := fraudProfile(t, traderProfiles, profiles.Momentum)
+
+	for i := 0; i < 20; i++ {
+		trades := pg.InjectMomentumIgnition(profile, time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC))
+		ignition, unwind := trades[:len(trades)-1], trades[len(trades)-1]
+		if len(ignition) < 4 {
+			t.Fatalf("got %d ignition orders, want at least 4", len(ignition))
+		}
+
+		side := ignition[0].Type
+		position := 0.0
+		for j, trade := range ignition {
+			if trade.Type != side {
+				t.Fatalf("ignition order %d is a %s in a %s cluster", j, trade.Type, side)
+			}
+			position += trade.Amount
+			if j == 0 {
+				continue
+			}
+			if trade.Amount <= ignition[j-1].Amount {
+				t.Errorf("ignition order %d: amount %.2f does not exceed %.2f", j, trade.Amount, ignition[j-1].Amount)
+			}
+			rising := trade.Price > ignition[j-1].Price
+			if rising != (side == models.TradeTypeBuy) {
+				t.Errorf("ignition order %d: %s cluster moved the price from %.2f to %.2f", j, side, ignition[j-1].Price, trade.Price)
+			}
+		}
+
+		if unwind.Type == side {
+			t.Errorf("unwind is a %s, want the opposite of the %s cluster", unwind.Type, side)
+		}
+		if math.Abs(unwind.Amount-position) > 1e-6 {
+			t.Errorf("unwind amount %.2f, want the %.2f position", unwind.Amount, position)
+		}
+		if !unwind.Timestamp.After(ignition[len(ignition)-1].Timestamp.Add(time.Second)) {
+			t.Error("unwind follows the cluster without a pause")
+		}
+	}
+}
+
+func TestComboEmbedsWashPairInVelocityBurst(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.ComboPatterns = []string{"VELOCITY", "WASH"}
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.Combo)
+	baseTime := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 20; i++ {
+		trades := pg.InjectCombo(profile, baseTime)
+
+		// The wash pair is the buy and sell sharing one amount
+		var buy, sell *feed.Trade
+		var burst []*feed.Trade
+		byAmount := make(map[float64][]*feed.Trade)
+		for _, trade := range trades {
+			byAmount[trade.Amount] = append(byAmount[trade.Amount], trade)
+		}
+		for _, trade := range trades {
+			if pair := byAmount[trade.Amount]; len(pair) == 2 && pair[0].Type == models.TradeTypeBuy && pair[1].Type == models.TradeTypeSell {
+				buy, sell = pair[0], pair[1]
+				continue
+			}
+			burst = append(burst, trade)
+		}
+		if buy == nil {
+			t.Fatalf("combo %d has no matched buy/sell pair", i)
+		}
+		if buy.UserID != sell.UserID || buy.Symbol != sell.Symbol {
+			t.Errorf("combo %d: wash legs by %s in %s and %s in %s", i, buy.UserID, buy.Symbol, sell.UserID, sell.Symbol)
+		}
+		if diff := math.Abs(sell.Price-buy.Price) / buy.Price; diff > 0.001 {
+			t.Errorf("combo %d: wash legs %.2f%% apart in price", i, diff*100)
+		}
+
+		// The burst is 10 or more trades a second apart around the pair
+		if len(burst) < 10 {
+			t.Fatalf("combo %d: got %d burst trades, want at least 10", i, len(burst))
+		}
+		first, last := burst[0].Timestamp, burst[len(burst)-1].Timestamp
+		if span := last.Sub(first); span > 20*time.Second {
+			t.Errorf("combo %d: burst spans %v, want a spike of at most 20s", i, span)
+		}
+		if buy.Timestamp.Before(first) || sell.Timestamp.After(last) {
+			t.Errorf("combo %d: wash pair %v-%v outside the burst %v-%v", i, buy.Timestamp, sell.Timestamp, first, last)
+		}
+	}
+}
+
+func TestMarketMakerQuotesStraddleMid(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+	var profile *profiles.TraderProfile
+	for i := range traderProfiles {
+		if traderProfiles[i].Type == profiles.MarketMakerTrader {
+			profile = &traderProfiles[i]
+			break
+		}
+	}
+	if profile == nil {
+		t.Fatal("no built-in market maker profile")
+	}
+
+	for i := 0; i < 100; i++ {
+		quotes := pg.MarketMakerQuotes(profile, time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC))
+		if len(quotes) != 2 {
+			t.Fatalf("got %d quotes, want a bid and an ask", len(quotes))
+		}
+		bid, ask := quotes[0], quotes[1]
+		if bid.Type == models.TradeTypeSell {
+			bid, ask = ask, bid
+		}
+		mid := pg.currentPrice(bid.Symbol)
+		if bid.Type != models.TradeTypeBuy || ask.Type != models.TradeTypeSell {
+			t.Fatalf("got a %s and a %s, want one of each side", quotes[0].Type, quotes[1].Type)
+		}
+		if !(bid.Price < mid && mid < ask.Price) {
+			t.Errorf("%s: bid %.4f and ask %.4f don't straddle mid %.4f", bid.Symbol, bid.Price, ask.Price, mid)
+		}
+		if bid.Symbol != ask.Symbol || bid.Amount != ask.Amount {
+			t.Errorf("quotes in %s x%.0f and %s x%.0f, want one symbol and size", bid.Symbol, bid.Amount, ask.Symbol, ask.Amount)
+		}
+	}
+}
+
+func TestJumpMovesLaterPrices(t *testing.T) {
+	pg, _ := newTestGenerator(config.Default())
+	before := pg.currentPrice("AAPL")
+
+	after := pg.Jump("AAPL", -0.15)
+	if want := before * 0.85; math.Abs(after-want) > 1e-9 {
+		t.Fatalf("jump returned %.2f, want %.2f", after, want)
+	}
+
+	// Later trades jitter around the new level, far from the old one
+	pg.StepPrices(time.Minute)
+	sum := 0.0
+	for i := 0; i < 1000; i++ {
+		price := pg.GetPrice("AAPL")
+		if price > before*0.9 {
+			t.Fatalf("price %.2f still near the pre-jump %.2f", price, before)
+		}
+		sum += price
+	}
+	if mean := sum / 1000; math.Abs(mean-after)/after > 0.01 {
+		t.Errorf("prices average %.2f after the jump, want about %.2f", mean, after)
+	}
+}
+
+func TestPennyJitterExceedsBlueChip(t *testing.T) {
+	cfg := config.Default()
+	cfg.PriceJitter = map[string]float64{"PENNY_A": 0.10}
+	pg, _ := newTestGenerator(cfg)
+
+	// Variance of each price relative to its level, so a $2 and a $175 stock compare
+	relativeVariance := func(symbol string) float64 {
+		base := pg.currentPrice(symbol)
+		var sum, sumSquares float64
+		for i := 0; i < 5000; i++ {
+			r := pg.GetPrice(symbol)/base - 1
+			sum += r
+			sumSquares += r * r
+		}
+		return sumSquares/5000 - (sum/5000)*(sum/5000)
+	}
+
+	penny, blueChip := relativeVariance("PENNY_A"), relativeVariance("AAPL")
+	// Uniform jitter of ±j has variance j²/3: 100x apart for ±10% against ±1%
+	if penny < 50*blueChip {
+		t.Errorf("got relative variance %.6f for PENNY_A and %.6f for AAPL, want the penny stock about 100x wider", penny, blueChip)
+	}
+	if want := 0.10 * 0.10 / 3; math.Abs(penny-want)/want > 0.1 {
+		t.Errorf("PENNY_A relative variance %.5f, want about %.5f", penny, want)
+	}
+}
+
+func TestSymbolMetadataFallsBackToDefaultSector(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.DefaultSector = "UNCLASSIFIED"
+	pg, _ := newTestGenerator(cfg)
+
+	tests := []struct {
+		symbol string
+		want   config.SymbolInfo
+	}{
+		{"AAPL", config.SymbolInfo{Sector: "TECHNOLOGY", AssetClass: AssetEquity}},
+		{"AMZN", config.SymbolInfo{Sector: "CONSUMER_DISCRETIONARY", AssetClass: AssetEquity}},
+		{"SPY", config.SymbolInfo{Sector: "BROAD_MARKET", AssetClass: AssetETF}},
+		{"NEWCO", config.SymbolInfo{Sector: "UNCLASSIFIED", AssetClass: cfg.Generate.DefaultAssetClass}},
+	}
+	for _, tt := range tests {
+		if got := pg.SymbolMetadata(tt.symbol); got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestTradingRingNetsOutWithHighGrossVolume(t *testing.T) {
+	for _, ringSize := range []int{0, 3, 6} {
+		cfg := config.Default()
+		cfg.Generate.RingSize = ringSize
+		pg, traderProfiles := newTestGenerator(cfg)
+		profile := fraudProfile(t, traderProfiles, profiles.TradingRing)
+
+		for i := 0; i < 20; i++ {
+			trades, ok := pg.Inject(profiles.TradingRing, profile, time.Now())
+			if !ok || len(trades) == 0 {
+				t.Fatalf("ring size %d: no trades injected", ringSize)
+			}
+
+			net := make(map[string]float64)
+			gross := make(map[string]float64)
+			largestLeg := 0.0
+			for _, trade := range trades {
+				if trade.Symbol != trades[0].Symbol {
+					t.Fatalf("ring size %d: ring traded %s and %s", ringSize, trades[0].Symbol, trade.Symbol)
+				}
+				if trade.Type == models.TradeTypeBuy {
+					net[trade.UserID] += trade.Amount
+				} else {
+					net[trade.UserID] -= trade.Amount
+				}
+				gross[trade.UserID] += trade.Amount
+				largestLeg = max(largestLeg, trade.Amount)
+			}
+
+			if ringSize != 0 && len(net) != ringSize {
+				t.Errorf("ring size %d: %d accounts traded", ringSize, len(net))
+			}
+			for account, position := range net {
+				if math.Abs(position) > 1e-6*gross[account] {
+					t.Errorf("ring size %d: %s ends with net position %.2f", ringSize, account, position)
+				}
+				// At least two rounds of buying and selling
+				if gross[account] < 2*largestLeg {
+					t.Errorf("ring size %d: %s traded %.2f gross, want several legs' worth", ringSize, account, gross[account])
+				}
+			}
+		}
+	}
+}
+
+func TestFatFingerCorrectionReversesError(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.FatFingerCorrection = 1
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.FatFinger)
+	baseTime := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 100; i++ {
+		trades, ok := pg.Inject(profiles.FatFinger, profile, baseTime)
+		if !ok || len(trades) != 2 {
+			t.Fatalf("injection %d: got %d trades, want the error and its correction", i, len(trades))
+		}
+		errorTrade, correction := trades[0], trades[1]
+
+		if correction.Type == errorTrade.Type {
+			t.Errorf("injection %d: correction is a %s like the error", i, correction.Type)
+		}
+		if correction.UserID != errorTrade.UserID || correction.Symbol != errorTrade.Symbol || correction.Amount != errorTrade.Amount {
+			t.Errorf("injection %d: correction %s %s %.0f doesn't match error %s %s %.0f", i,
+				correction.UserID, correction.Symbol, correction.Amount, errorTrade.UserID, errorTrade.Symbol, errorTrade.Amount)
+		}
+		if delay := correction.Timestamp.Sub(errorTrade.Timestamp); delay < 5*time.Second || delay > 30*time.Second {
+			t.Errorf("injection %d: correction came %v after the error, want 5-30s", i, delay)
+		}
+	}
+}
+
+func TestMeanRevertingPriceDriftsBack(t *testing.T) {
+	cfg := config.Default()
+	cfg.Generate.PriceModel = "mean_reverting"
+	cfg.Generate.PriceReversion = 2 // Per hour, so a displacement decays by e^-2 an hour
+	cfg.Generate.PriceVolatility = 0.01
+	pg, _ := newTestGenerator(cfg)
+	base := pg.currentPrice("AAPL")
+
+	pushed := pg.Jump("AAPL", 0.2)
+	displacement := math.Log(pushed / base)
+
+	pg.StepPrices(time.Hour)
+	afterHour := math.Log(pg.currentPrice("AAPL") / base)
+	if want := displacement * math.Exp(-2); math.Abs(afterHour-want) > 0.03 {
+		t.Errorf("log displacement %.3f an hour after the push, want about %.3f", afterHour, want)
+	}
+
+	pg.StepPrices(5 * time.Hour)
+	if price := pg.currentPrice("AAPL"); math.Abs(price/base-1) > 0.03 {
+		t.Errorf("price %.2f six hours after the push, want back near %.2f", price, base)
+	}
+}
+
+func TestFrontRunningHonoursFraudSymbols(t *testing.T) {
+	cfg := config.Default()
+	cfg.FraudSymbols["FRONT_RUN"] = []string{"NVDA", "AMD"}
+	pg, traderProfiles := newTestGenerator(cfg)
+	profile := fraudProfile(t, traderProfiles, profiles.FrontRunning)
+
+	for i := 0; i < 50; i++ {
+		trades, ok := pg.Inject(profiles.FrontRunning, profile, time.Now())
+		if !ok || len(trades) != 3 {
+			t.Fatalf("injection %d: got %d trades, want 3", i, len(trades))
+		}
+		for _, trade := range trades {
+			if trade.Symbol != "NVDA" && trade.Symbol != "AMD" {
+				t.Fatalf("injection %d: traded %s outside the configured symbols", i, trade.Symbol)
+			}
+		}
+	}
+}
+
+func TestFrontRunningEntersBeforeVictimAndExitsAfter(t *testing.T) {
+	pg, traderProfiles := newTestGenerator(config.Default())
+	profile := fraudProfile(t, traderProfiles, profiles.FrontRunning)
+	baseTime := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 100; i++ {
+		trades, ok := pg.Inject(profiles.FrontRunning, profile, baseTime)
+		if !ok || len(trades) != 3 {
+			t.Fatalf("injection %d: got %d trades, want entry, victim and exit", i, len(trades))
+		}
+		entry, victim, exit := trades[0], trades[1], trades[2]
+
+		if entry.UserID != profile.UserID || exit.UserID != profile.UserID || victim.UserID == profile.UserID {
+			t.Fatalf("injection %d: traders %s, %s, %s, want %s around a victim",
+				i, entry.UserID, victim.UserID, exit.UserID, profile.UserID)
+		}
+		if entry.Type != victim.Type || exit.Type == entry.Type {
+			t.Errorf("injection %d: entry %s, victim %s, exit %s, want the exit opposite the shared side",
+				i, entry.Type, victim.Type, exit.Type)
+		}
+		if lead := victim.Timestamp.Sub(entry.Timestamp); lead < 50*time.Millisecond || lead > 500*time.Millisecond {
+			t.Errorf("injection %d: entry %v before the victim, want 50-500ms", i, lead)
+		}
+		if !exit.Timestamp.After(victim.Timestamp) {
+			t.Errorf("injection %d: exit at %v doesn't follow the victim at %v", i, exit.Timestamp, victim.Timestamp)
+		}
+	}
+}
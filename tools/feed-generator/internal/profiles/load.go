@@ -0,0 +1,199 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadProfiles reads trader profiles from a YAML or JSON file (chosen by
+// extension) and validates them
+func LoadProfiles(path string) ([]TraderProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var profiles []TraderProfile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &profiles)
+	default:
+		err = yaml.Unmarshal(data, &profiles)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("profiles file %s contains no profiles", path)
+	}
+
+	for i := range profiles {
+		if profiles[i].FraudPattern == "" {
+			profiles[i].FraudPattern = NoFraud
+		}
+		if err := profiles[i].Validate(); err != nil {
+			return nil, fmt.Errorf("profile %d (%s): %w", i, profiles[i].UserID, err)
+		}
+	}
+
+	return profiles, nil
+}
+
+// knownFraudType reports whether fraudType names a fraud pattern or NONE
+func knownFraudType(fraudType FraudType) bool {
+	switch fraudType {
+	case NoFraud, WashTrade, VelocitySpike, VelocityMulti, Anomaly, Imbalance, FragmentedWash, SpoofPattern, PumpDump, Collusion, QuoteStuffing, Momentum, FrontRunning, Combo, TradingRing, FatFinger, Insider:
+		return true
+	}
+	return false
+}
+
+// validateFraudMix checks the fraud mix names drawable patterns with
+// non-negative weights, at least one of them positive
+func (p *TraderProfile) validateFraudMix() error {
+	if len(p.FraudMix) == 0 {
+		return nil
+	}
+	total := 0.0
+	for fraudType, weight := range p.FraudMix {
+		if !knownFraudType(fraudType) || fraudType == NoFraud {
+			return fmt.Errorf("unknown fraud pattern %q in fraud_mix", fraudType)
+		}
+		if fraudType == Insider {
+			return fmt.Errorf("fraud_mix can't include %s, which is driven by news events; use fraud_pattern: %s", Insider, Insider)
+		}
+		if weight < 0 {
+			return fmt.Errorf("fraud_mix weight for %s must be non-negative, got %.2f", fraudType, weight)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return fmt.Errorf("fraud_mix must give at least one pattern a positive weight")
+	}
+	return nil
+}
+
+// validatePatternFields checks the fields a fraud pattern relies on
+func (p *TraderProfile) validatePatternFields(fraudType FraudType) error {
+	switch fraudType {
+	case Collusion:
+		if len(p.LinkedUserIDs) == 0 {
+			return fmt.Errorf("fraud pattern %s requires at least one linked_user_ids entry", Collusion)
+		}
+	case TradingRing:
+		if len(p.LinkedUserIDs) < 2 {
+			return fmt.Errorf("fraud pattern %s requires at least two linked_user_ids entries, got %d", TradingRing, len(p.LinkedUserIDs))
+		}
+	case VelocityMulti:
+		if len(p.RelatedSymbols) == 1 {
+			return fmt.Errorf("related_symbols must list at least two symbols, got %v", p.RelatedSymbols)
+		}
+		if len(p.RelatedSymbols) == 0 && len(p.TypicalSymbols) < 2 {
+			return fmt.Errorf("fraud pattern %s requires two or more related_symbols or typical_symbols", VelocityMulti)
+		}
+	}
+	return nil
+}
+
+// Validate checks that a profile's fields are usable for generation
+func (p *TraderProfile) Validate() error {
+	if p.UserID == "" {
+		return fmt.Errorf("user_id must not be empty")
+	}
+
+	switch p.Type {
+	case HFTTrader, RegularTrader, CasualTrader, MarketMakerTrader, FraudTrader:
+	default:
+		return fmt.Errorf("unknown trader type %q", p.Type)
+	}
+
+	if !knownFraudType(p.FraudPattern) {
+		return fmt.Errorf("unknown fraud pattern %q", p.FraudPattern)
+	}
+	if err := p.validateFraudMix(); err != nil {
+		return err
+	}
+	if p.Type == FraudTrader && p.FraudPattern == NoFraud && len(p.FraudMix) == 0 {
+		return fmt.Errorf("fraud traders must set a fraud pattern or fraud mix")
+	}
+	if p.Type != FraudTrader && p.FraudPattern != NoFraud {
+		return fmt.Errorf("fraud pattern %s requires trader type %s", p.FraudPattern, FraudTrader)
+	}
+	if p.Type != FraudTrader && len(p.FraudMix) > 0 {
+		return fmt.Errorf("fraud_mix requires trader type %s", FraudTrader)
+	}
+
+	for _, fraudType := range []FraudType{Collusion, TradingRing, VelocityMulti} {
+		if p.UsesFraudType(fraudType) {
+			if err := p.validatePatternFields(fraudType); err != nil {
+				return err
+			}
+		}
+	}
+	for _, linked := range p.LinkedUserIDs {
+		if linked == "" || linked == p.UserID {
+			return fmt.Errorf("linked_user_ids must name other accounts, got %q", linked)
+		}
+	}
+
+	switch p.SizeDistribution {
+	case "", NormalSizes, LognormalSizes:
+	default:
+		return fmt.Errorf("unknown size_distribution %q, must be %s or %s", p.SizeDistribution, NormalSizes, LognormalSizes)
+	}
+
+	if len(p.TypicalSymbols) == 0 {
+		return fmt.Errorf("typical_symbols must list at least one symbol")
+	}
+	if p.AvgTradeSize <= 0 {
+		return fmt.Errorf("avg_trade_size must be positive, got %.2f", p.AvgTradeSize)
+	}
+	for _, hour := range p.ActiveHours {
+		if hour < 0 || hour > 23 {
+			return fmt.Errorf("active hour %d must be between 0 and 23", hour)
+		}
+	}
+	for _, day := range p.ActiveDays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("active day %d must be between 0 (Sunday) and 6 (Saturday)", day)
+		}
+	}
+	if p.AggressiveRatio < 0 || p.AggressiveRatio > 1 {
+		return fmt.Errorf("aggressive_ratio must be between 0.0 and 1.0, got %.2f", p.AggressiveRatio)
+	}
+	if p.BuyRatio < 0 || p.BuyRatio > 1 {
+		return fmt.Errorf("buy_ratio must be between 0.0 and 1.0, got %.2f", p.BuyRatio)
+	}
+	if p.TypicalRatio < 0 || p.TypicalRatio > 1 {
+		return fmt.Errorf("typical_ratio must be between 0.0 and 1.0, got %.2f", p.TypicalRatio)
+	}
+	if p.QuoteSpread < 0 || p.QuoteSpread >= 0.1 {
+		return fmt.Errorf("quote_spread must be between 0.0 and 0.1, got %.4f", p.QuoteSpread)
+	}
+	if p.QuoteSpread != 0 && p.Type != MarketMakerTrader {
+		return fmt.Errorf("quote_spread requires trader type %s", MarketMakerTrader)
+	}
+	if p.OrderToTrade != 0 && (p.OrderToTrade < 1 || p.OrderToTrade > 1000) {
+		return fmt.Errorf("order_to_trade must be between 1 and 1000, got %.2f", p.OrderToTrade)
+	}
+
+	total := 0.0
+	for symbol, weight := range p.SymbolWeights {
+		if weight < 0 {
+			return fmt.Errorf("symbol weight for %s must be non-negative, got %.2f", symbol, weight)
+		}
+		total += weight
+	}
+	if len(p.SymbolWeights) > 0 && total == 0 {
+		return fmt.Errorf("symbol_weights must have at least one positive weight")
+	}
+
+	return nil
+}
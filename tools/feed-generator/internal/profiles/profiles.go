@@ -1,41 +1,87 @@
 package profiles
 
 import (
+	"fmt"
 	"math/rand"
+	"slices"
+	"sort"
+	"strconv"
 	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
 )
 
 // TraderType represents the type of trader
 type TraderType string
 
 const (
-	HFTTrader     TraderType = "HFT"
-	RegularTrader TraderType = "REGULAR"
-	CasualTrader  TraderType = "CASUAL"
-	FraudTrader   TraderType = "FRAUD"
+	HFTTrader         TraderType = "HFT"
+	RegularTrader     TraderType = "REGULAR"
+	CasualTrader      TraderType = "CASUAL"
+	MarketMakerTrader TraderType = "MM"
+	FraudTrader       TraderType = "FRAUD"
 )
 
 // FraudType represents the type of fraud pattern
 type FraudType string
 
 const (
-	NoFraud       FraudType = "NONE"
-	WashTrade     FraudType = "WASH"
-	VelocitySpike FraudType = "VELOCITY"
-	Anomaly       FraudType = "ANOMALY"
-	AllFraud      FraudType = "ALL"
+	NoFraud        FraudType = "NONE"
+	WashTrade      FraudType = "WASH"
+	VelocitySpike  FraudType = "VELOCITY"
+	VelocityMulti  FraudType = "VELOCITY_MULTI"
+	Anomaly        FraudType = "ANOMALY"
+	Imbalance      FraudType = "IMBALANCE"
+	FragmentedWash FraudType = "FRAGMENTED_WASH"
+	SpoofPattern   FraudType = "SPOOF"
+	PumpDump       FraudType = "PUMP_DUMP"
+	Collusion      FraudType = "COLLUSION"
+	QuoteStuffing  FraudType = "QUOTE_STUFF"
+	Momentum       FraudType = "MOMENTUM"
+	FrontRunning   FraudType = "FRONT_RUN"
+	Combo          FraudType = "COMBO"
+	TradingRing    FraudType = "RING"
+	FatFinger      FraudType = "FAT_FINGER"
+	Insider        FraudType = "INSIDER" // Driven by news events, never drawn at random
+	AllFraud       FraudType = "ALL"
+)
+
+// SizeDistribution is the distribution a trader's trade sizes are drawn from
+type SizeDistribution string
+
+const (
+	NormalSizes    SizeDistribution = "normal"
+	LognormalSizes SizeDistribution = "lognormal"
 )
 
 // TraderProfile defines a trader's behavioral characteristics
 type TraderProfile struct {
-	UserID         string
-	Type           TraderType
-	TypicalSymbols []string
-	AvgTradeSize   float64
-	Volatility     float64 // Standard deviation multiplier (0.0-1.0)
-	ActiveHours    []int   // Hours when trader is active (0-23)
-	TradesPerHour  int     // Expected trades per hour
-	FraudPattern   FraudType
+	UserID           string             `yaml:"user_id" json:"user_id"`
+	Type             TraderType         `yaml:"type" json:"type"`
+	TypicalSymbols   []string           `yaml:"typical_symbols" json:"typical_symbols"`
+	AvgTradeSize     float64            `yaml:"avg_trade_size" json:"avg_trade_size"`
+	Volatility       float64            `yaml:"volatility" json:"volatility"`           // Standard deviation multiplier (0.0-1.0)
+	ActiveHours      []int              `yaml:"active_hours" json:"active_hours"`       // Hours when trader is active (0-23)
+	ActiveDays       []time.Weekday     `yaml:"active_days" json:"active_days"`         // Days when trader is active (0 = Sunday; empty = Monday-Friday)
+	TradesPerHour    int                `yaml:"trades_per_hour" json:"trades_per_hour"` // Expected trades per hour
+	FraudPattern     FraudType          `yaml:"fraud_pattern" json:"fraud_pattern"`
+	AggressiveRatio  float64            `yaml:"aggressive_ratio" json:"aggressive_ratio"`   // Fraction of trades crossing the spread (0 = default 0.5)
+	BuyRatio         float64            `yaml:"buy_ratio" json:"buy_ratio"`                 // Fraction of trades that are buys (0 = default 0.5)
+	SizeDistribution SizeDistribution   `yaml:"size_distribution" json:"size_distribution"` // Trade size distribution (empty = normal)
+	LinkedUserIDs    []string           `yaml:"linked_user_ids" json:"linked_user_ids"`     // Colluding accounts this trader routes legs through
+	RelatedSymbols   []string           `yaml:"related_symbols" json:"related_symbols"`     // Symbols burst together by cross-symbol velocity spikes
+	SymbolWeights    map[string]float64 `yaml:"symbol_weights" json:"symbol_weights"`       // Relative weights for typical-symbol draws (empty = uniform over typical_symbols)
+	TypicalRatio     float64            `yaml:"typical_ratio" json:"typical_ratio"`         // Fraction of trades in typical symbols rather than exploring (0 = default 0.8)
+	QuoteSpread      float64            `yaml:"quote_spread" json:"quote_spread"`           // Market makers' quoted spread as a fraction of the mid (0 = default 0.0005)
+	OrderToTrade     float64            `yaml:"order_to_trade" json:"order_to_trade"`       // Orders placed per execution, the rest cancelled (0 = default 1, no cancels)
+
+	// FraudMix weights the patterns a fraud trader switches between, e.g.
+	// {WASH: 0.6, VELOCITY: 0.4}. When empty the trader only uses FraudPattern.
+	FraudMix map[FraudType]float64 `yaml:"fraud_mix" json:"fraud_mix"`
+
+	// Universe replaces the named symbol set the trader explores outside its
+	// typical symbols. Set by RestrictSymbols, never loaded from a file.
+	Universe []string `yaml:"-" json:"-"`
 }
 
 // Symbol lists for different trader types
@@ -46,171 +92,412 @@ var (
 	PennyStocks     = []string{"PENNY_A", "PENNY_B", "PENNY_C", "MICRO_X", "MICRO_Y"}
 )
 
+// SyntheticSymbols returns n generated tickers (SYMBOL_0000, SYMBOL_0001, ...)
+// for scale testing against a large symbol universe
+func SyntheticSymbols(n int) []string {
+	width := len(strconv.Itoa(n - 1))
+	if width < 4 {
+		width = 4
+	}
+
+	symbols := make([]string, n)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("SYMBOL_%0*d", width, i)
+	}
+	return symbols
+}
+
+// AssignSymbols deals symbols round-robin across the normal profiles, replacing
+// their typical symbols. Fraud profiles keep their own symbols.
+func AssignSymbols(profiles []TraderProfile, symbols []string) {
+	var normal []int
+	for i := range profiles {
+		if profiles[i].Type != FraudTrader {
+			normal = append(normal, i)
+			profiles[i].TypicalSymbols = nil
+			profiles[i].SymbolWeights = nil
+		}
+	}
+	if len(normal) == 0 {
+		return
+	}
+
+	for i, symbol := range symbols {
+		p := &profiles[normal[i%len(normal)]]
+		p.TypicalSymbols = append(p.TypicalSymbols, symbol)
+	}
+}
+
+// RestrictSymbols confines every profile, fraud profiles included, to the
+// given symbols: they become each profile's typical, related and exploration
+// symbols, and symbol weights are dropped
+func RestrictSymbols(profiles []TraderProfile, symbols []string) {
+	for i := range profiles {
+		profiles[i].TypicalSymbols = slices.Clone(symbols)
+		profiles[i].RelatedSymbols = slices.Clone(symbols)
+		profiles[i].SymbolWeights = nil
+		profiles[i].Universe = symbols
+	}
+}
+
 // GetDefaultProfiles returns a set of default trader profiles
 func GetDefaultProfiles() []TraderProfile {
 	return []TraderProfile{
 		// High-Frequency Traders (20% of users, 80% of volume)
 		{
-			UserID:         "HFT_001",
-			Type:           HFTTrader,
-			TypicalSymbols: BlueChipSymbols,
-			AvgTradeSize:   75000,
-			Volatility:     0.2,
-			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15},
-			TradesPerHour:  100,
-			FraudPattern:   NoFraud,
+			UserID:          "HFT_001",
+			Type:            HFTTrader,
+			TypicalSymbols:  BlueChipSymbols,
+			AvgTradeSize:    75000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   100,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.3,
+			OrderToTrade:    10,
 		},
 		{
-			UserID:         "HFT_002",
-			Type:           HFTTrader,
-			TypicalSymbols: []string{"TSLA", "NVDA", "META", "AMZN"},
-			AvgTradeSize:   100000,
-			Volatility:     0.3,
-			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15, 16},
-			TradesPerHour:  150,
-			FraudPattern:   NoFraud,
+			UserID:          "HFT_002",
+			Type:            HFTTrader,
+			TypicalSymbols:  []string{"TSLA", "NVDA", "META", "AMZN"},
+			AvgTradeSize:    100000,
+			Volatility:      0.3,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15, 16},
+			TradesPerHour:   150,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.3,
+			OrderToTrade:    10,
 		},
 		{
-			UserID:         "HFT_003",
-			Type:           HFTTrader,
-			TypicalSymbols: BlueChipSymbols,
-			AvgTradeSize:   50000,
-			Volatility:     0.2,
-			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15},
-			TradesPerHour:  80,
-			FraudPattern:   NoFraud,
+			UserID:          "HFT_003",
+			Type:            HFTTrader,
+			TypicalSymbols:  BlueChipSymbols,
+			AvgTradeSize:    50000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   80,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.3,
+			OrderToTrade:    10,
 		},
 
 		// Regular Traders (70% of users, 18% of volume)
 		{
-			UserID:         "USER_001",
-			Type:           RegularTrader,
-			TypicalSymbols: PopularSymbols[:4],
-			AvgTradeSize:   5000,
-			Volatility:     0.5,
-			ActiveHours:    []int{10, 14},
-			TradesPerHour:  2,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_001",
+			Type:            RegularTrader,
+			TypicalSymbols:  PopularSymbols[:4],
+			AvgTradeSize:    5000,
+			Volatility:      0.5,
+			ActiveHours:     []int{10, 14},
+			TradesPerHour:   2,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_002",
-			Type:           RegularTrader,
-			TypicalSymbols: []string{"AAPL", "MSFT", "GOOGL"},
-			AvgTradeSize:   7500,
-			Volatility:     0.4,
-			ActiveHours:    []int{9, 12, 15},
-			TradesPerHour:  3,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_002",
+			Type:            RegularTrader,
+			TypicalSymbols:  []string{"AAPL", "MSFT", "GOOGL"},
+			AvgTradeSize:    7500,
+			Volatility:      0.4,
+			ActiveHours:     []int{9, 12, 15},
+			TradesPerHour:   3,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_003",
-			Type:           RegularTrader,
-			TypicalSymbols: PopularSymbols,
-			AvgTradeSize:   4000,
-			Volatility:     0.6,
-			ActiveHours:    []int{11, 14},
-			TradesPerHour:  1,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_003",
+			Type:            RegularTrader,
+			TypicalSymbols:  PopularSymbols,
+			AvgTradeSize:    4000,
+			Volatility:      0.6,
+			ActiveHours:     []int{11, 14},
+			TradesPerHour:   1,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_004",
-			Type:           RegularTrader,
-			TypicalSymbols: []string{"TSLA", "NVDA", "AMD"},
-			AvgTradeSize:   6000,
-			Volatility:     0.5,
-			ActiveHours:    []int{10, 13},
-			TradesPerHour:  2,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_004",
+			Type:            RegularTrader,
+			TypicalSymbols:  []string{"TSLA", "NVDA", "AMD"},
+			AvgTradeSize:    6000,
+			Volatility:      0.5,
+			ActiveHours:     []int{10, 13},
+			TradesPerHour:   2,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_005",
-			Type:           RegularTrader,
-			TypicalSymbols: PopularSymbols[:3],
-			AvgTradeSize:   5500,
-			Volatility:     0.4,
-			ActiveHours:    []int{9, 14},
-			TradesPerHour:  2,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_005",
+			Type:            RegularTrader,
+			TypicalSymbols:  PopularSymbols[:3],
+			AvgTradeSize:    5500,
+			Volatility:      0.4,
+			ActiveHours:     []int{9, 14},
+			TradesPerHour:   2,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_006",
-			Type:           RegularTrader,
-			TypicalSymbols: BlueChipSymbols[:4],
-			AvgTradeSize:   8000,
-			Volatility:     0.3,
-			ActiveHours:    []int{10, 15},
-			TradesPerHour:  3,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_006",
+			Type:            RegularTrader,
+			TypicalSymbols:  BlueChipSymbols[:4],
+			AvgTradeSize:    8000,
+			Volatility:      0.3,
+			ActiveHours:     []int{10, 15},
+			TradesPerHour:   3,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "USER_007",
-			Type:           RegularTrader,
-			TypicalSymbols: PopularSymbols,
-			AvgTradeSize:   4500,
-			Volatility:     0.5,
-			ActiveHours:    []int{11, 14},
-			TradesPerHour:  1,
-			FraudPattern:   NoFraud,
+			UserID:          "USER_007",
+			Type:            RegularTrader,
+			TypicalSymbols:  PopularSymbols,
+			AvgTradeSize:    4500,
+			Volatility:      0.5,
+			ActiveHours:     []int{11, 14},
+			TradesPerHour:   1,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.7,
 		},
 
 		// Casual Traders (10% of users, 2% of volume)
 		{
-			UserID:         "CASUAL_001",
-			Type:           CasualTrader,
-			TypicalSymbols: ETFSymbols[:2],
+			UserID:          "CASUAL_001",
+			Type:            CasualTrader,
+			TypicalSymbols:  ETFSymbols[:2],
+			AvgTradeSize:    1000,
+			Volatility:      0.3,
+			ActiveHours:     []int{10},
+			TradesPerHour:   1,
+			FraudPattern:    NoFraud,
+			AggressiveRatio: 0.8,
+		},
+
+		// Market Makers (selected only when mm_ratio is set)
+		{
+			UserID:         "MM_001",
+			Type:           MarketMakerTrader,
+			TypicalSymbols: BlueChipSymbols[:4],
+			AvgTradeSize:   500,
+			Volatility:     0.2,
+			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:  200,
+			FraudPattern:   NoFraud,
+			TypicalRatio:   1,
+			QuoteSpread:    0.0005,
+			OrderToTrade:   20,
+		},
+		{
+			UserID:         "MM_002",
+			Type:           MarketMakerTrader,
+			TypicalSymbols: ETFSymbols[:3],
 			AvgTradeSize:   1000,
-			Volatility:     0.3,
-			ActiveHours:    []int{10},
-			TradesPerHour:  1,
+			Volatility:     0.2,
+			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:  200,
 			FraudPattern:   NoFraud,
+			TypicalRatio:   1,
+			QuoteSpread:    0.0002,
+			OrderToTrade:   20,
 		},
 
 		// Fraud Traders (for testing detection)
 		{
-			UserID:         "FRAUD_WASH_001",
-			Type:           FraudTrader,
-			TypicalSymbols: PennyStocks,
-			AvgTradeSize:   10000,
-			Volatility:     0.1,
-			ActiveHours:    []int{9, 10, 11, 12, 13, 14, 15},
-			TradesPerHour:  20,
-			FraudPattern:   WashTrade,
+			UserID:          "FRAUD_WASH_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PennyStocks,
+			AvgTradeSize:    10000,
+			Volatility:      0.1,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   20,
+			FraudPattern:    WashTrade,
+			AggressiveRatio: 0.6,
 		},
 		{
-			UserID:         "FRAUD_VELOCITY_001",
-			Type:           FraudTrader,
-			TypicalSymbols: PopularSymbols[:3],
-			AvgTradeSize:   5000,
-			Volatility:     0.2,
-			ActiveHours:    []int{14},
-			TradesPerHour:  5,
-			FraudPattern:   VelocitySpike,
+			UserID:          "FRAUD_VELOCITY_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols[:3],
+			AvgTradeSize:    5000,
+			Volatility:      0.2,
+			ActiveHours:     []int{14},
+			TradesPerHour:   5,
+			FraudPattern:    VelocitySpike,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_VELOCITY_MULTI_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  ETFSymbols[:1],
+			AvgTradeSize:    8000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 15},
+			TradesPerHour:   5,
+			FraudPattern:    VelocityMulti,
+			AggressiveRatio: 0.8,
+			RelatedSymbols:  []string{"SPY", "AAPL", "MSFT", "NVDA", "AMZN"}, // An index and its top components
+		},
+		{
+			UserID:          "FRAUD_ANOMALY_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  BlueChipSymbols[:3],
+			AvgTradeSize:    3000,
+			Volatility:      0.4,
+			ActiveHours:     []int{10, 14},
+			TradesPerHour:   2,
+			FraudPattern:    Anomaly,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_IMBALANCE_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols[:4],
+			AvgTradeSize:    4000,
+			Volatility:      0.3,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   10,
+			FraudPattern:    Imbalance,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_FRAG_WASH_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PennyStocks,
+			AvgTradeSize:    500,
+			Volatility:      0.1,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   20,
+			FraudPattern:    FragmentedWash,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_SPOOF_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  BlueChipSymbols[:4],
+			AvgTradeSize:    2000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   10,
+			FraudPattern:    SpoofPattern,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_PUMP_DUMP_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PennyStocks,
+			AvgTradeSize:    20000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   30,
+			FraudPattern:    PumpDump,
+			AggressiveRatio: 0.8,
+		},
+		{
+			UserID:          "FRAUD_COLLUSION_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PennyStocks,
+			AvgTradeSize:    8000,
+			Volatility:      0.1,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   20,
+			FraudPattern:    Collusion,
+			AggressiveRatio: 0.6,
+			LinkedUserIDs:   []string{"FRAUD_COLLUSION_002", "FRAUD_COLLUSION_003"},
+		},
+		{
+			UserID:          "FRAUD_QUOTE_STUFF_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  BlueChipSymbols[:2],
+			AvgTradeSize:    500,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   100,
+			FraudPattern:    QuoteStuffing,
+			AggressiveRatio: 0.6,
+		},
+		{
+			UserID:          "FRAUD_MOMENTUM_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols[:4],
+			AvgTradeSize:    3000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   10,
+			FraudPattern:    Momentum,
+			AggressiveRatio: 0.9,
+		},
+		{
+			UserID:          "FRAUD_FRONT_RUN_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  BlueChipSymbols,
+			AvgTradeSize:    2000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   10,
+			FraudPattern:    FrontRunning,
+			AggressiveRatio: 0.9,
+		},
+		{
+			UserID:          "FRAUD_COMBO_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols[:3],
+			AvgTradeSize:    4000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   10,
+			FraudPattern:    Combo,
+			AggressiveRatio: 0.7,
 		},
 		{
-			UserID:         "FRAUD_ANOMALY_001",
-			Type:           FraudTrader,
-			TypicalSymbols: BlueChipSymbols[:3],
-			AvgTradeSize:   3000,
-			Volatility:     0.4,
-			ActiveHours:    []int{10, 14},
-			TradesPerHour:  2,
-			FraudPattern:   Anomaly,
+			UserID:          "FRAUD_RING_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PennyStocks,
+			AvgTradeSize:    6000,
+			Volatility:      0.1,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   20,
+			FraudPattern:    TradingRing,
+			AggressiveRatio: 0.5,
+			LinkedUserIDs:   []string{"FRAUD_RING_002", "FRAUD_RING_003", "FRAUD_RING_004", "FRAUD_RING_005", "FRAUD_RING_006"},
+		},
+		{
+			UserID:          "FRAUD_FAT_FINGER_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols,
+			AvgTradeSize:    1000,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   5,
+			FraudPattern:    FatFinger,
+			AggressiveRatio: 0.8,
+		},
+		{
+			UserID:          "FRAUD_INSIDER_001",
+			Type:            FraudTrader,
+			TypicalSymbols:  PopularSymbols[:4],
+			AvgTradeSize:    800,
+			Volatility:      0.2,
+			ActiveHours:     []int{9, 10, 11, 12, 13, 14, 15},
+			TradesPerHour:   5,
+			FraudPattern:    Insider,
+			AggressiveRatio: 0.3,
 		},
 	}
 }
 
 // SelectProfile selects a random profile based on weighted distribution
-func SelectProfile(profiles []TraderProfile, hftRatio, regularRatio, casualRatio float64) *TraderProfile {
-	r := rand.Float64()
+func SelectProfile(rng *rand.Rand, profiles []TraderProfile, hftRatio, regularRatio, mmRatio, casualRatio float64) *TraderProfile {
+	r := rng.Float64()
 
 	// Separate profiles by type
-	var hftProfiles, regularProfiles, casualProfiles, fraudProfiles []TraderProfile
+	var hftProfiles, regularProfiles, mmProfiles, casualProfiles, fraudProfiles []TraderProfile
 	for i := range profiles {
 		switch profiles[i].Type {
 		case HFTTrader:
 			hftProfiles = append(hftProfiles, profiles[i])
 		case RegularTrader:
 			regularProfiles = append(regularProfiles, profiles[i])
+		case MarketMakerTrader:
+			mmProfiles = append(mmProfiles, profiles[i])
 		case CasualTrader:
 			casualProfiles = append(casualProfiles, profiles[i])
 		case FraudTrader:
@@ -221,68 +508,245 @@ func SelectProfile(profiles []TraderProfile, hftRatio, regularRatio, casualRatio
 	// Select based on ratio
 	if r < hftRatio {
 		if len(hftProfiles) > 0 {
-			profile := hftProfiles[rand.Intn(len(hftProfiles))]
+			profile := hftProfiles[rng.Intn(len(hftProfiles))]
 			return &profile
 		}
 	} else if r < hftRatio+regularRatio {
 		if len(regularProfiles) > 0 {
-			profile := regularProfiles[rand.Intn(len(regularProfiles))]
+			profile := regularProfiles[rng.Intn(len(regularProfiles))]
+			return &profile
+		}
+	} else if r < hftRatio+regularRatio+mmRatio {
+		if len(mmProfiles) > 0 {
+			profile := mmProfiles[rng.Intn(len(mmProfiles))]
 			return &profile
 		}
 	} else {
 		if len(casualProfiles) > 0 {
-			profile := casualProfiles[rand.Intn(len(casualProfiles))]
+			profile := casualProfiles[rng.Intn(len(casualProfiles))]
 			return &profile
 		}
 	}
 
 	// Fallback
 	if len(profiles) > 0 {
-		profile := profiles[rand.Intn(len(profiles))]
+		profile := profiles[rng.Intn(len(profiles))]
 		return &profile
 	}
 	return nil
 }
 
 // SelectFraudProfile selects a random fraud profile
-func SelectFraudProfile(profiles []TraderProfile, fraudType FraudType) *TraderProfile {
+func SelectFraudProfile(rng *rand.Rand, profiles []TraderProfile, fraudType FraudType) *TraderProfile {
+	fraudProfiles := FilterFraudProfiles(profiles, fraudType)
+
+	if len(fraudProfiles) > 0 {
+		profile := fraudProfiles[rng.Intn(len(fraudProfiles))]
+		return &profile
+	}
+	return nil
+}
+
+// FilterFraudProfiles returns the fraud profiles using the given fraud type
+func FilterFraudProfiles(profiles []TraderProfile, fraudType FraudType) []TraderProfile {
 	var fraudProfiles []TraderProfile
 	for i := range profiles {
 		if profiles[i].Type == FraudTrader {
-			if fraudType == AllFraud || profiles[i].FraudPattern == fraudType {
+			if fraudType == AllFraud || profiles[i].UsesFraudType(fraudType) {
 				fraudProfiles = append(fraudProfiles, profiles[i])
 			}
 		}
 	}
+	return fraudProfiles
+}
 
-	if len(fraudProfiles) > 0 {
-		profile := fraudProfiles[rand.Intn(len(fraudProfiles))]
-		return &profile
+// SelectVictimProfile pairs a fraud profile with a normal profile to trade
+// ahead of, preferring traders of the fraud profile's symbols. It returns nil
+// if there are no normal profiles.
+func SelectVictimProfile(rng *rand.Rand, profiles []TraderProfile, fraud *TraderProfile) *TraderProfile {
+	var normal, overlapping []TraderProfile
+	for i := range profiles {
+		if profiles[i].Type == FraudTrader {
+			continue
+		}
+		normal = append(normal, profiles[i])
+		if sharesSymbol(profiles[i].TypicalSymbols, fraud.TypicalSymbols) {
+			overlapping = append(overlapping, profiles[i])
+		}
 	}
-	return nil
+
+	if len(overlapping) > 0 {
+		normal = overlapping
+	}
+	if len(normal) == 0 {
+		return nil
+	}
+	victim := normal[rng.Intn(len(normal))]
+	return &victim
+}
+
+// sharesSymbol reports whether two symbol lists have a symbol in common
+func sharesSymbol(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CountNormalProfiles returns the number of non-fraud profiles
+func CountNormalProfiles(profiles []TraderProfile) int {
+	count := 0
+	for i := range profiles {
+		if profiles[i].Type != FraudTrader {
+			count++
+		}
+	}
+	return count
+}
+
+// IsActiveNow checks if the trader is active on the clock's current day and
+// hour in the given timezone
+func (p *TraderProfile) IsActiveNow(c clock.Clock, location *time.Location) bool {
+	local := c.Now().In(location)
+	return p.IsActiveOn(local.Weekday()) && p.IsActiveAt(local.Hour())
 }
 
-// IsActiveNow checks if the trader is active at the current hour
-func (p *TraderProfile) IsActiveNow() bool {
-	currentHour := time.Now().Hour()
-	for _, hour := range p.ActiveHours {
-		if hour == currentHour {
+// IsActiveOn checks if the trader is active on the given day of the week,
+// defaulting to Monday-Friday when no active days are set
+func (p *TraderProfile) IsActiveOn(day time.Weekday) bool {
+	if len(p.ActiveDays) == 0 {
+		return day != time.Saturday && day != time.Sunday
+	}
+	return slices.Contains(p.ActiveDays, day)
+}
+
+// IsActiveAt checks if the trader is active during the given hour (0-23)
+func (p *TraderProfile) IsActiveAt(hour int) bool {
+	for _, active := range p.ActiveHours {
+		if active == hour {
 			return true
 		}
 	}
 	return false
 }
 
-// GetRandomSymbol returns a random symbol from the trader's typical symbols
-func (p *TraderProfile) GetRandomSymbol() string {
+// GetAggressiveRatio returns the fraction of the trader's trades that cross the spread
+func (p *TraderProfile) GetAggressiveRatio() float64 {
+	if p.AggressiveRatio == 0 {
+		return 0.5
+	}
+	return p.AggressiveRatio
+}
+
+// GetQuoteSpread returns a market maker's quoted spread as a fraction of the mid
+func (p *TraderProfile) GetQuoteSpread() float64 {
+	if p.QuoteSpread == 0 {
+		return 0.0005
+	}
+	return p.QuoteSpread
+}
+
+// GetOrderToTrade returns how many orders the trader places per execution
+func (p *TraderProfile) GetOrderToTrade() float64 {
+	if p.OrderToTrade == 0 {
+		return 1
+	}
+	return p.OrderToTrade
+}
+
+// GetTypicalRatio returns the fraction of the trader's trades in its typical symbols
+func (p *TraderProfile) GetTypicalRatio() float64 {
+	if p.TypicalRatio == 0 {
+		return 0.8
+	}
+	return p.TypicalRatio
+}
+
+// weightedSymbol draws a symbol from SymbolWeights. Symbols are visited in
+// sorted order so seeded runs repeat despite map iteration order.
+func (p *TraderProfile) weightedSymbol(rng *rand.Rand) string {
+	symbols := make([]string, 0, len(p.SymbolWeights))
+	total := 0.0
+	for symbol, weight := range p.SymbolWeights {
+		symbols = append(symbols, symbol)
+		total += weight
+	}
+	sort.Strings(symbols)
+
+	r := rng.Float64() * total
+	for _, symbol := range symbols {
+		r -= p.SymbolWeights[symbol]
+		if r < 0 {
+			return symbol
+		}
+	}
+	return symbols[len(symbols)-1]
+}
+
+// UsesFraudType reports whether the trader uses a fraud pattern, either as its
+// only pattern or with a positive weight in its mix
+func (p *TraderProfile) UsesFraudType(fraudType FraudType) bool {
+	if len(p.FraudMix) == 0 {
+		return p.FraudPattern == fraudType
+	}
+	return p.FraudMix[fraudType] > 0
+}
+
+// DrawFraudPattern draws the pattern for one fraud tick from FraudMix, or
+// returns FraudPattern when there is no mix. Patterns are visited in sorted
+// order so seeded runs repeat despite map iteration order.
+func (p *TraderProfile) DrawFraudPattern(rng *rand.Rand) FraudType {
+	if len(p.FraudMix) == 0 {
+		return p.FraudPattern
+	}
+
+	patterns := make([]FraudType, 0, len(p.FraudMix))
+	total := 0.0
+	for pattern, weight := range p.FraudMix {
+		patterns = append(patterns, pattern)
+		total += weight
+	}
+	slices.Sort(patterns)
+
+	r := rng.Float64() * total
+	for _, pattern := range patterns {
+		r -= p.FraudMix[pattern]
+		if r < 0 {
+			return pattern
+		}
+	}
+	return patterns[len(patterns)-1]
+}
+
+// GetBuyRatio returns the fraction of the trader's trades that are buys
+func (p *TraderProfile) GetBuyRatio() float64 {
+	if p.BuyRatio == 0 {
+		return 0.5
+	}
+	return p.BuyRatio
+}
+
+// GetRandomSymbol returns a random symbol from the trader's typical symbols,
+// weighted by SymbolWeights when set
+func (p *TraderProfile) GetRandomSymbol(rng *rand.Rand) string {
 	if len(p.TypicalSymbols) == 0 {
 		return "AAPL"
 	}
-	// 80% of the time, use typical symbols
-	if rand.Float64() < 0.8 {
-		return p.TypicalSymbols[rand.Intn(len(p.TypicalSymbols))]
+	// Most of the time, use typical symbols
+	if rng.Float64() < p.GetTypicalRatio() {
+		if len(p.SymbolWeights) > 0 {
+			return p.weightedSymbol(rng)
+		}
+		return p.TypicalSymbols[rng.Intn(len(p.TypicalSymbols))]
 	}
 	// 20% exploration of other symbols
+	if len(p.Universe) > 0 {
+		return p.Universe[rng.Intn(len(p.Universe))]
+	}
 	allSymbols := append(append(append([]string{}, BlueChipSymbols...), PopularSymbols...), ETFSymbols...)
-	return allSymbols[rand.Intn(len(allSymbols))]
+	return allSymbols[rng.Intn(len(allSymbols))]
 }
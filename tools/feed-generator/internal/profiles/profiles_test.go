@@ -0,0 +1,83 @@
+package profiles
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/clock"
+)
+
+func TestIsActiveNowInPinnedZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	profile := &TraderProfile{UserID: "RETAIL_001", ActiveHours: []int{9, 10, 11, 12, 13, 14, 15}}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		location *time.Location
+		want     bool
+	}{
+		// 14:30 UTC on a Monday is 9:30 in New York and 23:30 in Tokyo
+		{"monday morning in new york", time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC), newYork, true},
+		{"monday night in tokyo", time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC), tokyo, false},
+		{"monday afternoon in utc", time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC), time.UTC, true},
+		{"after the last active hour", time.Date(2026, 3, 2, 16, 0, 0, 0, newYork), newYork, false},
+		// 02:00 UTC on a Saturday is still Friday evening in New York
+		{"friday evening in new york", time.Date(2026, 3, 7, 2, 0, 0, 0, time.UTC), newYork, false},
+		{"saturday morning in tokyo", time.Date(2026, 3, 7, 1, 0, 0, 0, time.UTC), tokyo, false},
+		{"friday afternoon in new york", time.Date(2026, 3, 6, 15, 59, 0, 0, newYork), newYork, true},
+	}
+	for _, tt := range tests {
+		if got := profile.IsActiveNow(clock.NewFake(tt.now), tt.location); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWeightedSymbolDominates(t *testing.T) {
+	const n = 20000
+	profile := &TraderProfile{
+		TypicalSymbols: []string{"AAPL", "MSFT", "TSLA"},
+		SymbolWeights:  map[string]float64{"AAPL": 8, "MSFT": 1, "TSLA": 1},
+		TypicalRatio:   1,
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		counts[profile.GetRandomSymbol(rng)]++
+	}
+	for symbol, want := range map[string]float64{"AAPL": 0.8, "MSFT": 0.1, "TSLA": 0.1} {
+		if got := float64(counts[symbol]) / n; math.Abs(got-want) > 0.02 {
+			t.Errorf("%s: drawn %.3f of the time, want about %.1f", symbol, got, want)
+		}
+	}
+}
+
+func TestFraudMixDrawsFollowWeights(t *testing.T) {
+	const n = 20000
+	profile := &TraderProfile{
+		FraudPattern: WashTrade,
+		FraudMix:     map[FraudType]float64{WashTrade: 5, VelocitySpike: 3, SpoofPattern: 2, PumpDump: 0},
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	counts := make(map[FraudType]int)
+	for i := 0; i < n; i++ {
+		counts[profile.DrawFraudPattern(rng)]++
+	}
+	for pattern, want := range map[FraudType]float64{WashTrade: 0.5, VelocitySpike: 0.3, SpoofPattern: 0.2, PumpDump: 0} {
+		if got := float64(counts[pattern]) / n; math.Abs(got-want) > 0.02 {
+			t.Errorf("%s: drawn %.3f of the time, want about %.1f", pattern, got, want)
+		}
+	}
+}
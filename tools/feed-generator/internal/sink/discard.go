@@ -0,0 +1,31 @@
+package sink
+
+import (
+	"context"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// Discard accepts trades, labels and halt markers and drops them, for dry runs that
+// exercise generation without any output
+type Discard struct{}
+
+func (Discard) Publish(ctx context.Context, trade *feed.Trade) error {
+	return nil
+}
+
+func (Discard) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	return nil
+}
+
+func (Discard) PublishLabels(ctx context.Context, labels []*feed.Label) error {
+	return nil
+}
+
+func (Discard) PublishHalt(ctx context.Context, halt *feed.Halt) error {
+	return nil
+}
+
+func (Discard) Close() error {
+	return nil
+}
@@ -0,0 +1,213 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// Output file formats
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+// csvHeader lists the CSV columns in order
+var csvHeader = []string{
+	"trade_id", "user_id", "symbol", "amount", "price", "trade_type",
+	"timestamp", "liquidity", "conditions", "trader_type", "cancelled", "seq",
+	"sector", "asset_class",
+}
+
+// NewFileSink creates a file sink for the given format
+func NewFileSink(path, format string) (Sink, error) {
+	switch format {
+	case FormatNDJSON:
+		return NewNDJSONSink(path)
+	case FormatCSV:
+		return NewCSVSink(path)
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// NDJSONSink writes one JSON-encoded trade per line
+type NDJSONSink struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewNDJSONSink creates (or truncates) an NDJSON output file
+func NewNDJSONSink(path string) (*NDJSONSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	return &NDJSONSink{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+// Publish writes a trade as one JSON line
+func (s *NDJSONSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	if err := s.encoder.Encode(trade); err != nil {
+		return fmt.Errorf("failed to write trade: %w", err)
+	}
+	return nil
+}
+
+// PublishBatch writes trades as consecutive JSON lines
+func (s *NDJSONSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	for _, trade := range trades {
+		if err := s.Publish(ctx, trade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered lines and closes the file
+func (s *NDJSONSink) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("failed to flush output file: %w", err)
+	}
+	return s.file.Close()
+}
+
+// LabelFile writes one JSON-encoded label per line. It is safe for concurrent
+// use, so publisher workers can share it.
+type LabelFile struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewLabelFile creates (or truncates) an NDJSON label file
+func NewLabelFile(path string) (*LabelFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create labels file: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	return &LabelFile{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}, nil
+}
+
+// PublishLabels writes labels as consecutive JSON lines
+func (s *LabelFile) PublishLabels(ctx context.Context, labels []*feed.Label) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, label := range labels {
+		if err := s.encoder.Encode(label); err != nil {
+			return fmt.Errorf("failed to write label: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered labels and closes the file
+func (s *LabelFile) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("failed to flush labels file: %w", err)
+	}
+	return s.file.Close()
+}
+
+// CSVSink writes a header row followed by one row per trade
+type CSVSink struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// NewCSVSink creates (or truncates) a CSV output file and writes the header
+func NewCSVSink(path string) (*CSVSink, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(csvHeader); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	return &CSVSink{file: file, writer: writer}, nil
+}
+
+// Publish writes a trade as one CSV row
+func (s *CSVSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	if err := s.writer.Write(csvRecord(trade)); err != nil {
+		return fmt.Errorf("failed to write trade: %w", err)
+	}
+	return nil
+}
+
+// PublishBatch writes trades as consecutive CSV rows
+func (s *CSVSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	for _, trade := range trades {
+		if err := s.Publish(ctx, trade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered rows and closes the file
+func (s *CSVSink) Close() error {
+	s.writer.Flush()
+	if err := s.writer.Error(); err != nil {
+		s.file.Close()
+		return fmt.Errorf("failed to flush output file: %w", err)
+	}
+	return s.file.Close()
+}
+
+// csvRecord builds the CSV row for a trade, matching csvHeader
+func csvRecord(trade *feed.Trade) []string {
+	return []string{
+		trade.ID.String(),
+		trade.UserID,
+		trade.Symbol,
+		strconv.FormatFloat(trade.Amount, 'f', -1, 64),
+		strconv.FormatFloat(trade.Price, 'f', -1, 64),
+		string(trade.Type),
+		trade.Timestamp.Format(time.RFC3339Nano),
+		string(trade.Liquidity),
+		joinConditions(trade.Conditions),
+		trade.TraderType,
+		strconv.FormatBool(trade.Cancelled),
+		formatSeq(trade.Seq),
+		trade.Sector,
+		trade.AssetClass,
+	}
+}
+
+// formatSeq formats a sequence number, leaving the column empty when unset
+func formatSeq(seq uint64) string {
+	if seq == 0 {
+		return ""
+	}
+	return strconv.FormatUint(seq, 10)
+}
@@ -0,0 +1,108 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink produces generated trades to a Kafka topic. Messages are keyed by
+// user ID, so each account's trades land on one partition in order.
+type KafkaSink struct {
+	writer  *kafka.Writer
+	brokers []string
+	topic   string
+}
+
+// NewKafkaSink creates a Kafka sink producing to the configured topic
+func NewKafkaSink(cfg config.KafkaConfig) (*KafkaSink, error) {
+	acks, err := requiredAcks(cfg.RequiredAcks)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: acks,
+		// Publishes are synchronous, so don't hold a message back waiting for a fuller batch
+		BatchTimeout: time.Millisecond,
+	}
+
+	return &KafkaSink{writer: writer, brokers: cfg.Brokers, topic: cfg.Topic}, nil
+}
+
+// requiredAcks maps the configured acknowledgement level to the client's
+func requiredAcks(acks string) (kafka.RequiredAcks, error) {
+	switch acks {
+	case "none":
+		return kafka.RequireNone, nil
+	case "one":
+		return kafka.RequireOne, nil
+	case "all":
+		return kafka.RequireAll, nil
+	default:
+		return 0, fmt.Errorf("kafka required acks must be none, one or all, got %q", acks)
+	}
+}
+
+// Ping checks the first broker is reachable and the topic exists
+func (s *KafkaSink) Ping(ctx context.Context) error {
+	conn, err := kafka.DialContext(ctx, "tcp", s.brokers[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ReadPartitions(s.topic); err != nil {
+		return fmt.Errorf("topic %s: %w", s.topic, err)
+	}
+	return nil
+}
+
+// Publish produces a single trade
+func (s *KafkaSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	return s.PublishBatch(ctx, []*feed.Trade{trade})
+}
+
+// PublishBatch produces trades in one request. Messages for different users
+// may go to different partitions, so a partial failure is reported as a
+// *BatchError counting only the leading messages that were acknowledged; a
+// retry may re-send later messages that had already succeeded.
+func (s *KafkaSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	messages := make([]kafka.Message, len(trades))
+	for i, trade := range trades {
+		data, err := json.Marshal(trade)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trade: %w", err)
+		}
+		messages[i] = kafka.Message{
+			Key:   []byte(trade.UserID),
+			Value: data,
+			Time:  trade.Timestamp,
+		}
+	}
+
+	err := s.writer.WriteMessages(ctx, messages...)
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) {
+		for i, msgErr := range writeErrs {
+			if msgErr != nil {
+				return &BatchError{Published: i, Err: msgErr}
+			}
+		}
+	}
+	return err
+}
+
+// Close flushes pending messages and closes the producer
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,167 @@
+//go:build integration
+
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// Run against a real broker with:
+//
+//	KAFKA_BROKERS=localhost:9092 go test -tags integration ./internal/sink/ -run Kafka
+
+// kafkaTestTopic creates a fresh multi-partition topic on the broker named by
+// KAFKA_BROKERS, skipping the test when none is reachable
+func kafkaTestTopic(t *testing.T, partitions int) (string, string) {
+	t.Helper()
+	broker := os.Getenv("KAFKA_BROKERS")
+	if broker == "" {
+		broker = "localhost:9092"
+	}
+	conn, err := kafka.Dial("tcp", broker)
+	if err != nil {
+		t.Skipf("no Kafka broker at %s: %v", broker, err)
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		t.Fatal(err)
+	}
+	controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer controllerConn.Close()
+
+	topic := fmt.Sprintf("feed-generator-test-%d", time.Now().UnixNano())
+	if err := controllerConn.CreateTopics(kafka.TopicConfig{Topic: topic, NumPartitions: partitions, ReplicationFactor: 1}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { controllerConn.DeleteTopics(topic) })
+	return broker, topic
+}
+
+// readPartitions returns every message on the topic's partitions, each
+// partition in offset order
+func readPartitions(t *testing.T, broker, topic string, partitions int) [][]kafka.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	read := make([][]kafka.Message, partitions)
+	for partition := 0; partition < partitions; partition++ {
+		conn, err := kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last, err := conn.ReadLastOffset()
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last == 0 {
+			continue
+		}
+
+		reader := kafka.NewReader(kafka.ReaderConfig{Brokers: []string{broker}, Topic: topic, Partition: partition})
+		for int64(len(read[partition])) < last {
+			message, err := reader.ReadMessage(ctx)
+			if err != nil {
+				reader.Close()
+				t.Fatalf("partition %d: %v", partition, err)
+			}
+			read[partition] = append(read[partition], message)
+		}
+		reader.Close()
+	}
+	return read
+}
+
+func TestKafkaSinkKeysByUserInOrder(t *testing.T) {
+	const partitions = 4
+	broker, topic := kafkaTestTopic(t, partitions)
+	s, err := NewKafkaSink(config.KafkaConfig{Brokers: []string{broker}, Topic: topic, RequiredAcks: "all"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Several users' trades interleaved, a millisecond apart
+	users := []string{"USER_001", "USER_002", "USER_003", "USER_004", "USER_005"}
+	start := time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC)
+	trades := make([]*feed.Trade, 100)
+	published := make(map[string]*feed.Trade)
+	for i := range trades {
+		trades[i] = &feed.Trade{Trade: &models.Trade{
+			ID:        uuid.New(),
+			UserID:    users[i%len(users)],
+			Symbol:    "AAPL",
+			Amount:    float64(100 + i),
+			Price:     175.5,
+			Type:      models.TradeTypeBuy,
+			Timestamp: start.Add(time.Duration(i) * time.Millisecond),
+		}}
+		published[trades[i].ID.String()] = trades[i]
+	}
+	for _, batch := range [][]*feed.Trade{trades[:40], trades[40:]} {
+		if err := s.PublishBatch(ctx, batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	userPartition := make(map[string]int)
+	lastSeen := make(map[string]time.Time)
+	received := 0
+	for partition, messages := range readPartitions(t, broker, topic, partitions) {
+		for _, message := range messages {
+			var trade feed.Trade
+			if err := json.Unmarshal(message.Value, &trade); err != nil {
+				t.Fatal(err)
+			}
+			want, ok := published[trade.ID.String()]
+			if !ok {
+				t.Fatalf("partition %d: unexpected trade %s", partition, trade.ID)
+			}
+			received++
+
+			if string(message.Key) != want.UserID {
+				t.Errorf("trade %s keyed %q, want its user %s", trade.ID, message.Key, want.UserID)
+			}
+			if !message.Time.Equal(want.Timestamp) {
+				t.Errorf("trade %s message time %v, want the trade timestamp %v", trade.ID, message.Time, want.Timestamp)
+			}
+
+			// One partition per user, and a user's trades in publishing order
+			if p, seen := userPartition[want.UserID]; seen && p != partition {
+				t.Errorf("%s split across partitions %d and %d", want.UserID, p, partition)
+			}
+			userPartition[want.UserID] = partition
+			if !want.Timestamp.After(lastSeen[want.UserID]) {
+				t.Errorf("%s trade at %v arrived after one at %v", want.UserID, want.Timestamp, lastSeen[want.UserID])
+			}
+			lastSeen[want.UserID] = want.Timestamp
+		}
+	}
+	if received != len(trades) {
+		t.Errorf("read %d messages, want %d", received, len(trades))
+	}
+}
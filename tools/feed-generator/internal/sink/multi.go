@@ -0,0 +1,140 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// MultiSink fans trades out to several sinks, in order. Best-effort delivery
+// publishes to every sink and joins their errors; fail-fast stops at the first
+// sink that fails, so later sinks never see the batch.
+//
+// A failed batch reports the fewest trades any sink accepted, so a retry
+// re-sends trades to the sinks that already took them: like Kafka, fan-out is
+// at-least-once for every sink but the slowest.
+type MultiSink struct {
+	sinks    []Sink
+	failFast bool
+}
+
+// NewMultiSink creates a sink publishing to each of sinks
+func NewMultiSink(failFast bool, sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks, failFast: failFast}
+}
+
+// Sinks returns the underlying sinks, in publishing order
+func (m *MultiSink) Sinks() []Sink {
+	return m.sinks
+}
+
+func (m *MultiSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	var errs []error
+	for i, s := range m.sinks {
+		if err := s.Publish(ctx, trade); err != nil {
+			errs = append(errs, fmt.Errorf("output %d: %w", i+1, err))
+			if m.failFast {
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	var errs []error
+	published := len(trades)
+	for i, s := range m.sinks {
+		err := s.PublishBatch(ctx, trades)
+		if err == nil {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("output %d: %w", i+1, err))
+
+		accepted := 0
+		var batchErr *BatchError
+		if errors.As(err, &batchErr) {
+			accepted = batchErr.Published
+		}
+		published = min(published, accepted)
+		if m.failFast {
+			// Sinks after this one got nothing
+			if i < len(m.sinks)-1 {
+				published = 0
+			}
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &BatchError{Published: published, Err: errors.Join(errs...)}
+}
+
+// EnsureConsumerGroup creates the group on every sink that supports consumer
+// groups
+func (m *MultiSink) EnsureConsumerGroup(ctx context.Context, stream, group string) error {
+	for _, s := range m.sinks {
+		if creator, ok := s.(ConsumerGroupCreator); ok {
+			if err := creator.EnsureConsumerGroup(ctx, stream, group); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// StreamLength reports the length of the first sink that can report one
+func (m *MultiSink) StreamLength(ctx context.Context) (int64, error) {
+	for _, s := range m.sinks {
+		if reader, ok := s.(StreamLengthReader); ok {
+			return reader.StreamLength(ctx)
+		}
+	}
+	return 0, fmt.Errorf("no output reports a stream length")
+}
+
+// StreamLag reports the lag of the first sink that can report one
+func (m *MultiSink) StreamLag(ctx context.Context, stream, group string) (int64, error) {
+	for _, s := range m.sinks {
+		if reader, ok := s.(StreamLagReader); ok {
+			return reader.StreamLag(ctx, stream, group)
+		}
+	}
+	return 0, fmt.Errorf("no output reports consumer group lag")
+}
+
+// PublishHalt publishes the marker to every sink that can carry halt markers
+func (m *MultiSink) PublishHalt(ctx context.Context, halt *feed.Halt) error {
+	var errs []error
+	for i, s := range m.sinks {
+		if publisher, ok := s.(HaltPublisher); ok {
+			if err := publisher.PublishHalt(ctx, halt); err != nil {
+				errs = append(errs, fmt.Errorf("output %d: %w", i+1, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OnDrop registers fn with every sink that can drop accepted trades
+func (m *MultiSink) OnDrop(fn func(n int)) {
+	for _, s := range m.sinks {
+		if reporter, ok := s.(DropReporter); ok {
+			reporter.OnDrop(fn)
+		}
+	}
+}
+
+// Close closes every sink, even after one fails
+func (m *MultiSink) Close() error {
+	var errs []error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
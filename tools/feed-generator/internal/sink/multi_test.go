@@ -0,0 +1,76 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// fakeSink records the batches it accepts, or fails every publish with err
+type fakeSink struct {
+	Discard
+	err     error
+	batches [][]*feed.Trade
+}
+
+func (s *fakeSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, trades)
+	return nil
+}
+
+func TestMultiSinkBatchReachesEverySink(t *testing.T) {
+	sinks := []*fakeSink{{}, {}, {}}
+	m := NewMultiSink(false, sinks[0], sinks[1], sinks[2])
+	trades := testTrades(5)
+
+	if err := m.PublishBatch(context.Background(), trades); err != nil {
+		t.Fatal(err)
+	}
+	for i, s := range sinks {
+		if len(s.batches) != 1 || len(s.batches[0]) != len(trades) {
+			t.Errorf("sink %d got batches %v, want the one batch of %d trades", i+1, s.batches, len(trades))
+		}
+	}
+}
+
+func TestMultiSinkBestEffortSkipsFailingSink(t *testing.T) {
+	refused := errors.New("connection refused")
+	sinks := []*fakeSink{{}, {err: refused}, {}}
+	m := NewMultiSink(false, sinks[0], sinks[1], sinks[2])
+
+	err := m.PublishBatch(context.Background(), testTrades(5))
+	if !errors.Is(err, refused) {
+		t.Fatalf("got error %v, want the failing sink's error", err)
+	}
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Published != 0 {
+		t.Errorf("got %v, want a batch error reporting the failing sink's 0 trades", err)
+	}
+	for _, i := range []int{0, 2} {
+		if len(sinks[i].batches) != 1 {
+			t.Errorf("sink %d got %d batches, want 1 despite sink 2 failing", i+1, len(sinks[i].batches))
+		}
+	}
+}
+
+func TestMultiSinkFailFastStopsAtFirstFailure(t *testing.T) {
+	refused := errors.New("connection refused")
+	sinks := []*fakeSink{{}, {err: refused}, {}}
+	m := NewMultiSink(true, sinks[0], sinks[1], sinks[2])
+
+	err := m.PublishBatch(context.Background(), testTrades(5))
+	if !errors.Is(err, refused) {
+		t.Fatalf("got error %v, want the failing sink's error", err)
+	}
+	if len(sinks[0].batches) != 1 {
+		t.Errorf("sink 1 got %d batches, want 1 before the failure", len(sinks[0].batches))
+	}
+	if len(sinks[2].batches) != 0 {
+		t.Errorf("sink 3 got %d batches after sink 2 failed, want none", len(sinks[2].batches))
+	}
+}
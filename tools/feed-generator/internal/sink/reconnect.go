@@ -0,0 +1,197 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+)
+
+const (
+	// Delays between reconnection attempts, doubling from the first to the cap
+	reconnectBackoff    = 100 * time.Millisecond
+	maxReconnectBackoff = 5 * time.Second
+
+	// reconnectPingTimeout bounds each reconnection attempt
+	reconnectPingTimeout = 2 * time.Second
+)
+
+// reconnector keeps a sink publishing through a lost connection. When a
+// publish fails at the connection level it marks the sink down and buffers
+// trades, up to a limit, while a background loop pings with backoff. Once a
+// ping succeeds the buffer is replayed in order and publishing resumes.
+type reconnector struct {
+	publish func(ctx context.Context, trades []*feed.Trade) error // Publishes straight to the connection
+	ping    func(ctx context.Context) error
+	limit   int
+
+	mu      sync.Mutex
+	down    bool
+	buffer  []*feed.Trade
+	onDrop  func(n int)
+	cancel  context.CancelFunc // Stops the reconnection loop, nil while connected
+	stopped chan struct{}
+}
+
+// newReconnector wraps a publish function, buffering at most limit trades
+// while the connection is down
+func newReconnector(publish func(context.Context, []*feed.Trade) error, ping func(context.Context) error, limit int) *reconnector {
+	return &reconnector{publish: publish, ping: ping, limit: limit}
+}
+
+// publishBatch publishes trades, or buffers them while the connection is
+// down. Trades that overflow the buffer are dropped and reported to onDrop;
+// errors other than connection loss are returned as usual.
+func (r *reconnector) publishBatch(ctx context.Context, trades []*feed.Trade) error {
+	r.mu.Lock()
+	if r.down {
+		r.bufferLocked(trades)
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+
+	err := r.publish(ctx, trades)
+	if err == nil || !isConnectionError(err) {
+		return err
+	}
+
+	var batchErr *BatchError
+	if errors.As(err, &batchErr) {
+		trades = trades[batchErr.Published:]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bufferLocked(trades)
+	if !r.down {
+		r.down = true
+		slog.Warn("connection lost, buffering trades", "error", err, "buffer_limit", r.limit,
+			logging.Text("⚠️  Warning: connection lost (%v), buffering up to %d trades while reconnecting", err, r.limit))
+
+		loopCtx, cancel := context.WithCancel(context.Background())
+		r.cancel = cancel
+		r.stopped = make(chan struct{})
+		go r.reconnect(loopCtx, r.stopped)
+	}
+	return nil
+}
+
+// bufferLocked appends trades to the outage buffer, dropping those beyond the
+// limit. r.mu must be held.
+func (r *reconnector) bufferLocked(trades []*feed.Trade) {
+	room := max(r.limit-len(r.buffer), 0)
+	kept := min(room, len(trades))
+	r.buffer = append(r.buffer, trades[:kept]...)
+	if dropped := len(trades) - kept; dropped > 0 && r.onDrop != nil {
+		r.onDrop(dropped)
+	}
+}
+
+// reconnect pings until the connection is back, then replays the buffer. If
+// the replay fails the unpublished trades go back to the front of the buffer
+// and the loop carries on.
+func (r *reconnector) reconnect(ctx context.Context, stopped chan struct{}) {
+	defer close(stopped)
+
+	backoff := reconnectBackoff
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+
+		pingCtx, cancel := context.WithTimeout(ctx, reconnectPingTimeout)
+		err := r.ping(pingCtx)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		replayed, err := r.replay(ctx)
+		if err != nil {
+			continue
+		}
+		slog.Info("connection restored", "replayed", replayed,
+			logging.Text("✅ Connection restored, published %d buffered trades", replayed))
+		return
+	}
+}
+
+// replay publishes the buffer until it is empty, then marks the connection up
+// again. Trades buffered meanwhile are picked up by the next pass, so order is
+// preserved.
+func (r *reconnector) replay(ctx context.Context) (int, error) {
+	replayed := 0
+	for {
+		r.mu.Lock()
+		if len(r.buffer) == 0 {
+			r.down = false
+			r.cancel = nil
+			r.mu.Unlock()
+			return replayed, nil
+		}
+		batch := r.buffer
+		r.buffer = nil
+		r.mu.Unlock()
+
+		if err := r.publish(ctx, batch); err != nil {
+			var batchErr *BatchError
+			if errors.As(err, &batchErr) {
+				replayed += batchErr.Published
+				batch = batch[batchErr.Published:]
+			}
+			r.mu.Lock()
+			r.buffer = append(batch, r.buffer...)
+			r.mu.Unlock()
+			return replayed, err
+		}
+		replayed += len(batch)
+	}
+}
+
+// close stops reconnecting and reports trades still buffered as dropped
+func (r *reconnector) close() {
+	r.mu.Lock()
+	cancel, stopped := r.cancel, r.stopped
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-stopped
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n := len(r.buffer); n > 0 {
+		slog.Warn("buffered trades dropped at shutdown", "trades", n,
+			logging.Text("⚠️  Warning: connection still down at shutdown, %d buffered trades dropped", n))
+		if r.onDrop != nil {
+			r.onDrop(n)
+		}
+		r.buffer = nil
+	}
+}
+
+// isConnectionError reports whether err means the connection itself failed,
+// as opposed to the server rejecting a command or the caller giving up
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
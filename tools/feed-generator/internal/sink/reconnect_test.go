@@ -0,0 +1,101 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// fakeConn is a connection that refuses everything until it is brought back
+type fakeConn struct {
+	mu        sync.Mutex
+	down      bool
+	published []*feed.Trade
+}
+
+func (c *fakeConn) publish(ctx context.Context, trades []*feed.Trade) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return fmt.Errorf("dial tcp: %w", syscall.ECONNREFUSED)
+	}
+	c.published = append(c.published, trades...)
+	return nil
+}
+
+func (c *fakeConn) ping(ctx context.Context) error {
+	return c.publish(ctx, nil)
+}
+
+func (c *fakeConn) setDown(down bool) {
+	c.mu.Lock()
+	c.down = down
+	c.mu.Unlock()
+}
+
+func (c *fakeConn) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.published)
+}
+
+func TestReconnectorResumesAfterOutage(t *testing.T) {
+	conn := &fakeConn{}
+	r := newReconnector(conn.publish, conn.ping, 100)
+	dropped := 0
+	r.onDrop = func(n int) { dropped += n }
+	defer r.close()
+	ctx := context.Background()
+	trades := testTrades(30)
+
+	if err := r.publishBatch(ctx, trades[:10]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trades published during the outage are buffered, not failed
+	conn.setDown(true)
+	for _, batch := range [][]*feed.Trade{trades[10:15], trades[15:20]} {
+		if err := r.publishBatch(ctx, batch); err != nil {
+			t.Fatalf("publish during outage: %v", err)
+		}
+	}
+	if n := conn.count(); n != 10 {
+		t.Fatalf("%d trades reached the connection while it was down, want 10", n)
+	}
+
+	conn.setDown(false)
+	reconnecting := func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.down
+	}
+	for deadline := time.Now().Add(2 * time.Second); reconnecting(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("never reconnected, %d of 20 trades published", conn.count())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Once reconnected, publishing goes straight to the connection again
+	if err := r.publishBatch(ctx, trades[20:]); err != nil {
+		t.Fatal(err)
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if len(conn.published) != len(trades) {
+		t.Fatalf("published %d trades, want %d", len(conn.published), len(trades))
+	}
+	for i, trade := range conn.published {
+		if trade.ID != trades[i].ID {
+			t.Fatalf("trade %d published out of order", i)
+		}
+	}
+	if dropped != 0 {
+		t.Errorf("%d trades dropped within the buffer limit", dropped)
+	}
+}
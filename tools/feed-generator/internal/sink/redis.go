@@ -0,0 +1,292 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/redis/go-redis/v9"
+)
+
+// LabelStream holds ground-truth labels, kept apart so the worker never sees them
+const LabelStream = "trades:labels"
+
+// HaltStream holds trading halt markers
+const HaltStream = "trades:halts"
+
+// Trade stream payload formats
+const (
+	PayloadFields   = "fields"   // One stream field per trade attribute, plus trade_data JSON
+	PayloadJSON     = "json"     // The trade as JSON in a single data field
+	PayloadProtobuf = "protobuf" // The trade as a trade.proto message in a single data field
+)
+
+// RedisSink publishes generated trades to a Redis stream
+type RedisSink struct {
+	client *redis.Client
+	stream string // Trade stream the detection worker consumes
+	maxLen int64  // Approximate trade stream cap, 0 = unbounded
+	format string // Trade payload format, one of the Payload constants
+
+	reconnect *reconnector // nil unless outage buffering is enabled
+}
+
+// NewRedisSink creates a new Redis sink publishing trades to the given stream.
+// A positive maxLen trims the stream to roughly that many entries on each add.
+func NewRedisSink(cfg config.RedisConfig, stream string, maxLen int64) (*RedisSink, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	return &RedisSink{client: client, stream: stream, maxLen: maxLen, format: PayloadFields}, nil
+}
+
+// SetPayloadFormat sets how trades are encoded in trade stream entries:
+// PayloadFields (the default), PayloadJSON or PayloadProtobuf
+func (s *RedisSink) SetPayloadFormat(format string) {
+	s.format = format
+}
+
+// Ping checks connectivity to Redis
+func (s *RedisSink) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
+
+// EnableReconnect keeps publishing through a Redis outage: trades are
+// buffered, up to limit, while the sink re-dials with backoff, and replayed in
+// order once Redis answers again. Call it before publishing.
+func (s *RedisSink) EnableReconnect(limit int) {
+	s.reconnect = newReconnector(s.publishBatch, s.Ping, limit)
+}
+
+// OnDrop registers a callback for trades dropped because the outage buffer
+// was full or Redis was still down at Close
+func (s *RedisSink) OnDrop(fn func(n int)) {
+	if s.reconnect != nil {
+		s.reconnect.mu.Lock()
+		s.reconnect.onDrop = fn
+		s.reconnect.mu.Unlock()
+	}
+}
+
+// Publish appends a trade to the trade stream
+func (s *RedisSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	if s.reconnect != nil {
+		return s.reconnect.publishBatch(ctx, []*feed.Trade{trade})
+	}
+	return s.publishOne(ctx, trade)
+}
+
+// publishOne appends a trade to the trade stream on the current connection
+func (s *RedisSink) publishOne(ctx context.Context, trade *feed.Trade) error {
+	values, err := s.streamValues(trade)
+	if err != nil {
+		return err
+	}
+
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: values,
+	}).Err()
+}
+
+// PublishBatch appends trades to the trade stream in order, pipelining the
+// XADDs into a single round trip. A partial failure is reported as a
+// *BatchError counting the trades added before the first failed XADD.
+func (s *RedisSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	if s.reconnect != nil {
+		return s.reconnect.publishBatch(ctx, trades)
+	}
+	return s.publishBatch(ctx, trades)
+}
+
+// publishBatch pipelines trades to the trade stream on the current connection
+func (s *RedisSink) publishBatch(ctx context.Context, trades []*feed.Trade) error {
+	if len(trades) == 1 {
+		return s.publishOne(ctx, trades[0])
+	}
+
+	pipe := s.client.Pipeline()
+	for _, trade := range trades {
+		values, err := s.streamValues(trade)
+		if err != nil {
+			return err
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: s.stream,
+			MaxLen: s.maxLen,
+			Approx: s.maxLen > 0,
+			Values: values,
+		})
+	}
+
+	cmds, err := pipe.Exec(ctx)
+	if err == nil {
+		return nil
+	}
+	for i, cmd := range cmds {
+		if cmd.Err() != nil {
+			return &BatchError{Published: i, Err: cmd.Err()}
+		}
+	}
+	return err
+}
+
+// PublishLabels appends labels to the label stream in one pipelined round trip
+func (s *RedisSink) PublishLabels(ctx context.Context, labels []*feed.Label) error {
+	pipe := s.client.Pipeline()
+	for _, label := range labels {
+		values, err := labelValues(label)
+		if err != nil {
+			return err
+		}
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: LabelStream,
+			Values: values,
+		})
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// PublishHalt appends a halt marker to the halt stream
+func (s *RedisSink) PublishHalt(ctx context.Context, halt *feed.Halt) error {
+	data, err := json.Marshal(halt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal halt: %w", err)
+	}
+
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: HaltStream,
+		Values: map[string]interface{}{
+			"symbol":    halt.Symbol,
+			"status":    string(halt.Status),
+			"price":     halt.Price,
+			"timestamp": halt.Timestamp.Unix(),
+			"halt_data": string(data),
+		},
+	}).Err()
+}
+
+// StreamLength returns the number of entries in the trade stream
+func (s *RedisSink) StreamLength(ctx context.Context) (int64, error) {
+	return s.client.XLen(ctx, s.stream).Result()
+}
+
+// StreamLag returns how many entries of a stream a consumer group has yet to
+// be delivered, from XINFO GROUPS. Redis reports no lag (read here as 0) when
+// it can't determine one, such as after entries were deleted mid-stream.
+func (s *RedisSink) StreamLag(ctx context.Context, stream, group string) (int64, error) {
+	groups, err := s.client.XInfoGroups(ctx, stream).Result()
+	if err != nil {
+		return 0, err
+	}
+	for _, g := range groups {
+		if g.Name == group {
+			return g.Lag, nil
+		}
+	}
+	return 0, fmt.Errorf("consumer group %s not found on %s", group, stream)
+}
+
+// EnsureConsumerGroup creates a consumer group on a stream, creating the
+// stream too if needed. New groups start at the end of the stream, and a group
+// that already exists is left as it is.
+func (s *RedisSink) EnsureConsumerGroup(ctx context.Context, stream, group string) error {
+	err := s.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// Close stops any reconnection in progress and closes the Redis connection
+func (s *RedisSink) Close() error {
+	if s.reconnect != nil {
+		s.reconnect.close()
+	}
+	return s.client.Close()
+}
+
+// streamValues builds the XADD field set for a trade in the sink's payload
+// format
+func (s *RedisSink) streamValues(trade *feed.Trade) (map[string]interface{}, error) {
+	if s.format == PayloadProtobuf {
+		return map[string]interface{}{"data": trade.MarshalProto()}, nil
+	}
+
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal trade: %w", err)
+	}
+	if s.format == PayloadJSON {
+		return map[string]interface{}{"data": string(data)}, nil
+	}
+
+	values := map[string]interface{}{
+		"trade_id":   trade.ID.String(),
+		"user_id":    trade.UserID,
+		"symbol":     trade.Symbol,
+		"amount":     trade.Amount,
+		"price":      trade.Price,
+		"trade_type": string(trade.Type),
+		"timestamp":  trade.Timestamp.Unix(),
+		"trade_data": string(data),
+	}
+
+	if trade.Liquidity != "" {
+		values["liquidity"] = string(trade.Liquidity)
+	}
+
+	if trade.TraderType != "" {
+		values["trader_type"] = trade.TraderType
+	}
+
+	if trade.Sector != "" {
+		values["sector"] = trade.Sector
+		values["asset_class"] = trade.AssetClass
+	}
+
+	if trade.Cancelled {
+		values["cancelled"] = "true"
+	}
+
+	if trade.Seq != 0 {
+		values["seq"] = trade.Seq
+	}
+
+	if len(trade.Conditions) > 0 {
+		values["conditions"] = joinConditions(trade.Conditions)
+	}
+
+	return values, nil
+}
+
+// labelValues builds the XADD field set for a label
+func labelValues(label *feed.Label) (map[string]interface{}, error) {
+	data, err := json.Marshal(label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal label: %w", err)
+	}
+
+	tradeIDs := make([]string, len(label.TradeIDs))
+	for i, id := range label.TradeIDs {
+		tradeIDs[i] = id.String()
+	}
+
+	return map[string]interface{}{
+		"fraud_type":  label.FraudType,
+		"user_id":     label.UserID,
+		"trade_ids":   strings.Join(tradeIDs, ","),
+		"injected_at": label.InjectedAt.Unix(),
+		"label_data":  string(data),
+	}, nil
+}
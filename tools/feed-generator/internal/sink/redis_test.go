@@ -0,0 +1,141 @@
+package sink
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gauravdhanuka4/trade-detection-system/internal/models"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/config"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// testTrades returns n distinct trades a second apart
+func testTrades(n int) []*feed.Trade {
+	trades := make([]*feed.Trade, n)
+	for i := range trades {
+		trades[i] = &feed.Trade{Trade: &models.Trade{
+			ID:        uuid.New(),
+			UserID:    "USER_" + strconv.Itoa(i),
+			Symbol:    "AAPL",
+			Amount:    100,
+			Price:     175.5,
+			Type:      models.TradeTypeBuy,
+			Timestamp: time.Date(2026, 3, 2, 14, 0, i, 0, time.UTC),
+		}}
+	}
+	return trades
+}
+
+// newTestRedisSink returns a sink publishing to stream on server
+func newTestRedisSink(t *testing.T, server *miniredis.Miniredis, stream string, maxLen int64) *RedisSink {
+	t.Helper()
+	port, err := strconv.Atoi(server.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewRedisSink(config.RedisConfig{Host: server.Host(), Port: port}, stream, maxLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestRedisSinksWithDifferentStreamsDontCrossPublish(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+	first := newTestRedisSink(t, server, "trades:first", 0)
+	second := newTestRedisSink(t, server, "trades:second", 0)
+
+	if err := first.PublishBatch(ctx, testTrades(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Publish(ctx, testTrades(1)[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	for stream, want := range map[string]int{"trades:first": 3, "trades:second": 1} {
+		entries, err := server.Stream(stream)
+		if err != nil {
+			t.Fatalf("%s: %v", stream, err)
+		}
+		if len(entries) != want {
+			t.Errorf("%s: got %d entries, want %d", stream, len(entries), want)
+		}
+	}
+	if server.Exists("trades:stream") {
+		t.Error("a configured stream name still published to the default stream")
+	}
+}
+
+func TestStreamMaxLenBoundsTheStream(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+	capped := newTestRedisSink(t, server, "trades:capped", 100)
+	unbounded := newTestRedisSink(t, server, "trades:unbounded", 0)
+
+	for i := 0; i < 10; i++ {
+		trades := testTrades(50)
+		if err := capped.PublishBatch(ctx, trades); err != nil {
+			t.Fatal(err)
+		}
+		if err := unbounded.PublishBatch(ctx, trades); err != nil {
+			t.Fatal(err)
+		}
+
+		// Approximate trimming may overshoot the cap a little, never drift past it
+		length, err := capped.StreamLength(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := min(int64(50*(i+1)), 100); length < want || length > 110 {
+			t.Fatalf("after %d trades the capped stream holds %d, want about %d", 50*(i+1), length, want)
+		}
+	}
+
+	if length, _ := unbounded.StreamLength(ctx); length != 500 {
+		t.Errorf("unbounded stream holds %d, want all 500", length)
+	}
+}
+
+func TestEnsureConsumerGroupIsIdempotent(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+	s := newTestRedisSink(t, server, "trades:stream", 0)
+
+	// The stream doesn't exist yet, so the first call creates it too
+	for i := 0; i < 2; i++ {
+		if err := s.EnsureConsumerGroup(ctx, "trades:stream", "detectors"); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+
+	groups, err := s.client.XInfoGroups(ctx, "trades:stream").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || groups[0].Name != "detectors" {
+		t.Fatalf("got groups %+v, want only detectors", groups)
+	}
+
+	// Trades published after startup are delivered to the group
+	if err := s.PublishBatch(ctx, testTrades(3)); err != nil {
+		t.Fatal(err)
+	}
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    "detectors",
+		Consumer: "test",
+		Streams:  []string{"trades:stream", ">"},
+	}).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 1 || len(streams[0].Messages) != 3 {
+		t.Errorf("group read %v, want the 3 published trades", streams)
+	}
+}
@@ -0,0 +1,80 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// Sink receives generated trades
+type Sink interface {
+	// Publish writes a single trade
+	Publish(ctx context.Context, trade *feed.Trade) error
+	// PublishBatch writes trades in order as one unit
+	PublishBatch(ctx context.Context, trades []*feed.Trade) error
+	// Close flushes buffered output and releases the sink
+	Close() error
+}
+
+// LabelSink receives ground-truth labels for published trades
+type LabelSink interface {
+	PublishLabels(ctx context.Context, labels []*feed.Label) error
+	Close() error
+}
+
+// ConsumerGroupCreator is implemented by sinks whose consumers read through
+// consumer groups, so the generator can create the group before publishing
+type ConsumerGroupCreator interface {
+	EnsureConsumerGroup(ctx context.Context, stream, group string) error
+}
+
+// StreamLengthReader is implemented by sinks that can report how many trades
+// are waiting to be consumed
+type StreamLengthReader interface {
+	StreamLength(ctx context.Context) (int64, error)
+}
+
+// StreamLagReader is implemented by sinks that can report how many entries a
+// consumer group has yet to read
+type StreamLagReader interface {
+	StreamLag(ctx context.Context, stream, group string) (int64, error)
+}
+
+// HaltPublisher is implemented by sinks that can carry trading halt markers
+// alongside the trades
+type HaltPublisher interface {
+	PublishHalt(ctx context.Context, halt *feed.Halt) error
+}
+
+// DropReporter is implemented by sinks that can drop trades after accepting
+// them, such as when an outage buffer overflows, so the generator can count
+// them
+type DropReporter interface {
+	OnDrop(fn func(n int))
+}
+
+// BatchError reports a batch that failed part way. The first Published trades
+// were accepted in order, so a retry resumes from there.
+type BatchError struct {
+	Published int
+	Err       error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("published %d trades before failing: %v", e.Published, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// joinConditions formats trade conditions as a comma-separated list
+func joinConditions(conditions []feed.Condition) string {
+	names := make([]string, len(conditions))
+	for i, c := range conditions {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ",")
+}
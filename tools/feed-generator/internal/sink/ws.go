@@ -0,0 +1,278 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/logging"
+)
+
+// WebSocket protocol constants (RFC 6455)
+const (
+	wsAcceptGUID   = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	wsOpText       = 0x1
+	wsOpClose      = 0x8
+	wsFin          = 0x80
+	wsMaxFrameSize = 1 << 20 // Larger client frames close the connection
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WSSink broadcasts each trade as a JSON text message to every connected
+// WebSocket client. Each client has a bounded send buffer; a client too slow
+// to drain it misses trades instead of holding up generation. Clients only
+// listen: anything they send is read and discarded.
+type WSSink struct {
+	listener net.Listener
+	server   *http.Server
+	buffer   int
+	mu       sync.Mutex
+	clients  map[*wsClient]struct{}
+	dropped  atomic.Int64 // Messages skipped for slow clients
+}
+
+// wsClient is one connected WebSocket client
+type wsClient struct {
+	conn net.Conn
+	send chan []byte
+	once sync.Once
+	done chan struct{}
+}
+
+// NewWSSink starts a WebSocket server on addr, buffering up to buffer
+// messages per client
+func NewWSSink(addr string, buffer int) (*WSSink, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &WSSink{
+		listener: listener,
+		buffer:   buffer,
+		clients:  make(map[*wsClient]struct{}),
+	}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serveWS)}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("serving websocket feed failed", "error", err,
+				logging.Text("Error serving WebSocket feed: %v", err))
+		}
+	}()
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *WSSink) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Clients returns the number of connected clients
+func (s *WSSink) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// Dropped returns how many messages were skipped for slow clients, counting
+// each client that missed a trade
+func (s *WSSink) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+func (s *WSSink) Publish(ctx context.Context, trade *feed.Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trade: %w", err)
+	}
+	s.broadcast(data)
+	return nil
+}
+
+func (s *WSSink) PublishBatch(ctx context.Context, trades []*feed.Trade) error {
+	for i, trade := range trades {
+		if err := s.Publish(ctx, trade); err != nil {
+			return &BatchError{Published: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// broadcast queues a message for every client, skipping clients whose
+// buffer is full
+func (s *WSSink) broadcast(data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		select {
+		case client.send <- data:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Close disconnects every client and stops the server
+func (s *WSSink) Close() error {
+	err := s.server.Close()
+
+	s.mu.Lock()
+	for client := range s.clients {
+		client.close()
+	}
+	s.mu.Unlock()
+
+	if n := s.dropped.Load(); n > 0 {
+		slog.Warn("websocket messages dropped for slow clients", "messages", n,
+			logging.Text("⚠️  Warning: %d WebSocket messages dropped for slow clients", n))
+	}
+	return err
+}
+
+// serveWS upgrades a request to a WebSocket connection and streams trades to
+// it until either side closes
+func (s *WSSink) serveWS(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		http.Error(w, "expected a WebSocket upgrade", http.StatusBadRequest)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection cannot be upgraded", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAccept(key))
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+
+	client := &wsClient{conn: conn, send: make(chan []byte, s.buffer), done: make(chan struct{})}
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+
+	go client.readLoop(rw.Reader)
+	client.writeLoop()
+
+	s.mu.Lock()
+	delete(s.clients, client)
+	s.mu.Unlock()
+}
+
+// writeLoop sends queued messages until the client disconnects
+func (c *wsClient) writeLoop() {
+	defer c.close()
+	for {
+		select {
+		case <-c.done:
+			return
+		case data := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if _, err := c.conn.Write(wsFrame(wsOpText, data)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readLoop discards client frames, closing the connection when the client
+// sends a close frame or the connection fails
+func (c *wsClient) readLoop(r *bufio.Reader) {
+	defer c.close()
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header[:2]); err != nil {
+			return
+		}
+		opcode := header[0] & 0x0f
+		length := uint64(header[1] & 0x7f)
+		switch length {
+		case 126:
+			if _, err := io.ReadFull(r, header[:2]); err != nil {
+				return
+			}
+			length = uint64(binary.BigEndian.Uint16(header[:2]))
+		case 127:
+			if _, err := io.ReadFull(r, header[:8]); err != nil {
+				return
+			}
+			length = binary.BigEndian.Uint64(header[:8])
+		}
+		if header[1]&0x80 != 0 {
+			length += 4 // Masking key
+		}
+		if opcode == wsOpClose || length > wsMaxFrameSize {
+			c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			c.conn.Write(wsFrame(wsOpClose, nil))
+			return
+		}
+		if _, err := io.CopyN(io.Discard, r, int64(length)); err != nil {
+			return
+		}
+	}
+}
+
+// close ends the client's connection, once
+func (c *wsClient) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// wsFrame encodes an unmasked, unfragmented server frame
+func wsFrame(opcode byte, payload []byte) []byte {
+	frame := []byte{wsFin | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	return append(frame, payload...)
+}
+
+// wsAccept derives the Sec-WebSocket-Accept value for a client key
+func wsAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// headerContains reports whether a comma-separated header lists token,
+// ignoring case
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gauravdhanuka4/trade-detection-system/tools/feed-generator/internal/feed"
+)
+
+// newTestWSSink serves a WebSocket sink from an httptest server
+func newTestWSSink(t *testing.T, buffer int) *WSSink {
+	t.Helper()
+	s := &WSSink{buffer: buffer, clients: make(map[*wsClient]struct{})}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(s.serveWS))
+	s.listener = server.Listener
+	s.server = server.Config
+	server.Start()
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// testWSClient is a minimal WebSocket client reading server text frames
+type testWSClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWS connects a client to s and waits until the sink has registered it
+func dialWS(t *testing.T, s *WSSink) *testWSClient {
+	t.Helper()
+	registered := s.Clients()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	req, err := http.NewRequest(http.MethodGet, "http://"+s.Addr().String()+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	for deadline := time.Now().Add(time.Second); s.Clients() == registered; {
+		if time.Now().After(deadline) {
+			t.Fatal("sink never registered the client")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return &testWSClient{conn: conn, reader: reader}
+}
+
+// readMessage returns the payload of the next text frame
+func (c *testWSClient) readMessage(t *testing.T) []byte {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(c.reader, header[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if opcode := header[0] & 0x0f; opcode != wsOpText {
+		t.Fatalf("got opcode %#x, want a text frame", opcode)
+	}
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		if _, err := io.ReadFull(c.reader, header[:2]); err != nil {
+			t.Fatal(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(header[:2]))
+	case 127:
+		if _, err := io.ReadFull(c.reader, header[:8]); err != nil {
+			t.Fatal(err)
+		}
+		length = binary.BigEndian.Uint64(header[:8])
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestWSSlowClientGetsEveryTradeWithinItsBuffer(t *testing.T) {
+	s := newTestWSSink(t, 64)
+	client := dialWS(t, s)
+	ctx := context.Background()
+
+	// The client reads nothing while a buffer's worth of trades is published
+	trades := testTrades(64)
+	if err := s.PublishBatch(ctx, trades); err != nil {
+		t.Fatal(err)
+	}
+
+	// A client chatting back mid-stream is discarded, not disconnected
+	frame := []byte{wsFin | wsOpText, 0x80 | 5, 0, 0, 0, 0}
+	if _, err := client.conn.Write(append(frame, "hello"...)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, trade := range trades {
+		want, err := json.Marshal(trade)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := client.readMessage(t); string(got) != string(want) {
+			t.Fatalf("message %d: got %s, want %s", i, got, want)
+		}
+		time.Sleep(time.Millisecond) // Drain slowly
+	}
+	if n := s.Dropped(); n != 0 {
+		t.Errorf("%d messages dropped for a client within its buffer", n)
+	}
+	if n := s.Clients(); n != 1 {
+		t.Errorf("got %d clients, want the slow client still connected", n)
+	}
+}
+
+func TestWSClientsReceivePublishedTrades(t *testing.T) {
+	s, err := NewWSSink("127.0.0.1:0", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	clients := []*testWSClient{dialWS(t, s), dialWS(t, s)}
+	ctx := context.Background()
+
+	trades := testTrades(5)
+	if err := s.Publish(ctx, trades[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PublishBatch(ctx, trades[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every client gets every trade, in publishing order
+	for c, client := range clients {
+		for i, trade := range trades {
+			var got feed.Trade
+			if err := json.Unmarshal(client.readMessage(t), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != trade.ID || got.UserID != trade.UserID || got.Amount != trade.Amount || !got.Timestamp.Equal(trade.Timestamp) {
+				t.Errorf("client %d message %d: got %+v, want %+v", c+1, i, *got.Trade, *trade.Trade)
+			}
+		}
+	}
+}